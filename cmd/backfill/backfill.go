@@ -0,0 +1,66 @@
+package backfill
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strings"
+
+	"github.com/milad-rasouli/price/cmd/internal/cliflag"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+type Options struct {
+	Symbols []string
+	From    int64
+	To      int64
+	// Rate caps provider calls per minute, so a backfill can leave room in the
+	// plan for the ingestion running next to it. 0 keeps the configured limit.
+	Rate int
+}
+
+func Parse(args []string) (*Options, error) {
+	fs := flag.NewFlagSet("backfill", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price backfill -symbols btc,eth [-from T] [-to T]")
+		fmt.Fprintln(fs.Output(), "Loads past prices from the provider, skipping rows that are already stored.")
+		fs.PrintDefaults()
+	}
+	symbols := fs.String("symbols", "", "comma separated symbols to backfill (required)")
+	var from, to cliflag.Time
+	fs.Var(&from, "from", "start time, unix seconds or RFC 3339 (default to - 24h)")
+	fs.Var(&to, "to", "end time, unix seconds or RFC 3339 (default now)")
+	rate := fs.Int("rate", 0, "provider calls per minute for this backfill (default the configured rate limit)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *symbols == "" {
+		fs.Usage()
+		return nil, fmt.Errorf("-symbols is required")
+	}
+	if *rate < 0 {
+		return nil, fmt.Errorf("invalid rate %d", *rate)
+	}
+
+	opts := &Options{Rate: *rate}
+	for _, s := range strings.Split(*symbols, ",") {
+		opts.Symbols = append(opts.Symbols, strings.ToLower(strings.TrimSpace(s)))
+	}
+	opts.From, opts.To = cliflag.Range(&from, &to)
+	return opts, nil
+}
+
+func Run(ctx context.Context, svc service.PriceService, logger *slog.Logger, opts *Options) error {
+	lg := logger.With("method", "backfill.Run")
+
+	for _, symbol := range opts.Symbols {
+		n, err := svc.Backfill(ctx, symbol, opts.From, opts.To)
+		if err != nil {
+			return fmt.Errorf("failed to backfill %s: %w", symbol, err)
+		}
+		lg.Info("backfilled symbol", "symbol", symbol, "inserted", n)
+		fmt.Printf("%s: %d rows inserted\n", symbol, n)
+	}
+	return nil
+}
@@ -9,7 +9,6 @@ import (
 	"os"
 	"os/signal"
 	"syscall"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	swaggerfiles "github.com/swaggo/files"
@@ -17,23 +16,30 @@ import (
 
 	_ "github.com/milad-rasouli/price/docs"
 	"github.com/milad-rasouli/price/internal/app/api/routes"
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/service"
 )
 
 type Boot struct {
-	env    *godotenv.Env
+	cfg    *config.Config
+	store  *config.Store
 	logger *slog.Logger
+	alerts service.AlertService
 	rts    []routes.Router
 }
 
 func NewBoot(
-	env *godotenv.Env,
+	cfg *config.Config,
+	store *config.Store,
 	logger *slog.Logger,
+	alerts service.AlertService,
 	rts ...routes.Router,
 ) *Boot {
 	return &Boot{
-		env:    env,
+		cfg:    cfg,
+		store:  store,
 		logger: logger.With("layer", "boot"),
+		alerts: alerts,
 		rts:    rts,
 	}
 }
@@ -47,12 +53,23 @@ func (b *Boot) Boot() error {
 
 	r.GET("/swagger/*any", ginSwagger.WrapHandler(swaggerfiles.Handler))
 
-	addr := ":" + b.env.HTTPPort
+	addr := ":" + b.cfg.HTTP.Port
 	srv := &http.Server{
-		Addr:    addr,
-		Handler: r,
+		Addr:        addr,
+		Handler:     r,
+		ReadTimeout: b.cfg.HTTP.ReadTimeout.Duration,
 	}
 
+	// alert deliveries run beside the server and stop after it, so events
+	// queued by the last requests are still attempted
+	deliveriesCtx, stopDeliveries := context.WithCancel(context.Background())
+	defer stopDeliveries()
+	deliveriesDone := make(chan struct{})
+	go func() {
+		defer close(deliveriesDone)
+		b.alerts.RunDeliveries(deliveriesCtx)
+	}()
+
 	serverErr := make(chan error, 1)
 	go func() {
 		b.logger.Info("starting HTTP server", "addr", addr)
@@ -63,15 +80,24 @@ func (b *Boot) Boot() error {
 
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, os.Interrupt, syscall.SIGTERM)
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	defer signal.Stop(hup)
 
-	select {
-	case err := <-serverErr:
-		return err
-	case sig := <-quit:
-		b.logger.Info("received shutdown signal", "signal", sig.String())
+wait:
+	for {
+		select {
+		case err := <-serverErr:
+			return err
+		case <-hup:
+			b.reload()
+		case sig := <-quit:
+			b.logger.Info("received shutdown signal", "signal", sig.String())
+			break wait
+		}
 	}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	ctx, cancel := context.WithTimeout(context.Background(), b.cfg.HTTP.ShutdownTimeout.Duration)
 	defer cancel()
 
 	if err := srv.Shutdown(ctx); err != nil {
@@ -79,6 +105,32 @@ func (b *Boot) Boot() error {
 		return fmt.Errorf("server forced to shutdown: %w", err)
 	}
 
+	stopDeliveries()
+	select {
+	case <-deliveriesDone:
+	case <-ctx.Done():
+		// the unfinished attempt is retried once its lease expires
+		b.logger.Warn("alert delivery still running at shutdown")
+	}
+
 	b.logger.Info("server exited cleanly")
 	return nil
 }
+
+// reload applies the runtime settings from the config file and environment,
+// keeping the current configuration when the new one is invalid
+func (b *Boot) reload() {
+	cfg, restart, err := b.store.Reload()
+	if err != nil {
+		b.logger.Error("failed to reload configuration, keeping the current one", "error", err)
+		return
+	}
+	b.logger.Info("configuration reloaded",
+		"symbols", cfg.Ingest.Symbols,
+		"top", cfg.Ingest.Top,
+		"providers", cfg.Providers.Priority,
+	)
+	if len(restart) > 0 {
+		b.logger.Warn("changed settings need a restart to take effect", "settings", restart)
+	}
+}
@@ -0,0 +1,158 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"strings"
+	"syscall"
+
+	"github.com/milad-rasouli/price/cmd/backfill"
+	"github.com/milad-rasouli/price/cmd/cron"
+	"github.com/milad-rasouli/price/cmd/export"
+	"github.com/milad-rasouli/price/cmd/migrate"
+	"github.com/milad-rasouli/price/cmd/query"
+	"github.com/milad-rasouli/price/cmd/receiver"
+	"github.com/milad-rasouli/price/cmd/seed"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+)
+
+type command struct {
+	name    string
+	summary string
+	run     func(cfg *config.Config, logger *slog.Logger, args []string) error
+}
+
+var commands = []command{
+	{
+		name:    "serve",
+		summary: "run the HTTP API (default)",
+		run:     runServe,
+	},
+	{
+		name:    "cron",
+		summary: "trigger price ingestion on the API every interval",
+		run:     cron.Run,
+	},
+	{
+		name:    "config",
+		summary: "print the effective configuration with secrets redacted",
+		run:     runConfig,
+	},
+	{
+		name:    "migrate",
+		summary: "apply or inspect the embedded database migrations",
+		run:     migrate.Run,
+	},
+	{
+		name:    "backfill",
+		summary: "load past prices from the provider",
+		run: func(cfg *config.Config, logger *slog.Logger, args []string) error {
+			opts, err := backfill.Parse(args)
+			if err != nil {
+				return err
+			}
+			if opts.Rate > 0 {
+				cfg.Providers.CoinGecko.RateLimit.RequestsPerMinute = opts.Rate
+			}
+			return withServices(cfg, logger, func(ctx context.Context, s *Services) error {
+				return backfill.Run(ctx, s.Price, logger, opts)
+			})
+		},
+	},
+	{
+		name:    "seed",
+		summary: "fill the database with synthetic prices",
+		run: func(cfg *config.Config, logger *slog.Logger, args []string) error {
+			opts, err := seed.Parse(args)
+			if err != nil {
+				return err
+			}
+			return withServices(cfg, logger, func(ctx context.Context, s *Services) error {
+				return seed.Run(ctx, s.Prices, logger, opts)
+			})
+		},
+	},
+	{
+		name:    "export",
+		summary: "write history or raw ticks as CSV or NDJSON",
+		run: func(cfg *config.Config, logger *slog.Logger, args []string) error {
+			opts, err := export.Parse(args)
+			if err != nil {
+				return err
+			}
+			return withServices(cfg, logger, func(ctx context.Context, s *Services) error {
+				return export.Run(ctx, s.Price, opts)
+			})
+		},
+	},
+	{
+		name:    "query",
+		summary: "print the latest price or history of a symbol as JSON",
+		run: func(cfg *config.Config, logger *slog.Logger, args []string) error {
+			opts, err := query.Parse(args)
+			if err != nil {
+				return err
+			}
+			return withServices(cfg, logger, func(ctx context.Context, s *Services) error {
+				return query.Run(ctx, s.Price, opts, os.Stdout)
+			})
+		},
+	},
+	{
+		name:    "receiver",
+		summary: "receive and verify alert webhooks locally",
+		run: func(cfg *config.Config, logger *slog.Logger, args []string) error {
+			opts, err := receiver.Parse(args, cfg.Alerts.SigningSecret)
+			if err != nil {
+				return err
+			}
+			ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+			defer stop()
+			return receiver.Run(ctx, logger, opts, os.Stdout)
+		},
+	},
+}
+
+// run dispatches to the named subcommand; without one the API is served
+func run(cfg *config.Config, logger *slog.Logger, args []string) error {
+	name := "serve"
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		name, args = args[0], args[1:]
+	}
+
+	switch {
+	case len(args) > 0 && name == "serve" && (args[0] == "-cron" || args[0] == "--cron"):
+		logger.Warn("--cron is deprecated, use the cron subcommand")
+		name, args = "cron", args[1:]
+	case name == "help" || (len(args) > 0 && name == "serve" && (args[0] == "-h" || args[0] == "--help")):
+		usage()
+		return nil
+	}
+
+	// config has to work on an invalid configuration to help fixing it
+	if name != "config" {
+		if err := cfg.Validate(); err != nil {
+			return fmt.Errorf("invalid configuration:\n%w", err)
+		}
+	}
+
+	for _, c := range commands {
+		if c.name == name {
+			return c.run(cfg, logger, args)
+		}
+	}
+	usage()
+	return fmt.Errorf("unknown command %q", name)
+}
+
+func usage() {
+	fmt.Fprintln(os.Stderr, "usage: price <command> [flags]")
+	fmt.Fprintln(os.Stderr, "\ncommands:")
+	for _, c := range commands {
+		fmt.Fprintf(os.Stderr, "  %-10s %s\n", c.name, c.summary)
+	}
+	fmt.Fprintln(os.Stderr, "\nRun 'price <command> -h' for the flags of a command.")
+}
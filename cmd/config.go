@@ -0,0 +1,36 @@
+package main
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"os"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+)
+
+func runConfig(cfg *config.Config, logger *slog.Logger, args []string) error {
+	if len(args) == 0 || args[0] != "print" {
+		fmt.Fprintln(os.Stderr, "usage: price config print")
+		fmt.Fprintln(os.Stderr, "Prints the configuration after applying the config file and environment, with secrets redacted.")
+		if len(args) > 0 && (args[0] == "-h" || args[0] == "--help") {
+			return flag.ErrHelp
+		}
+		return errors.New("unknown config command")
+	}
+
+	out, err := cfg.Redacted().YAML()
+	if err != nil {
+		return err
+	}
+	if cfg.File != "" {
+		fmt.Printf("# loaded from %s\n", cfg.File)
+	}
+	fmt.Print(string(out))
+
+	if err := cfg.Validate(); err != nil {
+		return fmt.Errorf("invalid configuration:\n%w", err)
+	}
+	return nil
+}
@@ -2,6 +2,7 @@ package cron
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log/slog"
 	"net/http"
@@ -10,27 +11,50 @@ import (
 	"syscall"
 	"time"
 
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 )
 
-func Run(env *godotenv.Env, logger *slog.Logger) error {
+func Run(cfg *config.Config, logger *slog.Logger, args []string) error {
 	lg := logger.With("method", "cron.Run")
 
-	interval := time.Duration(env.ReadCoinInterval) * time.Second
+	fs := flag.NewFlagSet("cron", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price cron [flags]")
+		fmt.Fprintln(fs.Output(), "Triggers /cron/update-prices on the API every interval.")
+		fs.PrintDefaults()
+	}
+	interval := fs.Duration("interval", cfg.Ingest.Interval.Duration, "time between triggers, fixed: ignores the API's interval")
+	addr := fs.String("api", "http://localhost:"+cfg.HTTP.Port, "base URL of the API to trigger")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if *interval <= 0 {
+		return fmt.Errorf("invalid interval %s", *interval)
+	}
+	fixed := false
+	fs.Visit(func(f *flag.Flag) { fixed = fixed || f.Name == "interval" })
 
-	url := fmt.Sprintf("http://localhost:%s/cron/update-prices", env.HTTPPort)
-	ticker := time.NewTicker(interval)
+	url := *addr + "/cron/update-prices"
+	ticker := time.NewTicker(*interval)
 	defer ticker.Stop()
 
 	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
 	defer stop()
 
-	lg.Info("cron started", "interval", interval, "url", url)
+	lg.Info("cron started", "interval", *interval, "url", url)
 
 	for {
 		select {
 		case <-ticker.C:
-			callUpdatePrices(lg, url)
+			// the API owns the configuration, a reload there reaches the schedule with its next response
+			next := callUpdatePrices(lg, url)
+			if fixed || next <= 0 || next == *interval {
+				continue
+			}
+			*interval = next
+			ticker.Reset(*interval)
+			lg.Info("interval changed by the API", "interval", *interval)
 
 		case <-ctx.Done():
 			lg.Info("cron shutting down gracefully...")
@@ -39,11 +63,13 @@ func Run(env *godotenv.Env, logger *slog.Logger) error {
 	}
 }
 
-func callUpdatePrices(lg *slog.Logger, url string) {
+// callUpdatePrices triggers one ingestion tick and returns the interval the
+// API reported, 0 when it reported none
+func callUpdatePrices(lg *slog.Logger, url string) time.Duration {
 	resp, err := http.Get(url)
 	if err != nil {
 		lg.Error("failed to call update-prices", "error", err)
-		return
+		return 0
 	}
 	defer func() {
 		if cerr := resp.Body.Close(); cerr != nil {
@@ -59,4 +85,10 @@ func callUpdatePrices(lg *slog.Logger, url string) {
 			"code", resp.StatusCode,
 		)
 	}
+
+	interval, err := time.ParseDuration(resp.Header.Get(controller.IntervalHeader))
+	if err != nil {
+		return 0
+	}
+	return interval
 }
@@ -0,0 +1,42 @@
+package cron
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+func TestCallUpdatePrices(t *testing.T) {
+	tests := []struct {
+		name   string
+		status int
+		header string
+		want   time.Duration
+	}{
+		{"interval reported", http.StatusCreated, "30s", 30 * time.Second},
+		{"reported on a failed tick", http.StatusTooManyRequests, "2m0s", 2 * time.Minute},
+		{"not reported", http.StatusCreated, "", 0},
+		{"malformed", http.StatusCreated, "soon", 0},
+	}
+	lg := slog.New(slog.NewTextHandler(io.Discard, nil))
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				if tt.header != "" {
+					w.Header().Set(controller.IntervalHeader, tt.header)
+				}
+				w.WriteHeader(tt.status)
+			}))
+			defer srv.Close()
+
+			if got := callUpdatePrices(lg, srv.URL); got != tt.want {
+				t.Errorf("callUpdatePrices() = %s, want %s", got, tt.want)
+			}
+		})
+	}
+}
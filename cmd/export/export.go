@@ -0,0 +1,135 @@
+package export
+
+import (
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+
+	"github.com/milad-rasouli/price/cmd/internal/cliflag"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+const (
+	FormatCSV    = "csv"
+	FormatNDJSON = "ndjson"
+)
+
+type Options struct {
+	Symbol   string
+	Interval string
+	From     int64
+	To       int64
+	Format   string
+	Raw      bool
+	Output   string
+}
+
+func Parse(args []string) (*Options, error) {
+	fs := flag.NewFlagSet("export", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price export -symbol btc [-raw] [-interval 1h] [-from T] [-to T] [-format csv|ndjson] [-o file]")
+		fmt.Fprintln(fs.Output(), "Streams bucketed history, or raw ticks with -raw, to a file or stdout.")
+		fs.PrintDefaults()
+	}
+	opts := &Options{}
+	fs.StringVar(&opts.Symbol, "symbol", "", "symbol to export (required)")
+	fs.StringVar(&opts.Interval, "interval", "", "bucket size for history, e.g. 1m, 1h, 1d (default 1h)")
+	fs.StringVar(&opts.Format, "format", FormatCSV, "csv or ndjson")
+	fs.BoolVar(&opts.Raw, "raw", false, "export raw ticks instead of bucketed history")
+	fs.StringVar(&opts.Output, "o", "", "output file (default stdout)")
+	var from, to cliflag.Time
+	fs.Var(&from, "from", "start time, unix seconds or RFC 3339 (default to - 24h)")
+	fs.Var(&to, "to", "end time, unix seconds or RFC 3339 (default now)")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.Symbol == "" {
+		fs.Usage()
+		return nil, fmt.Errorf("-symbol is required")
+	}
+	if opts.Format != FormatCSV && opts.Format != FormatNDJSON {
+		return nil, fmt.Errorf("unknown format %q", opts.Format)
+	}
+	opts.From, opts.To = cliflag.Range(&from, &to)
+	return opts, nil
+}
+
+func Run(ctx context.Context, svc service.PriceService, opts *Options) (err error) {
+	var out io.Writer = os.Stdout
+	if opts.Output != "" {
+		f, err := os.Create(opts.Output)
+		if err != nil {
+			return err
+		}
+		defer func() {
+			if cerr := f.Close(); err == nil {
+				err = cerr
+			}
+		}()
+		out = f
+	}
+
+	w := newWriter(out, opts.Format)
+	if opts.Raw {
+		w.header(dto.TickCSVHeader)
+		err = svc.StreamTicks(ctx, &dto.TicksReq{Symbol: opts.Symbol, From: opts.From, To: opts.To}, func(t *dto.TickRes) error {
+			return w.write(t)
+		})
+	} else {
+		w.header(dto.HistoryCSVHeader)
+		req := &dto.HistoryReq{Symbol: opts.Symbol, Interval: opts.Interval, From: opts.From, To: opts.To}
+		err = svc.StreamHistory(ctx, req, func(h *dto.HistoryRes) error {
+			return w.write(h)
+		})
+	}
+	if err != nil {
+		return err
+	}
+	return w.flush()
+}
+
+type record interface {
+	CSVRecord() []string
+}
+
+type writer struct {
+	csv  *csv.Writer
+	json *json.Encoder
+	err  error
+}
+
+func newWriter(out io.Writer, format string) *writer {
+	if format == FormatCSV {
+		return &writer{csv: csv.NewWriter(out)}
+	}
+	return &writer{json: json.NewEncoder(out)}
+}
+
+func (w *writer) header(cols []string) {
+	if w.csv != nil {
+		w.err = w.csv.Write(cols)
+	}
+}
+
+func (w *writer) write(r record) error {
+	if w.err != nil {
+		return w.err
+	}
+	if w.csv != nil {
+		return w.csv.Write(r.CSVRecord())
+	}
+	return w.json.Encode(r)
+}
+
+func (w *writer) flush() error {
+	if w.csv != nil {
+		w.csv.Flush()
+		return w.csv.Error()
+	}
+	return w.err
+}
@@ -0,0 +1,46 @@
+// Package cliflag holds flag types shared by the price subcommands.
+package cliflag
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+)
+
+// Time is a flag.Value accepting unix seconds or an RFC 3339 timestamp
+type Time struct {
+	Unix int64
+}
+
+func (t *Time) String() string {
+	if t == nil || t.Unix == 0 {
+		return ""
+	}
+	return time.Unix(t.Unix, 0).UTC().Format(time.RFC3339)
+}
+
+func (t *Time) Set(s string) error {
+	if unix, err := strconv.ParseInt(s, 10, 64); err == nil {
+		t.Unix = unix
+		return nil
+	}
+	parsed, err := time.Parse(time.RFC3339, s)
+	if err != nil {
+		return fmt.Errorf("expected unix seconds or RFC 3339, got %q", s)
+	}
+	t.Unix = parsed.Unix()
+	return nil
+}
+
+// Range fills in the defaults used by the API: to is now and from is one day before to
+func Range(from, to *Time) (int64, int64) {
+	end := to.Unix
+	if end == 0 {
+		end = time.Now().Unix()
+	}
+	start := from.Unix
+	if start == 0 {
+		start = end - 86400
+	}
+	return start, end
+}
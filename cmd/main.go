@@ -2,70 +2,93 @@ package main
 
 import (
 	"context"
+	"errors"
 	"flag"
-	"github.com/milad-rasouli/price/cmd/cron"
+	"fmt"
 	"log/slog"
 	"os"
-	"time"
 
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
 )
 
 func main() {
-	env := godotenv.NewEnv()
-	logger := initSlogLogger(env)
-	cronflag := flag.Bool("cron", false, "Trigger cron jobs")
-	flag.Parse()
-	if *cronflag {
-		if err := cron.Run(env, logger); err != nil {
-			logger.Error("failed to run cron", "error", err)
-			os.Exit(1)
+	cfg, err := config.Load()
+	if err != nil {
+		fmt.Fprintln(os.Stderr, "invalid configuration:", err)
+		os.Exit(1)
+	}
+	logger := initSlogLogger(cfg)
+
+	if err := run(cfg, logger, os.Args[1:]); err != nil {
+		if errors.Is(err, flag.ErrHelp) {
+			return
 		}
+		logger.Error("command failed", "error", err)
+		os.Exit(1)
 	}
+}
 
-	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+// connect opens the pool and makes sure PostgreSQL answers
+func connect(cfg *config.Config) (*postgresql.Postgres, error) {
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout.Duration)
 	defer cancel()
 
-	pg := postgresql.NewPostgre(env)
+	pg := postgresql.NewPostgre(cfg)
 	err := pg.Setup(ctx)
 	if err != nil {
-		logger.Error("failed to initialize PostgreSQL Primary", "error", err)
-		os.Exit(1)
+		return nil, err
 	}
-	defer pg.Close()
 
 	err = pg.HealthCheck(ctx)
 	if err != nil {
-		logger.Error("failed to check health", "error", err)
-		os.Exit(1)
+		pg.Close()
+		return nil, err
 	}
-	pool := pg.Pool
+	return pg, nil
+}
 
-	boot, err := wireApp(env, logger, pg, pool)
+func migrateUp(cfg *config.Config, logger *slog.Logger) error {
+	m, err := postgresql.NewMigrator(cfg)
 	if err != nil {
-		logger.Error("failed to setup app", "error", err)
-		os.Exit(1)
+		return err
+	}
+	defer func() {
+		if cerr := m.Close(); cerr != nil {
+			logger.Warn("failed to close migrator", "error", cerr)
+		}
+	}()
+
+	if err := m.Up(); err != nil {
+		return err
 	}
-	err = boot.Boot()
+	status, err := m.Status()
 	if err != nil {
-		logger.Error("failed to start app", "error", err)
-		os.Exit(1)
+		return err
 	}
-
+	logger.Info("database schema is up to date", "version", status.Version)
+	return nil
 }
 
-func initSlogLogger(e *godotenv.Env) *slog.Logger {
+func initSlogLogger(cfg *config.Config) *slog.Logger {
 	logLevel := slog.LevelDebug
-	if e.Environment == "production" {
+	if cfg.Environment == "production" {
 		logLevel = slog.LevelWarn
 	}
+	if cfg.Log.Level != "" {
+		_ = logLevel.UnmarshalText([]byte(cfg.Log.Level)) // validated with the config
+	}
 	//development
 	slogHandlerOptions := &slog.HandlerOptions{
 		AddSource: true,
 		Level:     logLevel,
 	}
-	logger := slog.New(slog.NewTextHandler(os.Stdout, slogHandlerOptions))
+	// stdout is reserved for command output such as exports
+	var handler slog.Handler = slog.NewTextHandler(os.Stderr, slogHandlerOptions)
+	if cfg.Log.Format == "json" {
+		handler = slog.NewJSONHandler(os.Stderr, slogHandlerOptions)
+	}
+	logger := slog.New(handler)
 	slog.SetDefault(logger)
 
 	return logger
@@ -0,0 +1,83 @@
+package migrate
+
+import (
+	"errors"
+	"flag"
+	"fmt"
+	"log/slog"
+	"strconv"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+)
+
+const Usage = "usage: migrate up | down [N] | status | goto V | force V"
+
+// Run executes one migrate command against the embedded migrations
+func Run(cfg *config.Config, logger *slog.Logger, args []string) (err error) {
+	lg := logger.With("method", "migrate.Run")
+
+	if len(args) == 0 {
+		return errors.New(Usage)
+	}
+	if args[0] == "-h" || args[0] == "--help" || args[0] == "help" {
+		fmt.Println(Usage)
+		return flag.ErrHelp
+	}
+
+	m, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		return err
+	}
+	defer func() {
+		if cerr := m.Close(); cerr != nil {
+			lg.Warn("failed to close migrator", "error", cerr)
+		}
+	}()
+
+	switch args[0] {
+	case "up":
+		err = m.Up()
+	case "down":
+		steps := 1
+		if len(args) > 1 {
+			steps, err = strconv.Atoi(args[1])
+			if err != nil || steps < 1 {
+				return fmt.Errorf("invalid number of steps %q", args[1])
+			}
+		}
+		err = m.Down(steps)
+	case "goto":
+		if len(args) < 2 {
+			return errors.New(Usage)
+		}
+		version, perr := strconv.ParseUint(args[1], 10, 64)
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = m.Goto(uint(version))
+	case "force":
+		if len(args) < 2 {
+			return errors.New(Usage)
+		}
+		version, perr := strconv.Atoi(args[1])
+		if perr != nil {
+			return fmt.Errorf("invalid version %q", args[1])
+		}
+		err = m.Force(version)
+	case "status":
+	default:
+		return fmt.Errorf("unknown migrate command %q, %s", args[0], Usage)
+	}
+	if err != nil {
+		return err
+	}
+
+	status, err := m.Status()
+	if err != nil {
+		return err
+	}
+	lg.Info("migration status", "version", status.Version, "dirty", status.Dirty, "latest", status.Latest)
+	fmt.Printf("version: %d\ndirty: %t\nlatest: %d\n", status.Version, status.Dirty, status.Latest)
+	return nil
+}
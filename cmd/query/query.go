@@ -0,0 +1,88 @@
+package query
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+
+	"github.com/milad-rasouli/price/cmd/internal/cliflag"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+const Usage = "usage: price query latest|history -symbol btc [flags]"
+
+type Options struct {
+	Kind    string
+	Latest  *dto.LatestReq
+	History *dto.HistoryReq
+}
+
+func Parse(args []string) (*Options, error) {
+	if len(args) == 0 {
+		return nil, errors.New(Usage)
+	}
+
+	opts := &Options{Kind: args[0]}
+	fs := flag.NewFlagSet("query "+args[0], flag.ContinueOnError)
+	symbol := fs.String("symbol", "", "symbol to query (required)")
+
+	switch opts.Kind {
+	case "latest":
+		fs.Usage = func() {
+			fmt.Fprintln(fs.Output(), "usage: price query latest -symbol btc [-windows 1h,24h,7d,30d,ytd]")
+			fs.PrintDefaults()
+		}
+		windows := fs.String("windows", "", "comma separated change windows, intervals or ytd (default 1h,24h,7d,30d,ytd)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return nil, err
+		}
+		opts.Latest = &dto.LatestReq{Symbol: *symbol, Windows: *windows}
+	case "history":
+		fs.Usage = func() {
+			fmt.Fprintln(fs.Output(), "usage: price query history -symbol btc [-interval 1h] [-from T] [-to T]")
+			fs.PrintDefaults()
+		}
+		interval := fs.String("interval", "", "bucket size, e.g. 1m, 1h, 1d (default 1h)")
+		var from, to cliflag.Time
+		fs.Var(&from, "from", "start time, unix seconds or RFC 3339 (default to - 24h)")
+		fs.Var(&to, "to", "end time, unix seconds or RFC 3339 (default now)")
+		if err := fs.Parse(args[1:]); err != nil {
+			return nil, err
+		}
+		opts.History = &dto.HistoryReq{Symbol: *symbol, Interval: *interval}
+		opts.History.From, opts.History.To = cliflag.Range(&from, &to)
+	default:
+		return nil, fmt.Errorf("unknown query %q, %s", opts.Kind, Usage)
+	}
+
+	if *symbol == "" {
+		fs.Usage()
+		return nil, errors.New("-symbol is required")
+	}
+	return opts, nil
+}
+
+// Run prints the result as indented JSON, the same payload the API returns in data
+func Run(ctx context.Context, svc service.PriceService, opts *Options, out io.Writer) error {
+	var (
+		result any
+		err    error
+	)
+	switch opts.Kind {
+	case "latest":
+		result, err = svc.GetLatest(ctx, opts.Latest)
+	case "history":
+		result, err = svc.GetHistory(ctx, opts.History)
+	}
+	if err != nil {
+		return err
+	}
+
+	enc := json.NewEncoder(out)
+	enc.SetIndent("", "  ")
+	return enc.Encode(result)
+}
@@ -0,0 +1,89 @@
+package receiver
+
+import (
+	"context"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"sync/atomic"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/webhook"
+)
+
+type Options struct {
+	Addr      string
+	Secret    string
+	Fail      int64
+	Tolerance time.Duration
+}
+
+// Parse reads the flags; secret is the configured signing secret used when -secret isn't given
+func Parse(args []string, secret string) (*Options, error) {
+	fs := flag.NewFlagSet("receiver", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price receiver [-addr :9000] [-secret s] [-fail n]")
+		fmt.Fprintln(fs.Output(), "Receives alert webhooks locally, verifies their signature and prints them.")
+		fs.PrintDefaults()
+	}
+	opts := &Options{}
+	fs.StringVar(&opts.Addr, "addr", ":9000", "address to listen on")
+	fs.StringVar(&opts.Secret, "secret", secret, "signing secret (default alerts.signing_secret)")
+	fs.Int64Var(&opts.Fail, "fail", 0, "answer 503 to the first n deliveries, to try out retries")
+	fs.DurationVar(&opts.Tolerance, "tolerance", webhook.DefaultTolerance, "accepted clock difference of delivery timestamps")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if opts.Secret == "" {
+		return nil, errors.New("a signing secret is required, set -secret or alerts.signing_secret")
+	}
+	return opts, nil
+}
+
+// Run serves until ctx is done, writing every verified event to out
+func Run(ctx context.Context, logger *slog.Logger, opts *Options, out io.Writer) error {
+	lg := logger.With("method", "receiver.Run")
+
+	var received atomic.Int64
+	mux := http.NewServeMux()
+	mux.HandleFunc("POST /", func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(io.LimitReader(r.Body, 1<<20))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		delivery, attempt := r.Header.Get(webhook.DeliveryHeader), r.Header.Get(webhook.AttemptHeader)
+		err = webhook.Verify(opts.Secret, r.Header.Get(webhook.TimestampHeader), r.Header.Get(webhook.SignatureHeader),
+			body, time.Now(), opts.Tolerance)
+		if err != nil {
+			lg.Warn("rejected delivery", "delivery", delivery, "attempt", attempt, "error", err)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if n := received.Add(1); n <= opts.Fail {
+			lg.Info("failing delivery on purpose", "delivery", delivery, "attempt", attempt, "n", n)
+			http.Error(w, "failing on purpose", http.StatusServiceUnavailable)
+			return
+		}
+
+		fmt.Fprintf(out, "%s %s attempt %s: %s\n", time.Now().Format(time.RFC3339), delivery, attempt, body)
+		w.WriteHeader(http.StatusNoContent)
+	})
+
+	srv := &http.Server{Addr: opts.Addr, Handler: mux, ReadHeaderTimeout: 10 * time.Second}
+	go func() {
+		<-ctx.Done()
+		shutdown, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		_ = srv.Shutdown(shutdown)
+	}()
+
+	lg.Info("receiving webhooks", "addr", opts.Addr)
+	if err := srv.ListenAndServe(); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return err
+	}
+	return nil
+}
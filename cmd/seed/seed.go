@@ -0,0 +1,83 @@
+package seed
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/synthetic"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+type Options struct {
+	Walks     []*synthetic.Walk
+	Period    time.Duration
+	Step      time.Duration
+	BatchSize int
+	Seed      uint64
+}
+
+func Parse(args []string) (*Options, error) {
+	fs := flag.NewFlagSet("seed", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price seed [flags]")
+		fmt.Fprintln(fs.Output(), "Fills coin_prices with synthetic random walks.")
+		fs.PrintDefaults()
+	}
+	symbols := fs.String("symbols", synthetic.DefaultSymbols, "comma separated symbol:start_price pairs")
+	period := fs.Duration("period", 30*24*time.Hour, "how far back from now to generate")
+	step := fs.Duration("step", time.Minute, "time between ticks")
+	drift := fs.Float64("drift", 0.05, "annualized drift")
+	volatility := fs.Float64("volatility", 0.6, "annualized volatility")
+	gapProb := fs.Float64("gap-prob", 0.0005, "probability that a tick starts a data gap")
+	maxGap := fs.Duration("max-gap", 2*time.Hour, "longest data gap")
+	seed := fs.Uint64("seed", uint64(time.Now().UnixNano()), "random seed, fix it for reproducible data")
+	batchSize := fs.Int("batch", 5000, "rows per insert")
+	if err := fs.Parse(args); err != nil {
+		return nil, err
+	}
+	if *step < time.Second || *period < *step || *batchSize < 1 {
+		return nil, fmt.Errorf("invalid seed parameters: step=%s period=%s batch=%d", *step, *period, *batchSize)
+	}
+
+	assets, err := synthetic.ParseSymbols(*symbols)
+	if err != nil {
+		return nil, err
+	}
+	opts := &Options{Period: *period, Step: *step, BatchSize: *batchSize, Seed: *seed}
+	for _, a := range assets {
+		w := synthetic.NewWalk(a.Symbol, a.Start, *drift, *volatility, *seed)
+		w.GapProbability = *gapProb
+		w.MaxGap = *maxGap
+		opts.Walks = append(opts.Walks, w)
+	}
+	return opts, nil
+}
+
+// Run fills coin_prices with synthetic random walks
+func Run(ctx context.Context, repo price.PriceRepository, logger *slog.Logger, opts *Options) error {
+	lg := logger.With("method", "seed.Run")
+
+	to := time.Now()
+	from := to.Add(-opts.Period)
+	lg.Info("seeding prices", "symbols", len(opts.Walks), "from", from, "to", to, "step", opts.Step, "seed", opts.Seed)
+
+	for _, w := range opts.Walks {
+		inserted := 0
+		err := w.Generate(from, to, opts.Step, opts.BatchSize, func(batch []*entity.Price) error {
+			if err := repo.BatchInsert(ctx, batch); err != nil {
+				return err
+			}
+			inserted += len(batch)
+			return nil
+		})
+		if err != nil {
+			return fmt.Errorf("failed to seed %s: %w", w.Symbol, err)
+		}
+		lg.Info("seeded symbol", "symbol", w.Symbol, "rows", inserted, "last_price", w.Price)
+	}
+	return nil
+}
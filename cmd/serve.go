@@ -0,0 +1,53 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"log/slog"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+)
+
+func runServe(cfg *config.Config, logger *slog.Logger, args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ContinueOnError)
+	fs.Usage = func() {
+		fmt.Fprintln(fs.Output(), "usage: price serve [flags]")
+		fmt.Fprintln(fs.Output(), "Runs the HTTP API.")
+		fs.PrintDefaults()
+	}
+	migrateflag := fs.Bool("migrate", false, "Apply pending migrations before starting the API")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	pg, err := connect(cfg)
+	if err != nil {
+		return fmt.Errorf("failed to initialize PostgreSQL Primary: %w", err)
+	}
+	defer pg.Close()
+
+	if *migrateflag {
+		err = migrateUp(cfg, logger)
+		if err != nil {
+			return fmt.Errorf("failed to apply migrations: %w", err)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), cfg.Database.ConnectTimeout.Duration)
+	defer cancel()
+	err = pg.ApplyPolicies(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to apply database policies: %w", err)
+	}
+
+	boot, err := wireApp(cfg, logger, pg, pg.Pool)
+	if err != nil {
+		return fmt.Errorf("failed to setup app: %w", err)
+	}
+	err = boot.Boot()
+	if err != nil {
+		return fmt.Errorf("failed to start app: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,45 @@
+package main
+
+import (
+	"context"
+	"log/slog"
+	"os"
+	"os/signal"
+	"syscall"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+// Services are the wire-built dependencies shared by the CLI commands
+type Services struct {
+	Prices price.PriceRepository
+	Price  service.PriceService
+}
+
+func NewServices(prices price.PriceRepository, priceService service.PriceService) *Services {
+	return &Services{
+		Prices: prices,
+		Price:  priceService,
+	}
+}
+
+// withServices connects to PostgreSQL, builds the services and runs fn until
+// it returns or the process is interrupted.
+func withServices(cfg *config.Config, logger *slog.Logger, fn func(ctx context.Context, s *Services) error) error {
+	pg, err := connect(cfg)
+	if err != nil {
+		return err
+	}
+	defer pg.Close()
+
+	s, err := wireServices(cfg, logger, pg, pg.Pool)
+	if err != nil {
+		return err
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	defer stop()
+	return fn(ctx, s)
+}
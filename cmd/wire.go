@@ -4,7 +4,7 @@
 package main
 
 import (
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
 	"github.com/milad-rasouli/price/internal/providers"
 	"log/slog"
@@ -18,7 +18,7 @@ import (
 )
 
 func wireApp(
-	env *godotenv.Env,
+	cfg *config.Config,
 	logger *slog.Logger,
 	pg *postgresql.Postgres,
 	pool *pgxpool.Pool,
@@ -32,3 +32,17 @@ func wireApp(
 		wire.NewSet(NewBoot),
 	))
 }
+
+func wireServices(
+	cfg *config.Config,
+	logger *slog.Logger,
+	pg *postgresql.Postgres,
+	pool *pgxpool.Pool,
+) (*Services, error) {
+	panic(wire.Build(
+		providers.ProviderSet,
+		repository.ProviderSet,
+		service.ProviderSet,
+		wire.NewSet(NewServices),
+	))
+}
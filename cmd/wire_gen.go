@@ -11,9 +11,14 @@ import (
 	"github.com/milad-rasouli/price/internal/app/api/controllers"
 	"github.com/milad-rasouli/price/internal/app/api/routes"
 	"github.com/milad-rasouli/price/internal/infrastructure/coingecko"
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
-	"github.com/milad-rasouli/price/internal/repository/repository/price/pgx"
+	"github.com/milad-rasouli/price/internal/providers"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert/pgx"
+	pgx3 "github.com/milad-rasouli/price/internal/repository/repository/anomaly/pgx"
+	pgx4 "github.com/milad-rasouli/price/internal/repository/repository/basket/pgx"
+	pgx5 "github.com/milad-rasouli/price/internal/repository/repository/catalog/pgx"
+	pgx2 "github.com/milad-rasouli/price/internal/repository/repository/price/pgx"
 	"github.com/milad-rasouli/price/internal/service"
 	"log/slog"
 )
@@ -24,17 +29,61 @@ import (
 
 // Injectors from wire.go:
 
-func wireApp(env *godotenv.Env, logger *slog.Logger, pg *postgresql.Postgres, pool *pgxpool.Pool) (*Boot, error) {
-	priceRepository := pgx.NewPriceRepository(pool)
-	coinGecko := coingecko.NewCoinGecko(logger)
-	priceService := service.NewPriceService(logger, priceRepository, coinGecko)
-	priceController := controller.NewPriceController(logger, priceService)
+func wireApp(cfg *config.Config, logger *slog.Logger, pg *postgresql.Postgres, pool *pgxpool.Pool) (*Boot, error) {
+	store := config.NewStore(cfg)
+	alertRepository := pgx.NewAlertRepository(pool)
+	priceRepository := pgx2.NewPriceRepository(pool)
+	alertService := service.NewAlertService(logger, store, alertRepository, priceRepository)
+	coinGecko := coingecko.NewCoinGecko(cfg, logger)
+	currencyProvider, err := providers.NewCurrencyProvider(store, logger, coinGecko)
+	if err != nil {
+		return nil, err
+	}
+	anomalyRepository := pgx3.NewAnomalyRepository(pool)
+	anomalyService := service.NewAnomalyService(logger, store, anomalyRepository, priceRepository)
+	basketRepository := pgx4.NewBasketRepository(pool)
+	basketService := service.NewBasketService(logger, store, basketRepository, priceRepository)
+	catalogRepository := pgx5.NewCatalogRepository(pool)
+	catalogService := service.NewCatalogService(logger, catalogRepository)
+	priceService := service.NewPriceService(logger, store, priceRepository, currencyProvider, anomalyService, basketService, alertService, catalogService)
+	priceController := controller.NewPriceController(logger, store, priceService)
 	priceRouter := routes.NewPriceRouter(priceController)
-	cronController := controller.NewCronController(logger, priceService)
+	cronController := controller.NewCronController(logger, store, priceService)
 	cronRouter := routes.NewCronRouter(cronController)
 	healthController := controller.NewHealthController(logger, pg)
 	healthRouter := routes.NewHealthRouter(healthController)
-	v := routes.CreateRouters(priceRouter, cronRouter, healthRouter)
-	boot := NewBoot(env, logger, v...)
+	adminController := controller.NewAdminController(logger, store)
+	adminRouter := routes.NewAdminRouter(adminController)
+	basketController := controller.NewBasketController(logger, basketService)
+	basketRouter := routes.NewBasketRouter(basketController, adminController)
+	alertController := controller.NewAlertController(logger, alertService)
+	alertRouter := routes.NewAlertRouter(alertController, adminController)
+	anomalyController := controller.NewAnomalyController(logger, anomalyService)
+	anomalyRouter := routes.NewAnomalyRouter(anomalyController, adminController)
+	catalogController := controller.NewCatalogController(logger, catalogService)
+	catalogRouter := routes.NewCatalogRouter(catalogController)
+	v := routes.CreateRouters(priceRouter, cronRouter, healthRouter, adminRouter, basketRouter, alertRouter, anomalyRouter, catalogRouter)
+	boot := NewBoot(cfg, store, logger, alertService, v...)
 	return boot, nil
 }
+
+func wireServices(cfg *config.Config, logger *slog.Logger, pg *postgresql.Postgres, pool *pgxpool.Pool) (*Services, error) {
+	priceRepository := pgx2.NewPriceRepository(pool)
+	store := config.NewStore(cfg)
+	coinGecko := coingecko.NewCoinGecko(cfg, logger)
+	currencyProvider, err := providers.NewCurrencyProvider(store, logger, coinGecko)
+	if err != nil {
+		return nil, err
+	}
+	anomalyRepository := pgx3.NewAnomalyRepository(pool)
+	anomalyService := service.NewAnomalyService(logger, store, anomalyRepository, priceRepository)
+	basketRepository := pgx4.NewBasketRepository(pool)
+	basketService := service.NewBasketService(logger, store, basketRepository, priceRepository)
+	alertRepository := pgx.NewAlertRepository(pool)
+	alertService := service.NewAlertService(logger, store, alertRepository, priceRepository)
+	catalogRepository := pgx5.NewCatalogRepository(pool)
+	catalogService := service.NewCatalogService(logger, catalogRepository)
+	priceService := service.NewPriceService(logger, store, priceRepository, currencyProvider, anomalyService, basketService, alertService, catalogService)
+	services := NewServices(priceRepository, priceService)
+	return services, nil
+}
@@ -15,35 +15,47 @@ const docTemplate = `{
     "host": "{{.Host}}",
     "basePath": "{{.BasePath}}",
     "paths": {
-        "/liveness": {
+        "/admin/alerts": {
             "get": {
-                "description": "Used by Kubernetes or monitoring tools to check if the service is alive.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "alerts"
+                ],
+                "summary": "List alert rules",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
                 ],
-                "summary": "Liveness probe",
                 "responses": {
                     "200": {
-                        "description": "Service is alive",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AlertRuleRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "503": {
-                        "description": "Service unavailable",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     }
                 }
-            }
-        },
-        "/prices/history": {
-            "get": {
-                "description": "Returns historical price data for a given symbol within a time range, optionally grouped by interval.",
+            },
+            "post": {
+                "description": "Rules are evaluated after every ingested batch and fire when their condition starts to hold.\nThe first evaluation only records the state, so a condition that already holds at creation doesn't fire until it stopped holding in between.\nConditions:\n` + "`" + `crosses_above` + "`" + `/` + "`" + `crosses_below` + "`" + ` compare the price with ` + "`" + `threshold` + "`" + `, ` + "`" + `change` + "`" + ` compares the absolute percentage change over ` + "`" + `window` + "`" + ` with it.\nEvents are posted as dto.AlertEvent to ` + "`" + `webhook_url` + "`" + `, signed with HMAC-SHA256 in X-Price-Signature over \"\u003cX-Price-Timestamp\u003e.\u003cbody\u003e\", and retried with backoff.",
                 "consumes": [
                     "application/json"
                 ],
@@ -51,41 +63,92 @@ const docTemplate = `{
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "alerts"
                 ],
-                "summary": "Get historical cryptocurrency prices",
+                "summary": "Create an alert rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Symbol (e.g., btc, eth)",
-                        "name": "symbol",
-                        "in": "query",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
                     },
                     {
-                        "type": "string",
-                        "description": "Interval (e.g., 1m, 5m, 1h, 1d)",
-                        "name": "interval",
-                        "in": "query"
+                        "description": "Rule",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AlertRuleReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "201": {
+                        "description": "Created",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AlertRuleRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
                     },
+                    "409": {
+                        "description": "alerts.signing_secret is not set",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/alerts/{id}": {
+            "delete": {
+                "description": "Deletes the rule and its delivery log.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "alerts"
+                ],
+                "summary": "Delete an alert rule",
+                "parameters": [
                     {
-                        "type": "integer",
-                        "description": "Start time (unix timestamp)",
-                        "name": "from",
-                        "in": "query"
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
                     },
                     {
                         "type": "integer",
-                        "description": "End time (unix timestamp)",
-                        "name": "to",
-                        "in": "query"
+                        "description": "Rule id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_HistoryRes"
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
                     "400": {
@@ -94,20 +157,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "408": {
-                        "description": "Request Timeout",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "504": {
-                        "description": "Gateway Timeout",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
@@ -115,33 +178,43 @@ const docTemplate = `{
                 }
             }
         },
-        "/prices/latest": {
+        "/admin/alerts/{id}/deliveries": {
             "get": {
-                "description": "Returns the latest stored price for a given symbol, including 24h change.",
-                "consumes": [
-                    "application/json"
-                ],
+                "description": "Returns every delivery attempt, newest first.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "prices"
+                    "alerts"
                 ],
-                "summary": "Get latest cryptocurrency price",
+                "summary": "List webhook deliveries of a rule",
                 "parameters": [
                     {
                         "type": "string",
-                        "description": "Symbol (e.g., btc, eth)",
-                        "name": "symbol",
-                        "in": "query",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
                         "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Rule id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Attempts to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
                     }
                 ],
                 "responses": {
                     "200": {
                         "description": "OK",
                         "schema": {
-                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_LatestRes"
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AlertDeliveryRes"
                         }
                     },
                     "400": {
@@ -150,20 +223,20 @@ const docTemplate = `{
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "408": {
-                        "description": "Request Timeout",
+                    "401": {
+                        "description": "Unauthorized",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "500": {
-                        "description": "Internal Server Error",
+                    "404": {
+                        "description": "Not Found",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "504": {
-                        "description": "Gateway Timeout",
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
@@ -171,98 +244,2386 @@ const docTemplate = `{
                 }
             }
         },
-        "/readiness": {
+        "/admin/anomalies": {
             "get": {
-                "description": "Verifies if dependencies (e.g., PostgreSQL) are healthy and service can handle requests.",
+                "description": "Ticks that moved more than anomaly.max_change_pct from the last stored price, or whose return was more than anomaly.z_score standard deviations from the recent returns, newest first.\nFlagged ticks were stored as usual, quarantined ones wait for approval.",
                 "produces": [
                     "application/json"
                 ],
                 "tags": [
-                    "health"
+                    "anomalies"
+                ],
+                "summary": "List anomalous ticks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "pending (default), approved, rejected or all",
+                        "name": "status",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Symbol, e.g. btc",
+                        "name": "symbol",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Anomalies to return (default 50, max 500)",
+                        "name": "limit",
+                        "in": "query"
+                    }
                 ],
-                "summary": "Readiness probe",
                 "responses": {
                     "200": {
-                        "description": "Service is ready",
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AnomalyRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     },
-                    "503": {
-                        "description": "Service not ready",
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
                         "schema": {
                             "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
                         }
                     }
                 }
             }
-        }
-    },
-    "definitions": {
-        "github_com_milad-rasouli_price_internal_app_api_dto.HistoryRes": {
-            "type": "object",
-            "properties": {
-                "avg_price": {
-                    "type": "number"
-                },
-                "last_price": {
-                    "type": "number"
-                },
-                "startedAt": {
-                    "type": "integer"
-                },
-                "symbol": {
-                    "type": "string"
-                }
-            }
-        },
-        "github_com_milad-rasouli_price_internal_app_api_dto.LatestRes": {
-            "type": "object",
-            "properties": {
-                "change_24h_pct": {
-                    "type": "number"
-                },
-                "price": {
-                    "type": "number"
-                },
-                "symbol": {
-                    "type": "string"
-                },
-                "timestamp": {
-                    "type": "integer"
-                }
-            }
-        },
-        "github_com_milad-rasouli_price_internal_app_api_response.Response-any": {
-            "type": "object",
-            "properties": {
-                "data": {},
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
-                }
-            }
         },
-        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_HistoryRes": {
-            "type": "object",
-            "properties": {
-                "data": {
-                    "type": "array",
-                    "items": {
-                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.HistoryRes"
+        "/admin/anomalies/{id}/approve": {
+            "post": {
+                "description": "Stores a quarantined tick with its market data; a flagged tick is already stored and only marked approved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Approve an anomalous tick",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Anomaly id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AnomalyRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
                     }
-                },
-                "message": {
-                    "type": "string"
-                },
-                "status": {
-                    "type": "integer"
                 }
             }
         },
-        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_LatestRes": {
+        "/admin/anomalies/{id}/reject": {
+            "post": {
+                "description": "Removes a flagged tick and its market data from the stored prices; a quarantined tick is only marked rejected.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "anomalies"
+                ],
+                "summary": "Reject an anomalous tick",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Anomaly id",
+                        "name": "id",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AnomalyRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "already reviewed",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/baskets/{name}": {
+            "put": {
+                "description": "Fixes the units of every component so that each holds its weight of the basket value at the latest prices.\nA new basket starts at ` + "`" + `base_value` + "`" + ` (default 100), an existing one is rebalanced at its current value.\nThe name can't be one any coin is stored under, or one of a deleted basket.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Create or rebalance a basket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Basket name, up to 16 lowercase letters, digits and dashes",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    },
+                    {
+                        "description": "Components and weights",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.BasketReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_BasketRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "a component, or the basket being rebalanced, has no recent price",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "409": {
+                        "description": "the name is taken by another symbol",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            },
+            "delete": {
+                "description": "Stops valuing the basket. Its stored prices are kept and its name stays reserved.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Delete a basket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Basket name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/admin/reload": {
+            "post": {
+                "description": "Reads the config file and environment again and applies the tracked symbols, provider priority and the query, alerts and anomaly settings from the next ingestion tick. The cron process picks up the interval from the next update-prices response. Other changed settings are listed in restart_required. Same as sending SIGHUP to the process.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "admin"
+                ],
+                "summary": "Reload the configuration",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Bearer ADMIN_TOKEN",
+                        "name": "Authorization",
+                        "in": "header",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_ReloadRes"
+                        }
+                    },
+                    "401": {
+                        "description": "Unauthorized",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "ADMIN_TOKEN is not set",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "422": {
+                        "description": "the new configuration is invalid, the current one is kept",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets": {
+            "get": {
+                "description": "Lists the weighted baskets. Their values are stored as prices under the basket name on every ingestion tick,\nso /prices/latest, /prices/history and the other price endpoints accept a basket name as the symbol.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "List baskets",
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_BasketRes"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/baskets/{name}": {
+            "get": {
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "baskets"
+                ],
+                "summary": "Get a basket",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Basket name",
+                        "name": "name",
+                        "in": "path",
+                        "required": true
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_BasketRes"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/convert": {
+            "get": {
+                "description": "Uses the stored from-to pair when there is one, otherwise the inverse of to-from, otherwise crosses the USD prices of both.\nPrices are the ones in effect at ` + "`" + `at` + "`" + `, or the latest ones; ` + "`" + `legs` + "`" + ` lists each price used and when it was recorded.\nA pair priced more than ` + "`" + `max_staleness` + "`" + ` before ` + "`" + `at` + "`" + ` is passed over; when only stale prices are left the conversion fails with 404.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Convert an amount between currencies",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Currency to convert from (e.g., eth)",
+                        "name": "from",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Currency to convert to (e.g., btc, usd)",
+                        "name": "to",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Amount to convert (default 1)",
+                        "name": "amount",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Convert at this time (unix timestamp, default now)",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Oldest acceptable price before at (e.g., 15m, 2d), default from the config",
+                        "name": "max_staleness",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_ConvertRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "no rate, or only stale ones",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/liveness": {
+            "get": {
+                "description": "Used by Kubernetes or monitoring tools to check if the service is alive.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Liveness probe",
+                "responses": {
+                    "200": {
+                        "description": "Service is alive",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "503": {
+                        "description": "Service unavailable",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/portfolio/value": {
+            "post": {
+                "description": "Values every holding at ` + "`" + `at` + "`" + ` (default now) and at ` + "`" + `since` + "`" + ` (default 24h earlier) from stored prices,\nwith each asset's weight and the profit and loss between the two times.\nEvery holding needs a price within ` + "`" + `max_staleness` + "`" + ` of both times, otherwise the request fails with 404.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "portfolio"
+                ],
+                "summary": "Value a portfolio",
+                "parameters": [
+                    {
+                        "description": "Holdings to value",
+                        "name": "request",
+                        "in": "body",
+                        "required": true,
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PortfolioReq"
+                        }
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_PortfolioRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "a holding has no recent price",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/asof": {
+            "get": {
+                "description": "Returns the price of every symbol in effect at every requested time, i.e. the last one recorded at or before it.\nA price recorded more than ` + "`" + `max_staleness` + "`" + ` earlier is reported with status ` + "`" + `stale` + "`" + ` and no price; ` + "`" + `missing` + "`" + ` means there is no earlier price at all.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get prices as of given times",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Comma separated symbols (e.g., btc,eth)",
+                        "name": "symbols",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated times, unix seconds or RFC 3339 (default now)",
+                        "name": "at",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Oldest acceptable price before each time (e.g., 15m, 2d), default from the config",
+                        "name": "max_staleness",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AsOfRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/history": {
+            "get": {
+                "description": "Returns historical price data for a given symbol within a time range, optionally grouped by interval.\nEvery interval bucket overlapping the range is returned whole, so edge buckets may hold data outside it.\nSend ` + "`" + `Accept: text/csv` + "`" + ` or ` + "`" + `Accept: application/x-ndjson` + "`" + ` to stream the rows as an export.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get historical cryptocurrency prices",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval (e.g., 30s, 1m, 5m, 1h, 1d, 1w)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response, only sent when to is given",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_HistoryRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/indicators": {
+            "get": {
+                "description": "Computes SMA, EMA, RSI, Bollinger bands and MACD over the closing price of every bucket, as returned by /prices/history.\n` + "`" + `indicators` + "`" + ` is a comma separated list of name[:params]: sma:period, ema:period, rsi:period, bollinger:period:width and macd:fast:slow:signal.\nPeriods are between 1 and 200. History before ` + "`" + `from` + "`" + ` is read automatically so the first point is already warmed up.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get technical indicators",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval (e.g., 5m, 1h, 1d), default 1h",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp, default to - 24h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp, default now)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Indicators (default sma:20,ema:20,rsi:14,bollinger:20:2,macd:12:26:9)",
+                        "name": "indicators",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_IndicatorsRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/latest": {
+            "get": {
+                "description": "Returns the latest stored price for a given symbol, its change over each requested window and the market data reported with it.\nEvery change is relative to the price in effect when the window started, returned as ` + "`" + `reference` + "`" + `.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get latest cryptocurrency price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Comma separated intervals or ytd (default 1h,24h,7d,30d,ytd)",
+                        "name": "windows",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_LatestRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/market/history": {
+            "get": {
+                "description": "Returns market cap, 24h volume, circulating supply and 24h high/low for a symbol, one point per interval holding the values last reported within it.\nEvery interval bucket overlapping the range is returned whole, as in /prices/history.\nSend ` + "`" + `Accept: text/csv` + "`" + ` or ` + "`" + `Accept: application/x-ndjson` + "`" + ` to stream the rows as an export.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get historical market data",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval (e.g., 30s, 1m, 5m, 1h, 1d, 1w)",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "ETag from a previous response",
+                        "name": "If-None-Match",
+                        "in": "header"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Last-Modified from a previous response, only sent when to is given",
+                        "name": "If-Modified-Since",
+                        "in": "header"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_MarketHistoryRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/statistics": {
+            "get": {
+                "description": "Summarizes the closing price of every bucket in a window, as returned by /prices/history:\nmin/max, simple and log return, realized volatility of the bucket log returns and the maximum drawdown.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get window statistics",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "string",
+                        "description": "Interval (e.g., 5m, 1h, 1d), default 1h",
+                        "name": "interval",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp, default to - 24h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp, default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_StatisticsRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/ticks": {
+            "get": {
+                "description": "Returns the raw stored rows for a symbol within a time range, paginated with an opaque cursor.\nPass ` + "`" + `next_cursor` + "`" + ` from a response as ` + "`" + `cursor` + "`" + ` to fetch the next page.\nSend ` + "`" + `Accept: text/csv` + "`" + ` or ` + "`" + `Accept: application/x-ndjson` + "`" + ` to stream every row in the range as an export.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json",
+                    "text/csv",
+                    "application/x-ndjson"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get raw cryptocurrency price ticks",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp)",
+                        "name": "to",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Page size (default 100, max 1000)",
+                        "name": "limit",
+                        "in": "query"
+                    },
+                    {
+                        "enum": [
+                            "asc",
+                            "desc"
+                        ],
+                        "type": "string",
+                        "description": "Sort order by time",
+                        "name": "order",
+                        "in": "query"
+                    },
+                    {
+                        "type": "string",
+                        "description": "Cursor from a previous page",
+                        "name": "cursor",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_TicksRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/twap": {
+            "get": {
+                "description": "Averages the price over a window, weighting every tick by how long it held until the next one.\nThe last tick before ` + "`" + `from` + "`" + ` counts for the start of the window.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get the time-weighted average price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp, default to - 24h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp, default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AverageRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "Not Found",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/prices/vwap": {
+            "get": {
+                "description": "Averages the price over a window, weighting every tick by the time it held times the rolling 24h volume reported with it.\nThis is not a true VWAP: providers don't report the volume traded at each price, so the 24h volume stands in for the trading rate.",
+                "consumes": [
+                    "application/json"
+                ],
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "prices"
+                ],
+                "summary": "Get a volume-proxy weighted average price",
+                "parameters": [
+                    {
+                        "type": "string",
+                        "description": "Symbol (e.g., btc, eth)",
+                        "name": "symbol",
+                        "in": "query",
+                        "required": true
+                    },
+                    {
+                        "type": "integer",
+                        "description": "Start time (unix timestamp, default to - 24h)",
+                        "name": "from",
+                        "in": "query"
+                    },
+                    {
+                        "type": "integer",
+                        "description": "End time (unix timestamp, default now)",
+                        "name": "to",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AverageRes"
+                        }
+                    },
+                    "304": {
+                        "description": "Not Modified"
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "404": {
+                        "description": "no price or no volume in the window",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "408": {
+                        "description": "Request Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "504": {
+                        "description": "Gateway Timeout",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/readiness": {
+            "get": {
+                "description": "Verifies if dependencies (e.g., PostgreSQL) are healthy, the database schema matches this build and service can handle requests.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "health"
+                ],
+                "summary": "Readiness probe",
+                "responses": {
+                    "200": {
+                        "description": "Service is ready",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "503": {
+                        "description": "Service not ready",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        },
+        "/symbols": {
+            "get": {
+                "description": "Lists every symbol with stored prices: its name and provider ids, the first and last stored tick, the row count\nand whether the latest ingestion tick included it. The row count includes rows dropped by raw retention since.",
+                "produces": [
+                    "application/json"
+                ],
+                "tags": [
+                    "symbols"
+                ],
+                "summary": "List symbols",
+                "parameters": [
+                    {
+                        "type": "boolean",
+                        "description": "Only symbols the latest ingestion tick did (true) or didn't (false) include",
+                        "name": "ingesting",
+                        "in": "query"
+                    }
+                ],
+                "responses": {
+                    "200": {
+                        "description": "OK",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_SymbolRes"
+                        }
+                    },
+                    "400": {
+                        "description": "Bad Request",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    },
+                    "500": {
+                        "description": "Internal Server Error",
+                        "schema": {
+                            "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_response.Response-any"
+                        }
+                    }
+                }
+            }
+        }
+    },
+    "definitions": {
+        "github_com_milad-rasouli_price_internal_app_api_dto.AlertDeliveryRes": {
+            "type": "object",
+            "properties": {
+                "attempt": {
+                    "type": "integer"
+                },
+                "delivered": {
+                    "type": "boolean"
+                },
+                "error": {
+                    "type": "string"
+                },
+                "event_id": {
+                    "type": "string"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "payload": {
+                    "type": "string"
+                },
+                "status_code": {
+                    "type": "integer"
+                },
+                "time": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.AlertRuleReq": {
+            "type": "object",
+            "required": [
+                "kind",
+                "symbol",
+                "webhook_url"
+            ],
+            "properties": {
+                "kind": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "string"
+                },
+                "webhook_url": {
+                    "type": "string"
+                },
+                "window": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.AlertRuleRes": {
+            "type": "object",
+            "properties": {
+                "active": {
+                    "description": "null until the rule is first evaluated",
+                    "type": "boolean"
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "kind": {
+                    "type": "string"
+                },
+                "last_triggered_at": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "threshold": {
+                    "type": "number"
+                },
+                "webhook_url": {
+                    "type": "string"
+                },
+                "window_seconds": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.AnomalyRes": {
+            "type": "object",
+            "properties": {
+                "action": {
+                    "type": "string"
+                },
+                "change_pct": {
+                    "type": "number"
+                },
+                "detected_at": {
+                    "type": "integer"
+                },
+                "id": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "reference": {
+                    "description": "the last stored price before the tick",
+                    "type": "number"
+                },
+                "reviewed_at": {
+                    "type": "integer"
+                },
+                "status": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "time": {
+                    "type": "integer"
+                },
+                "z_score": {
+                    "description": "null when the history was too short",
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.AsOfRes": {
+            "type": "object",
+            "properties": {
+                "age_seconds": {
+                    "type": "integer"
+                },
+                "at": {
+                    "type": "integer"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "status": {
+                    "description": "ok, stale or missing",
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.AverageRes": {
+            "type": "object",
+            "properties": {
+                "covered_seconds": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "integer"
+                },
+                "method": {
+                    "description": "twap or vwap",
+                    "type": "string"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "ticks": {
+                    "type": "integer"
+                },
+                "to": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.BasketComponentReq": {
+            "type": "object",
+            "required": [
+                "symbol"
+            ],
+            "properties": {
+                "symbol": {
+                    "type": "string"
+                },
+                "weight": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.BasketComponentRes": {
+            "type": "object",
+            "properties": {
+                "symbol": {
+                    "type": "string"
+                },
+                "units": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.BasketReq": {
+            "type": "object",
+            "required": [
+                "components"
+            ],
+            "properties": {
+                "base_value": {
+                    "type": "string"
+                },
+                "components": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.BasketComponentReq"
+                    }
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.BasketRes": {
+            "type": "object",
+            "properties": {
+                "base_value": {
+                    "type": "number"
+                },
+                "components": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.BasketComponentRes"
+                    }
+                },
+                "created_at": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "updated_at": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.ChangeRes": {
+            "type": "object",
+            "properties": {
+                "change": {
+                    "type": "number"
+                },
+                "change_pct": {
+                    "type": "number"
+                },
+                "reference": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.ConvertLeg": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.ConvertRes": {
+            "type": "object",
+            "properties": {
+                "amount": {
+                    "type": "number"
+                },
+                "at": {
+                    "type": "integer"
+                },
+                "from": {
+                    "type": "string"
+                },
+                "legs": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.ConvertLeg"
+                    }
+                },
+                "method": {
+                    "description": "identity, direct, inverse or cross",
+                    "type": "string"
+                },
+                "rate": {
+                    "type": "number"
+                },
+                "result": {
+                    "type": "number"
+                },
+                "to": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.Drawdown": {
+            "type": "object",
+            "properties": {
+                "peak": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "ratio": {
+                    "type": "number"
+                },
+                "trough": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.HistoryRes": {
+            "type": "object",
+            "properties": {
+                "avg_price": {
+                    "type": "number"
+                },
+                "last_price": {
+                    "type": "number"
+                },
+                "startedAt": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.HoldingReq": {
+            "type": "object",
+            "required": [
+                "symbol"
+            ],
+            "properties": {
+                "quantity": {
+                    "type": "string"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.HoldingRes": {
+            "type": "object",
+            "properties": {
+                "pnl": {
+                    "type": "number"
+                },
+                "pnl_pct": {
+                    "type": "number"
+                },
+                "price": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "quantity": {
+                    "type": "number"
+                },
+                "since_price": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "since_value": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "value": {
+                    "type": "number"
+                },
+                "weight": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.IndicatorPoint": {
+            "type": "object",
+            "properties": {
+                "close": {
+                    "type": "number"
+                },
+                "startedAt": {
+                    "type": "integer"
+                },
+                "values": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.IndicatorsRes": {
+            "type": "object",
+            "properties": {
+                "interval": {
+                    "type": "string"
+                },
+                "points": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.IndicatorPoint"
+                    }
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.LatestRes": {
+            "type": "object",
+            "properties": {
+                "change_24h_pct": {
+                    "type": "number"
+                },
+                "changes": {
+                    "type": "object",
+                    "additionalProperties": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.ChangeRes"
+                    }
+                },
+                "market": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.MarketRes"
+                },
+                "price": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.MarketHistoryRes": {
+            "type": "object",
+            "properties": {
+                "circulating_supply": {
+                    "type": "string"
+                },
+                "high_24h": {
+                    "type": "string"
+                },
+                "low_24h": {
+                    "type": "string"
+                },
+                "market_cap": {
+                    "type": "string"
+                },
+                "startedAt": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "total_volume": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.MarketRes": {
+            "type": "object",
+            "properties": {
+                "circulating_supply": {
+                    "type": "string"
+                },
+                "high_24h": {
+                    "type": "string"
+                },
+                "low_24h": {
+                    "type": "string"
+                },
+                "market_cap": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                },
+                "total_volume": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.PortfolioReq": {
+            "type": "object",
+            "required": [
+                "holdings"
+            ],
+            "properties": {
+                "at": {
+                    "type": "integer"
+                },
+                "holdings": {
+                    "type": "array",
+                    "minItems": 1,
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.HoldingReq"
+                    }
+                },
+                "max_staleness": {
+                    "description": "e.g. 15m or 2d, default from the config",
+                    "type": "string"
+                },
+                "since": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.PortfolioRes": {
+            "type": "object",
+            "properties": {
+                "assets": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.HoldingRes"
+                    }
+                },
+                "at": {
+                    "type": "integer"
+                },
+                "pnl": {
+                    "type": "number"
+                },
+                "pnl_pct": {
+                    "type": "number"
+                },
+                "since": {
+                    "type": "integer"
+                },
+                "since_value": {
+                    "type": "number"
+                },
+                "value": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.PricePoint": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.ReloadRes": {
+            "type": "object",
+            "properties": {
+                "file": {
+                    "type": "string"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "providers": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "quote_currencies": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "restart_required": {
+                    "description": "RestartRequired lists changed settings that are only read at startup",
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "symbols": {
+                    "type": "array",
+                    "items": {
+                        "type": "string"
+                    }
+                },
+                "timeout": {
+                    "type": "string"
+                },
+                "top": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.StatisticsRes": {
+            "type": "object",
+            "properties": {
+                "annualized_volatility": {
+                    "type": "number"
+                },
+                "buckets": {
+                    "type": "integer"
+                },
+                "close": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "interval": {
+                    "type": "string"
+                },
+                "log_return": {
+                    "type": "number"
+                },
+                "max": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "max_drawdown": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.Drawdown"
+                },
+                "min": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "open": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PricePoint"
+                },
+                "simple_return": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "volatility": {
+                    "type": "number"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.SymbolRes": {
+            "type": "object",
+            "properties": {
+                "basket": {
+                    "type": "boolean"
+                },
+                "first_time": {
+                    "description": "null while no price is stored",
+                    "type": "integer"
+                },
+                "ingesting": {
+                    "description": "part of the latest ingestion tick",
+                    "type": "boolean"
+                },
+                "last_ingested_at": {
+                    "type": "integer"
+                },
+                "last_time": {
+                    "type": "integer"
+                },
+                "name": {
+                    "type": "string"
+                },
+                "provider_ids": {
+                    "description": "provider -\u003e the coin's id there",
+                    "type": "object",
+                    "additionalProperties": {
+                        "type": "string"
+                    }
+                },
+                "rows": {
+                    "type": "integer"
+                },
+                "symbol": {
+                    "type": "string"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.TickRes": {
+            "type": "object",
+            "properties": {
+                "price": {
+                    "type": "number"
+                },
+                "symbol": {
+                    "type": "string"
+                },
+                "timestamp": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_dto.TicksRes": {
+            "type": "object",
+            "properties": {
+                "next_cursor": {
+                    "type": "string"
+                },
+                "ticks": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.TickRes"
+                    }
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-any": {
+            "type": "object",
+            "properties": {
+                "data": {},
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AlertDeliveryRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AlertDeliveryRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AlertRuleRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AlertRuleRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AnomalyRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AnomalyRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_AsOfRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AsOfRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_BasketRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.BasketRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_HistoryRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.HistoryRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_MarketHistoryRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.MarketHistoryRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-array_github_com_milad-rasouli_price_internal_app_api_dto_SymbolRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "type": "array",
+                    "items": {
+                        "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.SymbolRes"
+                    }
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AlertRuleRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AlertRuleRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AnomalyRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AnomalyRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_AverageRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.AverageRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_BasketRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.BasketRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_ConvertRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.ConvertRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_IndicatorsRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.IndicatorsRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_LatestRes": {
             "type": "object",
             "properties": {
                 "data": {
@@ -275,6 +2636,62 @@ const docTemplate = `{
                     "type": "integer"
                 }
             }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_PortfolioRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.PortfolioRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_ReloadRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.ReloadRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_StatisticsRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.StatisticsRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
+        },
+        "github_com_milad-rasouli_price_internal_app_api_response.Response-github_com_milad-rasouli_price_internal_app_api_dto_TicksRes": {
+            "type": "object",
+            "properties": {
+                "data": {
+                    "$ref": "#/definitions/github_com_milad-rasouli_price_internal_app_api_dto.TicksRes"
+                },
+                "message": {
+                    "type": "string"
+                },
+                "status": {
+                    "type": "integer"
+                }
+            }
         }
     }
 }`
@@ -0,0 +1,50 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+const (
+	AlertCrossesAbove = "crosses_above"
+	AlertCrossesBelow = "crosses_below"
+	// AlertChange fires when the price moved by at least Threshold percent, either way, over Window
+	AlertChange = "change"
+)
+
+// AlertRule fires when its condition starts to hold, and again only after it
+// stopped holding in between. Active is nil until the rule is first evaluated,
+// which only records the state: a condition holding at creation doesn't fire.
+type AlertRule struct {
+	ID              int64
+	Symbol          string
+	Kind            string
+	Threshold       decimal.Decimal
+	Window          int64 // seconds, change rules only
+	WebhookURL      string
+	Active          *bool
+	CreatedAt       int64
+	LastTriggeredAt *int64
+}
+
+// AlertDelivery is one attempt to deliver an event to a rule's webhook
+type AlertDelivery struct {
+	ID         int64
+	RuleID     int64
+	EventID    string
+	Attempt    int
+	StatusCode *int
+	Error      string
+	Delivered  bool
+	Payload    string
+	Time       int64
+}
+
+// AlertOutboxEvent is a fired event waiting for delivery. Attempts counts the
+// deliveries tried so far.
+type AlertOutboxEvent struct {
+	EventID       string
+	RuleID        int64
+	WebhookURL    string
+	Payload       string
+	Attempts      int
+	NextAttemptAt int64
+	CreatedAt     int64
+}
@@ -0,0 +1,24 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+const (
+	AnomalyPending  = "pending"
+	AnomalyApproved = "approved"
+	AnomalyRejected = "rejected"
+)
+
+// Anomaly is an ingested tick that deviated too far from the recent prices of
+// its symbol. Action tells whether it was stored anyway (flag) or held back
+// until it is approved (quarantine).
+type Anomaly struct {
+	ID         int64
+	Tick       Price
+	Action     string
+	Reference  decimal.Decimal // the last stored price before the tick
+	ChangePct  decimal.Decimal
+	ZScore     *float64 // nil when the history was too short
+	Status     string
+	DetectedAt int64
+	ReviewedAt *int64
+}
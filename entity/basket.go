@@ -0,0 +1,33 @@
+package entity
+
+import "github.com/shopspring/decimal"
+
+// Basket is a weighted set of symbols whose value is stored as a price under
+// its own name. Units are fixed when the basket is saved, so that each
+// component held Weight of BaseValue at that time.
+type Basket struct {
+	Name       string            `json:"name"`
+	BaseValue  decimal.Decimal   `json:"base_value"`
+	CreatedAt  int64             `json:"created_at"`
+	UpdatedAt  int64             `json:"updated_at"`
+	Components []BasketComponent `json:"components"`
+}
+
+type BasketComponent struct {
+	Symbol string          `json:"symbol"`
+	Weight decimal.Decimal `json:"weight"` // share of the value when the basket was saved, weights add up to 1
+	Units  decimal.Decimal `json:"units"`
+}
+
+// Value is the basket's value at the given component prices; ok is false
+// when a component has no price
+func (b *Basket) Value(prices map[string]decimal.Decimal) (value decimal.Decimal, ok bool) {
+	for _, c := range b.Components {
+		p, found := prices[c.Symbol]
+		if !found {
+			return decimal.Zero, false
+		}
+		value = value.Add(c.Units.Mul(p))
+	}
+	return value, true
+}
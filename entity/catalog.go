@@ -0,0 +1,25 @@
+package entity
+
+// CatalogEntry is a symbol with stored prices. FirstTime and LastTime are
+// nil while none are stored; Ingesting tells whether the latest ingestion
+// tick included it. Basket marks names reserved by a basket, deleted or not.
+type CatalogEntry struct {
+	Symbol         string
+	Name           string
+	ProviderIDs    map[string]string
+	FirstTime      *int64
+	LastTime       *int64
+	Rows           int64
+	Basket         bool
+	Ingesting      bool
+	LastIngestedAt *int64
+}
+
+// DataVersion identifies what is stored for a symbol up to a point in time.
+// LastTime is the newest data at or before that time; Revision is bumped by
+// every write that changed data already stored, at RevisedAt.
+type DataVersion struct {
+	LastTime  int64
+	Revision  int64
+	RevisedAt int64
+}
@@ -4,8 +4,38 @@ import (
 	"github.com/shopspring/decimal"
 )
 
+// DefaultQuote is the quote currency of plain symbols such as "btc"
+const DefaultQuote = "usd"
+
 type Price struct {
 	Symbol string          `json:"symbol"`
 	Price  decimal.Decimal `json:"price"`
 	Time   int64           `json:"time"`
+	Market *Market         `json:"market,omitempty"` // nil when the provider reports none
+	Asset  *Asset          `json:"asset,omitempty"`  // nil when the provider reports none
+}
+
+// Asset is what the provider knows about the coin behind a price
+type Asset struct {
+	Name        string            `json:"name"`
+	ProviderIDs map[string]string `json:"provider_ids"` // provider -> the coin's id there, e.g. coingecko: bitcoin
+}
+
+// Market is what the provider reports about a coin's market along with its
+// price, in the same quote currency. Any field may be missing.
+type Market struct {
+	MarketCap         decimal.NullDecimal `json:"market_cap"`
+	TotalVolume       decimal.NullDecimal `json:"total_volume"` // traded over the last 24 hours
+	CirculatingSupply decimal.NullDecimal `json:"circulating_supply"`
+	High24h           decimal.NullDecimal `json:"high_24h"`
+	Low24h            decimal.NullDecimal `json:"low_24h"`
+}
+
+// PairSymbol is the stored symbol of base priced in quote: "btc" in USD,
+// "btc-eur" in any other quote currency.
+func PairSymbol(base, quote string) string {
+	if quote == "" || quote == DefaultQuote {
+		return base
+	}
+	return base + "-" + quote
 }
@@ -0,0 +1,79 @@
+package controller
+
+import (
+	"crypto/subtle"
+	"log/slog"
+	"net/http"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+)
+
+type AdminController struct {
+	logger *slog.Logger
+	store  *config.Store
+}
+
+func NewAdminController(logger *slog.Logger, store *config.Store) *AdminController {
+	return &AdminController{
+		logger: logger.With("layer", "AdminController"),
+		store:  store,
+	}
+}
+
+// Authorize lets requests through when they carry the admin token as a
+// bearer token. Without a configured token the admin endpoints don't exist.
+func (ac *AdminController) Authorize(c *gin.Context) {
+	token := ac.store.Current().Admin.Token
+	if token == "" {
+		response.NotFound(c)
+		c.Abort()
+		return
+	}
+
+	given, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok || subtle.ConstantTimeCompare([]byte(given), []byte(token)) != 1 {
+		ac.logger.Warn("rejected admin request", "path", c.FullPath(), "ip", c.ClientIP())
+		response.Custom(c, http.StatusUnauthorized, nil, "unauthorized")
+		c.Abort()
+		return
+	}
+	c.Next()
+}
+
+// Reload godoc
+// @Summary Reload the configuration
+// @Description Reads the config file and environment again and applies the tracked symbols, provider priority and the query, alerts and anomaly settings from the next ingestion tick. The cron process picks up the interval from the next update-prices response. Other changed settings are listed in restart_required. Same as sending SIGHUP to the process.
+// @Tags admin
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Success 200 {object} response.Response[dto.ReloadRes]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any] "ADMIN_TOKEN is not set"
+// @Failure 422 {object} response.Response[any] "the new configuration is invalid, the current one is kept"
+// @Router /admin/reload [post]
+func (ac *AdminController) Reload(c *gin.Context) {
+	lg := ac.logger.With("method", "Reload")
+
+	cfg, restart, err := ac.store.Reload()
+	if err != nil {
+		lg.Warn("failed to reload configuration", "error", err)
+		response.Custom(c, http.StatusUnprocessableEntity, nil, err.Error())
+		return
+	}
+	lg.Info("configuration reloaded", "restart_required", restart)
+
+	response.Ok(c, &dto.ReloadRes{
+		File:            cfg.File,
+		Interval:        cfg.Ingest.Interval.String(),
+		Timeout:         cfg.Ingest.Timeout.String(),
+		Symbols:         cfg.Ingest.Symbols,
+		Top:             cfg.Ingest.Top,
+		QuoteCurrencies: cfg.Ingest.QuoteCurrencies,
+		Providers:       cfg.Providers.Priority,
+		RestartRequired: restart,
+	}, "")
+}
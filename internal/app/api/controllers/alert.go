@@ -0,0 +1,172 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+type AlertController struct {
+	logger  *slog.Logger
+	service service.AlertService
+}
+
+func NewAlertController(logger *slog.Logger, svc service.AlertService) *AlertController {
+	return &AlertController{
+		logger:  logger.With("layer", "AlertController"),
+		service: svc,
+	}
+}
+
+// CreateRule godoc
+// @Summary Create an alert rule
+// @Description Rules are evaluated after every ingested batch and fire when their condition starts to hold.
+// @Description The first evaluation only records the state, so a condition that already holds at creation doesn't fire until it stopped holding in between.
+// @Description Conditions:
+// @Description `crosses_above`/`crosses_below` compare the price with `threshold`, `change` compares the absolute percentage change over `window` with it.
+// @Description Events are posted as dto.AlertEvent to `webhook_url`, signed with HMAC-SHA256 in X-Price-Signature over "<X-Price-Timestamp>.<body>", and retried with backoff.
+// @Tags alerts
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param request body dto.AlertRuleReq true "Rule"
+// @Success 201 {object} response.Response[dto.AlertRuleRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 409 {object} response.Response[any] "alerts.signing_secret is not set"
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/alerts [post]
+func (ac *AlertController) CreateRule(c *gin.Context) {
+	req := &dto.AlertRuleReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		response.BadRequest(c, "invalid body: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	rule, err := ac.service.CreateRule(ctx, req)
+	if err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Custom(c, http.StatusCreated, rule, "")
+}
+
+// ListRules godoc
+// @Summary List alert rules
+// @Tags alerts
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Success 200 {object} response.Response[[]dto.AlertRuleRes]
+// @Failure 401 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/alerts [get]
+func (ac *AlertController) ListRules(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	rules, err := ac.service.ListRules(ctx)
+	if err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Ok(c, rules, "")
+}
+
+// DeleteRule godoc
+// @Summary Delete an alert rule
+// @Description Deletes the rule and its delivery log.
+// @Tags alerts
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param id path int true "Rule id"
+// @Success 200 {object} response.Response[any]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/alerts/{id} [delete]
+func (ac *AlertController) DeleteRule(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid rule id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := ac.service.DeleteRule(ctx, id); err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Ok(c, nil, "deleted")
+}
+
+// ListDeliveries godoc
+// @Summary List webhook deliveries of a rule
+// @Description Returns every delivery attempt, newest first.
+// @Tags alerts
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param id path int true "Rule id"
+// @Param limit query int false "Attempts to return (default 50, max 500)"
+// @Success 200 {object} response.Response[[]dto.AlertDeliveryRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/alerts/{id}/deliveries [get]
+func (ac *AlertController) ListDeliveries(c *gin.Context) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid rule id")
+		return
+	}
+	limit := 0
+	if l := c.Query("limit"); l != "" {
+		if limit, err = strconv.Atoi(l); err != nil {
+			response.BadRequest(c, "invalid limit")
+			return
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	deliveries, err := ac.service.ListDeliveries(ctx, id, limit)
+	if err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Ok(c, deliveries, "")
+}
+
+func (ac *AlertController) httpError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Custom(c, http.StatusGatewayTimeout, nil, "upstream service timed out")
+	case errors.Is(err, context.Canceled):
+		response.Custom(c, http.StatusRequestTimeout, nil, "request was canceled by client")
+	case errors.Is(err, alert.ErrRuleNotFound):
+		response.NotFound(c)
+	case errors.Is(err, service.ErrAlertsDisabled):
+		response.Custom(c, http.StatusConflict, nil, err.Error())
+	case errors.Is(err, service.ErrInvalidRequest):
+		response.BadRequest(c, err.Error())
+	default:
+		ac.logger.Error("internal server error", "error", err)
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,133 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/repository/repository/anomaly"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+type AnomalyController struct {
+	logger  *slog.Logger
+	service service.AnomalyService
+}
+
+func NewAnomalyController(logger *slog.Logger, svc service.AnomalyService) *AnomalyController {
+	return &AnomalyController{
+		logger:  logger.With("layer", "AnomalyController"),
+		service: svc,
+	}
+}
+
+// ListAnomalies godoc
+// @Summary List anomalous ticks
+// @Description Ticks that moved more than anomaly.max_change_pct from the last stored price, or whose return was more than anomaly.z_score standard deviations from the recent returns, newest first.
+// @Description Flagged ticks were stored as usual, quarantined ones wait for approval.
+// @Tags anomalies
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param status query string false "pending (default), approved, rejected or all"
+// @Param symbol query string false "Symbol, e.g. btc"
+// @Param limit query int false "Anomalies to return (default 50, max 500)"
+// @Success 200 {object} response.Response[[]dto.AnomalyRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/anomalies [get]
+func (ac *AnomalyController) ListAnomalies(c *gin.Context) {
+	req := &dto.AnomaliesReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	anomalies, err := ac.service.ListAnomalies(ctx, req)
+	if err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Ok(c, anomalies, "")
+}
+
+// Approve godoc
+// @Summary Approve an anomalous tick
+// @Description Stores a quarantined tick with its market data; a flagged tick is already stored and only marked approved.
+// @Tags anomalies
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param id path int true "Anomaly id"
+// @Success 200 {object} response.Response[dto.AnomalyRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 409 {object} response.Response[any] "already reviewed"
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/anomalies/{id}/approve [post]
+func (ac *AnomalyController) Approve(c *gin.Context) {
+	ac.review(c, ac.service.Approve)
+}
+
+// Reject godoc
+// @Summary Reject an anomalous tick
+// @Description Removes a flagged tick and its market data from the stored prices; a quarantined tick is only marked rejected.
+// @Tags anomalies
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param id path int true "Anomaly id"
+// @Success 200 {object} response.Response[dto.AnomalyRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 409 {object} response.Response[any] "already reviewed"
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/anomalies/{id}/reject [post]
+func (ac *AnomalyController) Reject(c *gin.Context) {
+	ac.review(c, ac.service.Reject)
+}
+
+func (ac *AnomalyController) review(c *gin.Context, decide func(context.Context, int64) (*dto.AnomalyRes, error)) {
+	id, err := strconv.ParseInt(c.Param("id"), 10, 64)
+	if err != nil {
+		response.BadRequest(c, "invalid anomaly id")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	res, err := decide(ctx, id)
+	if err != nil {
+		ac.httpError(err, c)
+		return
+	}
+	response.Ok(c, res, "")
+}
+
+func (ac *AnomalyController) httpError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Custom(c, http.StatusGatewayTimeout, nil, "upstream service timed out")
+	case errors.Is(err, context.Canceled):
+		response.Custom(c, http.StatusRequestTimeout, nil, "request was canceled by client")
+	case errors.Is(err, anomaly.ErrAnomalyNotFound):
+		response.NotFound(c)
+	case errors.Is(err, anomaly.ErrAlreadyReviewed):
+		response.Custom(c, http.StatusConflict, nil, err.Error())
+	case errors.Is(err, service.ErrInvalidRequest):
+		response.BadRequest(c, err.Error())
+	default:
+		ac.logger.Error("internal server error", "error", err)
+		response.InternalError(c)
+	}
+}
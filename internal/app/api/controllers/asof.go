@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+)
+
+// GetAsOf godoc
+// @Summary Get prices as of given times
+// @Description Returns the price of every symbol in effect at every requested time, i.e. the last one recorded at or before it.
+// @Description A price recorded more than `max_staleness` earlier is reported with status `stale` and no price; `missing` means there is no earlier price at all.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param symbols query string true "Comma separated symbols (e.g., btc,eth)"
+// @Param at query string false "Comma separated times, unix seconds or RFC 3339 (default now)"
+// @Param max_staleness query string false "Oldest acceptable price before each time (e.g., 15m, 2d), default from the config"
+// @Success 200 {object} response.Response[[]dto.AsOfRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/asof [get]
+func (pc *PriceController) GetAsOf(c *gin.Context) {
+	req := &dto.AsOfReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	res, err := pc.service.GetAsOf(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to get as-of prices", "error", err, "symbols", req.Symbols)
+		pc.httpError(err, c)
+		return
+	}
+
+	response.Ok(c, res, "")
+}
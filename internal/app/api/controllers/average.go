@@ -0,0 +1,89 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+// GetTWAP godoc
+// @Summary Get the time-weighted average price
+// @Description Averages the price over a window, weighting every tick by how long it held until the next one.
+// @Description The last tick before `from` counts for the start of the window.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param from query int false "Start time (unix timestamp, default to - 24h)"
+// @Param to query int false "End time (unix timestamp, default now)"
+// @Success 200 {object} response.Response[dto.AverageRes]
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/twap [get]
+func (pc *PriceController) GetTWAP(c *gin.Context) {
+	pc.average(c, "twap", pc.service.GetTWAP)
+}
+
+// GetVWAP godoc
+// @Summary Get a volume-proxy weighted average price
+// @Description Averages the price over a window, weighting every tick by the time it held times the rolling 24h volume reported with it.
+// @Description This is not a true VWAP: providers don't report the volume traded at each price, so the 24h volume stands in for the trading rate.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param from query int false "Start time (unix timestamp, default to - 24h)"
+// @Param to query int false "End time (unix timestamp, default now)"
+// @Success 200 {object} response.Response[dto.AverageRes]
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any] "no price or no volume in the window"
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/vwap [get]
+func (pc *PriceController) GetVWAP(c *gin.Context) {
+	pc.average(c, "vwap", pc.service.GetVWAP)
+}
+
+func (pc *PriceController) average(c *gin.Context, method string, compute func(context.Context, *dto.AverageReq) (*dto.AverageRes, error)) {
+	req := &dto.AverageReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - 86400
+	}
+	if err := service.ValidateAverage(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, req.To) {
+		return
+	}
+
+	res, err := compute(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to compute average", "error", err, "method", method, "symbol", req.Symbol)
+		pc.httpError(err, c)
+		return
+	}
+
+	response.Ok(c, res, "")
+}
@@ -0,0 +1,148 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/repository/repository/basket"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+type BasketController struct {
+	logger  *slog.Logger
+	service service.BasketService
+}
+
+func NewBasketController(logger *slog.Logger, svc service.BasketService) *BasketController {
+	return &BasketController{
+		logger:  logger.With("layer", "BasketController"),
+		service: svc,
+	}
+}
+
+// ListBaskets godoc
+// @Summary List baskets
+// @Description Lists the weighted baskets. Their values are stored as prices under the basket name on every ingestion tick,
+// @Description so /prices/latest, /prices/history and the other price endpoints accept a basket name as the symbol.
+// @Tags baskets
+// @Produce json
+// @Success 200 {object} response.Response[[]dto.BasketRes]
+// @Failure 500 {object} response.Response[any]
+// @Router /baskets [get]
+func (bc *BasketController) ListBaskets(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	baskets, err := bc.service.ListBaskets(ctx)
+	if err != nil {
+		bc.httpError(err, c)
+		return
+	}
+	response.Ok(c, baskets, "")
+}
+
+// GetBasket godoc
+// @Summary Get a basket
+// @Tags baskets
+// @Produce json
+// @Param name path string true "Basket name"
+// @Success 200 {object} response.Response[dto.BasketRes]
+// @Failure 404 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /baskets/{name} [get]
+func (bc *BasketController) GetBasket(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	b, err := bc.service.GetBasket(ctx, c.Param("name"))
+	if err != nil {
+		bc.httpError(err, c)
+		return
+	}
+	response.Ok(c, b, "")
+}
+
+// SaveBasket godoc
+// @Summary Create or rebalance a basket
+// @Description Fixes the units of every component so that each holds its weight of the basket value at the latest prices.
+// @Description A new basket starts at `base_value` (default 100), an existing one is rebalanced at its current value.
+// @Description The name can't be one any coin is stored under, or one of a deleted basket.
+// @Tags baskets
+// @Accept json
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param name path string true "Basket name, up to 16 lowercase letters, digits and dashes"
+// @Param request body dto.BasketReq true "Components and weights"
+// @Success 200 {object} response.Response[dto.BasketRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any] "a component, or the basket being rebalanced, has no recent price"
+// @Failure 409 {object} response.Response[any] "the name is taken by another symbol"
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/baskets/{name} [put]
+func (bc *BasketController) SaveBasket(c *gin.Context) {
+	req := &dto.BasketReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		response.BadRequest(c, "invalid body: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	b, err := bc.service.SaveBasket(ctx, c.Param("name"), req)
+	if err != nil {
+		bc.httpError(err, c)
+		return
+	}
+	response.Ok(c, b, "")
+}
+
+// DeleteBasket godoc
+// @Summary Delete a basket
+// @Description Stops valuing the basket. Its stored prices are kept and its name stays reserved.
+// @Tags baskets
+// @Produce json
+// @Param Authorization header string true "Bearer ADMIN_TOKEN"
+// @Param name path string true "Basket name"
+// @Success 200 {object} response.Response[any]
+// @Failure 401 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /admin/baskets/{name} [delete]
+func (bc *BasketController) DeleteBasket(c *gin.Context) {
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	if err := bc.service.DeleteBasket(ctx, c.Param("name")); err != nil {
+		bc.httpError(err, c)
+		return
+	}
+	response.Ok(c, nil, "deleted")
+}
+
+func (bc *BasketController) httpError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Custom(c, http.StatusGatewayTimeout, nil, "upstream service timed out")
+	case errors.Is(err, context.Canceled):
+		response.Custom(c, http.StatusRequestTimeout, nil, "request was canceled by client")
+	case errors.Is(err, basket.ErrBasketNotFound):
+		response.NotFound(c)
+	case errors.Is(err, service.ErrStalePrice):
+		response.Custom(c, http.StatusNotFound, nil, err.Error())
+	case errors.Is(err, basket.ErrNameTaken):
+		response.Custom(c, http.StatusConflict, nil, err.Error())
+	case errors.Is(err, service.ErrInvalidRequest):
+		response.BadRequest(c, err.Error())
+	default:
+		bc.logger.Error("internal server error", "error", err)
+		response.InternalError(c)
+	}
+}
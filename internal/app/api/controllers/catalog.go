@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"errors"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+type CatalogController struct {
+	logger  *slog.Logger
+	service service.CatalogService
+}
+
+func NewCatalogController(logger *slog.Logger, svc service.CatalogService) *CatalogController {
+	return &CatalogController{
+		logger:  logger.With("layer", "CatalogController"),
+		service: svc,
+	}
+}
+
+// ListSymbols godoc
+// @Summary List symbols
+// @Description Lists every symbol with stored prices: its name and provider ids, the first and last stored tick, the row count
+// @Description and whether the latest ingestion tick included it. The row count includes rows dropped by raw retention since.
+// @Tags symbols
+// @Produce json
+// @Param ingesting query bool false "Only symbols the latest ingestion tick did (true) or didn't (false) include"
+// @Success 200 {object} response.Response[[]dto.SymbolRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /symbols [get]
+func (cc *CatalogController) ListSymbols(c *gin.Context) {
+	req := &dto.SymbolsReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query parameters")
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	symbols, err := cc.service.ListSymbols(ctx, req)
+	if err != nil {
+		cc.httpError(err, c)
+		return
+	}
+	response.Ok(c, symbols, "")
+}
+
+func (cc *CatalogController) httpError(err error, c *gin.Context) {
+	switch {
+	case errors.Is(err, context.DeadlineExceeded):
+		response.Custom(c, http.StatusGatewayTimeout, nil, "upstream service timed out")
+	case errors.Is(err, context.Canceled):
+		response.Custom(c, http.StatusRequestTimeout, nil, "request was canceled by client")
+	default:
+		cc.logger.Error("internal server error", "error", err)
+		response.InternalError(c)
+	}
+}
@@ -0,0 +1,50 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+)
+
+// Convert godoc
+// @Summary Convert an amount between currencies
+// @Description Uses the stored from-to pair when there is one, otherwise the inverse of to-from, otherwise crosses the USD prices of both.
+// @Description Prices are the ones in effect at `at`, or the latest ones; `legs` lists each price used and when it was recorded.
+// @Description A pair priced more than `max_staleness` before `at` is passed over; when only stale prices are left the conversion fails with 404.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param from query string true "Currency to convert from (e.g., eth)"
+// @Param to query string true "Currency to convert to (e.g., btc, usd)"
+// @Param amount query string false "Amount to convert (default 1)"
+// @Param at query int false "Convert at this time (unix timestamp, default now)"
+// @Param max_staleness query string false "Oldest acceptable price before at (e.g., 15m, 2d), default from the config"
+// @Success 200 {object} response.Response[dto.ConvertRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any] "no rate, or only stale ones"
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /convert [get]
+func (pc *PriceController) Convert(c *gin.Context) {
+	req := &dto.ConvertReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
+	defer cancel()
+
+	res, err := pc.service.Convert(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to convert", "error", err, "from", req.From, "to", req.To)
+		pc.httpError(err, c)
+		return
+	}
+
+	response.Ok(c, res, "")
+}
@@ -3,31 +3,39 @@ package controller
 import (
 	"context"
 	"errors"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/providers/currency"
 	"github.com/milad-rasouli/price/internal/service"
 	"log/slog"
 	"net/http"
-	"time"
 
 	"github.com/gin-gonic/gin"
 	"github.com/milad-rasouli/price/internal/app/api/response"
 )
 
+// IntervalHeader carries the live ingestion interval on every update-prices
+// response, so the cron process follows reloads of the API
+const IntervalHeader = "X-Ingest-Interval"
+
 type CronController struct {
 	logger  *slog.Logger
 	service service.PriceService
+	store   *config.Store
 }
 
-func NewCronController(logger *slog.Logger, svc service.PriceService) *CronController {
+func NewCronController(logger *slog.Logger, store *config.Store, svc service.PriceService) *CronController {
 	return &CronController{
 		logger:  logger.With("layer", "CronController"),
 		service: svc,
+		store:   store,
 	}
 }
 
 func (pc *CronController) UpdatePrice(c *gin.Context) {
+	ingest := pc.store.Current().Ingest
+	c.Header(IntervalHeader, ingest.Interval.String())
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 30*time.Second)
+	ctx, cancel := context.WithTimeout(c.Request.Context(), ingest.Timeout.Duration)
 	defer cancel()
 
 	err := pc.service.InsertBatch(ctx)
@@ -47,7 +47,7 @@ func (hc *HealthController) Liveness(c *gin.Context) {
 
 // Readiness godoc
 // @Summary Readiness probe
-// @Description Verifies if dependencies (e.g., PostgreSQL) are healthy and service can handle requests.
+// @Description Verifies if dependencies (e.g., PostgreSQL) are healthy, the database schema matches this build and service can handle requests.
 // @Tags health
 // @Produce json
 // @Success 200 {object} response.Response[any] "Service is ready"
@@ -65,6 +65,12 @@ func (hc *HealthController) Readiness(c *gin.Context) {
 		return
 	}
 
+	if err := hc.pg.CheckSchema(ctx); err != nil {
+		lg.Error("schema check failed", "error", err)
+		response.Custom(c, http.StatusServiceUnavailable, nil, "database schema is outdated")
+		return
+	}
+
 	hc.lastReady = time.Now()
 	response.Ok(c, nil, "")
 }
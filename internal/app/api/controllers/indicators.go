@@ -0,0 +1,67 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+// GetIndicators godoc
+// @Summary Get technical indicators
+// @Description Computes SMA, EMA, RSI, Bollinger bands and MACD over the closing price of every bucket, as returned by /prices/history.
+// @Description `indicators` is a comma separated list of name[:params]: sma:period, ema:period, rsi:period, bollinger:period:width and macd:fast:slow:signal.
+// @Description Periods are between 1 and 200. History before `from` is read automatically so the first point is already warmed up.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param interval query string false "Interval (e.g., 5m, 1h, 1d), default 1h"
+// @Param from query int false "Start time (unix timestamp, default to - 24h)"
+// @Param to query int false "End time (unix timestamp, default now)"
+// @Param indicators query string false "Indicators (default sma:20,ema:20,rsi:14,bollinger:20:2,macd:12:26:9)"
+// @Success 200 {object} response.Response[dto.IndicatorsRes]
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/indicators [get]
+func (pc *PriceController) GetIndicators(c *gin.Context) {
+	req := &dto.IndicatorsReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - 86400
+	}
+	if err := service.ValidateIndicators(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, historyEnd(req.Interval, req.To)) {
+		return
+	}
+
+	res, err := pc.service.GetIndicators(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to compute indicators", "error", err, "symbol", req.Symbol)
+		pc.httpError(err, c)
+		return
+	}
+
+	pc.logger.Info("indicators computed", "symbol", req.Symbol, "interval", req.Interval, "count", len(res.Points))
+	response.Ok(c, res, "")
+}
@@ -0,0 +1,46 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+)
+
+// ValuePortfolio godoc
+// @Summary Value a portfolio
+// @Description Values every holding at `at` (default now) and at `since` (default 24h earlier) from stored prices,
+// @Description with each asset's weight and the profit and loss between the two times.
+// @Description Every holding needs a price within `max_staleness` of both times, otherwise the request fails with 404.
+// @Tags portfolio
+// @Accept json
+// @Produce json
+// @Param request body dto.PortfolioReq true "Holdings to value"
+// @Success 200 {object} response.Response[dto.PortfolioRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any] "a holding has no recent price"
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /portfolio/value [post]
+func (pc *PriceController) ValuePortfolio(c *gin.Context) {
+	req := &dto.PortfolioReq{}
+	if err := c.ShouldBindJSON(req); err != nil {
+		response.BadRequest(c, "invalid body: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	res, err := pc.service.ValuePortfolio(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to value portfolio", "error", err, "holdings", len(req.Holdings))
+		pc.httpError(err, c)
+		return
+	}
+
+	response.Ok(c, res, "")
+}
@@ -4,9 +4,11 @@ import (
 	"context"
 	"errors"
 	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/service"
 	"log/slog"
 	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/gin-gonic/gin"
@@ -14,29 +16,82 @@ import (
 	"github.com/milad-rasouli/price/internal/repository/repository/price"
 )
 
+// exportTimeout bounds streamed CSV/NDJSON exports, which may cover years of data
+const exportTimeout = 5 * time.Minute
+
 type PriceController struct {
 	logger  *slog.Logger
 	service service.PriceService
+	store   *config.Store
 }
 
-func NewPriceController(logger *slog.Logger, svc service.PriceService) *PriceController {
+func NewPriceController(logger *slog.Logger, store *config.Store, svc service.PriceService) *PriceController {
 	return &PriceController{
 		logger:  logger.With("layer", "PriceController"),
 		service: svc,
+		store:   store,
 	}
 }
 
+// maxAge is how long clients may cache prices, which only change once per ingestion tick
+func (pc *PriceController) maxAge() time.Duration {
+	return pc.store.Current().Ingest.Interval.Duration
+}
+
+// conditional sets the caching headers from the data version of symbol up to
+// to and answers conditional requests. It returns true when a response has
+// been written, which is also the case when the version lookup failed.
+//
+// The ETag covers the query as the client sent it, not the defaults resolved
+// from it, so a window ending now keeps its ETag until the data changes. Such a
+// rolling window moves with the clock without the data changing, so it gets no
+// Last-Modified to be compared with If-Modified-Since.
+func (pc *PriceController) conditional(ctx context.Context, c *gin.Context, symbol string, to int64, etagParts ...any) bool {
+	version, err := pc.service.GetVersion(ctx, symbol, to)
+	if err != nil {
+		pc.logger.Error("failed to get data version", "error", err, "symbol", symbol)
+		pc.httpError(err, c)
+		return true
+	}
+	parts := append([]any{c.FullPath(), c.Request.URL.Query().Encode()}, etagParts...)
+	etag := response.ETag(append(parts, version.LastTime, version.Revision)...)
+
+	var lastModified time.Time
+	if explicit, _ := strconv.ParseInt(c.Query("to"), 10, 64); explicit != 0 {
+		lastModified = time.Unix(max(version.LastTime, version.RevisedAt), 0)
+	}
+	return response.Cacheable(c, etag, lastModified, pc.maxAge())
+}
+
+// historyEnd is the last second the buckets of a validated history request
+// cover, which is past to unless to ends a bucket
+func historyEnd(interval string, to int64) int64 {
+	if interval == "" {
+		interval = price.DefaultInterval
+	}
+	d, _ := price.ParseInterval(interval)
+	_, end := price.Span(d, to, to)
+	return end - 1
+}
+
 // GetHistory godoc
 // @Summary Get historical cryptocurrency prices
 // @Description Returns historical price data for a given symbol within a time range, optionally grouped by interval.
+// @Description Every interval bucket overlapping the range is returned whole, so edge buckets may hold data outside it.
+// @Description Send `Accept: text/csv` or `Accept: application/x-ndjson` to stream the rows as an export.
 // @Tags prices
 // @Accept json
 // @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
 // @Param symbol query string true "Symbol (e.g., btc, eth)"
-// @Param interval query string false "Interval (e.g., 1m, 5m, 1h, 1d)"
+// @Param interval query string false "Interval (e.g., 30s, 1m, 5m, 1h, 1d, 1w)"
 // @Param from query int false "Start time (unix timestamp)"
 // @Param to query int false "End time (unix timestamp)"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response, only sent when to is given"
 // @Success 200 {object} response.Response[[]dto.HistoryRes]
+// @Success 304 "Not Modified"
 // @Failure 400 {object} response.Response[any]
 // @Failure 408 {object} response.Response[any]
 // @Failure 504 {object} response.Response[any]
@@ -51,16 +106,38 @@ func (pc *PriceController) GetHistory(c *gin.Context) {
 
 	// TODO: better validation using go-playground/validator
 
-	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	format := c.NegotiateFormat(response.MIMEJSON, response.MIMECSV, response.MIMENDJSON)
+	if format == "" {
+		response.Custom(c, http.StatusNotAcceptable, nil, "supported formats: application/json, text/csv, application/x-ndjson")
+		return
+	}
+	c.Header("Vary", "Accept")
+
+	timeout := 10 * time.Second
+	if format != response.MIMEJSON {
+		timeout = exportTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
 	defer cancel()
 
-	now := time.Now().Unix()
 	if req.To == 0 {
-		req.To = now
+		req.To = time.Now().Unix()
 	}
 	if req.From == 0 {
 		req.From = req.To - 86400
 	}
+	if err := service.ValidateHistory(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, historyEnd(req.Interval, req.To), format) {
+		return
+	}
+
+	if format != response.MIMEJSON {
+		pc.streamHistory(ctx, c, req, format)
+		return
+	}
 
 	history, err := pc.service.GetHistory(ctx, req)
 	if err != nil {
@@ -73,14 +150,158 @@ func (pc *PriceController) GetHistory(c *gin.Context) {
 	response.Ok(c, history, "")
 }
 
+// GetMarketHistory godoc
+// @Summary Get historical market data
+// @Description Returns market cap, 24h volume, circulating supply and 24h high/low for a symbol, one point per interval holding the values last reported within it.
+// @Description Every interval bucket overlapping the range is returned whole, as in /prices/history.
+// @Description Send `Accept: text/csv` or `Accept: application/x-ndjson` to stream the rows as an export.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param interval query string false "Interval (e.g., 30s, 1m, 5m, 1h, 1d, 1w)"
+// @Param from query int false "Start time (unix timestamp)"
+// @Param to query int false "End time (unix timestamp)"
+// @Param If-None-Match header string false "ETag from a previous response"
+// @Param If-Modified-Since header string false "Last-Modified from a previous response, only sent when to is given"
+// @Success 200 {object} response.Response[[]dto.MarketHistoryRes]
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/market/history [get]
+func (pc *PriceController) GetMarketHistory(c *gin.Context) {
+	req := &dto.HistoryReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	format := c.NegotiateFormat(response.MIMEJSON, response.MIMECSV, response.MIMENDJSON)
+	if format == "" {
+		response.Custom(c, http.StatusNotAcceptable, nil, "supported formats: application/json, text/csv, application/x-ndjson")
+		return
+	}
+	c.Header("Vary", "Accept")
+
+	timeout := 10 * time.Second
+	if format != response.MIMEJSON {
+		timeout = exportTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - 86400
+	}
+	if err := service.ValidateHistory(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, historyEnd(req.Interval, req.To), format) {
+		return
+	}
+
+	if format != response.MIMEJSON {
+		pc.streamMarketHistory(ctx, c, req, format)
+		return
+	}
+
+	history, err := pc.service.GetMarketHistory(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to get market history", "error", err, "symbol", req.Symbol)
+		pc.httpError(err, c)
+		return
+	}
+
+	pc.logger.Info("market history fetched", "symbol", req.Symbol, "interval", req.Interval, "count", len(history))
+	response.Ok(c, history, "")
+}
+
+// GetTicks godoc
+// @Summary Get raw cryptocurrency price ticks
+// @Description Returns the raw stored rows for a symbol within a time range, paginated with an opaque cursor.
+// @Description Pass `next_cursor` from a response as `cursor` to fetch the next page.
+// @Description Send `Accept: text/csv` or `Accept: application/x-ndjson` to stream every row in the range as an export.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Produce text/csv
+// @Produce application/x-ndjson
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param from query int false "Start time (unix timestamp)"
+// @Param to query int false "End time (unix timestamp)"
+// @Param limit query int false "Page size (default 100, max 1000)"
+// @Param order query string false "Sort order by time" Enums(asc, desc)
+// @Param cursor query string false "Cursor from a previous page"
+// @Success 200 {object} response.Response[dto.TicksRes]
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/ticks [get]
+func (pc *PriceController) GetTicks(c *gin.Context) {
+	req := &dto.TicksReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	format := c.NegotiateFormat(response.MIMEJSON, response.MIMECSV, response.MIMENDJSON)
+	if format == "" {
+		response.Custom(c, http.StatusNotAcceptable, nil, "supported formats: application/json, text/csv, application/x-ndjson")
+		return
+	}
+
+	timeout := 10 * time.Second
+	if format != response.MIMEJSON {
+		timeout = exportTimeout
+	}
+	ctx, cancel := context.WithTimeout(c.Request.Context(), timeout)
+	defer cancel()
+
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - 86400
+	}
+
+	if format != response.MIMEJSON {
+		pc.streamTicks(ctx, c, req, format)
+		return
+	}
+
+	ticks, err := pc.service.GetTicks(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to get ticks", "error", err, "symbol", req.Symbol)
+		pc.httpError(err, c)
+		return
+	}
+
+	response.Ok(c, ticks, "")
+}
+
 // GetLatest godoc
 // @Summary Get latest cryptocurrency price
-// @Description Returns the latest stored price for a given symbol, including 24h change.
+// @Description Returns the latest stored price for a given symbol, its change over each requested window and the market data reported with it.
+// @Description Every change is relative to the price in effect when the window started, returned as `reference`.
 // @Tags prices
 // @Accept json
 // @Produce json
 // @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param windows query string false "Comma separated intervals or ytd (default 1h,24h,7d,30d,ytd)"
+// @Param If-None-Match header string false "ETag from a previous response"
 // @Success 200 {object} response.Response[dto.LatestRes]
+// @Success 304 "Not Modified"
 // @Failure 400 {object} response.Response[any]
 // @Failure 408 {object} response.Response[any]
 // @Failure 504 {object} response.Response[any]
@@ -97,6 +318,14 @@ func (pc *PriceController) GetLatest(c *gin.Context) {
 	ctx, cancel := context.WithTimeout(c.Request.Context(), 5*time.Second)
 	defer cancel()
 
+	if err := service.ValidateLatest(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, time.Now().Unix()) {
+		return
+	}
+
 	latest, err := pc.service.GetLatest(ctx, req)
 	if err != nil {
 		pc.logger.Error("failed to get latest price", "error", err, "symbol", req.Symbol)
@@ -107,6 +336,60 @@ func (pc *PriceController) GetLatest(c *gin.Context) {
 	response.Ok(c, latest, "")
 }
 
+func (pc *PriceController) streamHistory(ctx context.Context, c *gin.Context, req *dto.HistoryReq, format string) {
+	stream := response.NewStream(c, format, req.Symbol+"-history", dto.HistoryCSVHeader)
+	err := pc.service.StreamHistory(ctx, req, func(point *dto.HistoryRes) error {
+		return stream.Write(point)
+	})
+	if err == nil {
+		err = stream.Flush()
+	}
+	if err != nil {
+		pc.logger.Error("failed to stream history", "error", err, "symbol", req.Symbol)
+		if !stream.Started() {
+			pc.httpError(err, c)
+		}
+		return
+	}
+	pc.logger.Info("history exported", "symbol", req.Symbol, "interval", req.Interval, "format", format)
+}
+
+func (pc *PriceController) streamMarketHistory(ctx context.Context, c *gin.Context, req *dto.HistoryReq, format string) {
+	stream := response.NewStream(c, format, req.Symbol+"-market-history", dto.MarketHistoryCSVHeader)
+	err := pc.service.StreamMarketHistory(ctx, req, func(point *dto.MarketHistoryRes) error {
+		return stream.Write(point)
+	})
+	if err == nil {
+		err = stream.Flush()
+	}
+	if err != nil {
+		pc.logger.Error("failed to stream market history", "error", err, "symbol", req.Symbol)
+		if !stream.Started() {
+			pc.httpError(err, c)
+		}
+		return
+	}
+	pc.logger.Info("market history exported", "symbol", req.Symbol, "interval", req.Interval, "format", format)
+}
+
+func (pc *PriceController) streamTicks(ctx context.Context, c *gin.Context, req *dto.TicksReq, format string) {
+	stream := response.NewStream(c, format, req.Symbol+"-ticks", dto.TickCSVHeader)
+	err := pc.service.StreamTicks(ctx, req, func(tick *dto.TickRes) error {
+		return stream.Write(tick)
+	})
+	if err == nil {
+		err = stream.Flush()
+	}
+	if err != nil {
+		pc.logger.Error("failed to stream ticks", "error", err, "symbol", req.Symbol)
+		if !stream.Started() {
+			pc.httpError(err, c)
+		}
+		return
+	}
+	pc.logger.Info("ticks exported", "symbol", req.Symbol, "format", format)
+}
+
 func (pc *PriceController) httpError(err error, c *gin.Context) {
 	switch {
 	case errors.Is(err, context.DeadlineExceeded):
@@ -117,6 +400,10 @@ func (pc *PriceController) httpError(err error, c *gin.Context) {
 		response.Custom(c, http.StatusRequestTimeout, nil, "request was canceled by client")
 	case errors.Is(err, price.ErrPriceNotFound):
 		response.NotFound(c)
+	case errors.Is(err, price.ErrVolumeNotFound), errors.Is(err, service.ErrStalePrice):
+		response.Custom(c, http.StatusNotFound, nil, err.Error())
+	case errors.Is(err, service.ErrInvalidRequest), errors.Is(err, price.ErrInvalidInterval):
+		response.BadRequest(c, err.Error())
 	default:
 		pc.logger.Error("internal server error", "error", err)
 		response.InternalError(c)
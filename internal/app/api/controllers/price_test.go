@@ -0,0 +1,132 @@
+package controller
+
+import (
+	"context"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+// versionedPrices serves an empty history at a fixed data version
+type versionedPrices struct {
+	service.PriceService
+	version entity.DataVersion
+}
+
+func (s *versionedPrices) GetVersion(ctx context.Context, symbol string, to int64) (*entity.DataVersion, error) {
+	v := s.version
+	return &v, nil
+}
+
+func (s *versionedPrices) GetHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.HistoryRes, error) {
+	return []*dto.HistoryRes{}, nil
+}
+
+func (s *versionedPrices) StreamHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.HistoryRes) error) error {
+	return nil
+}
+
+func newHistoryRouter(svc service.PriceService) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+	cfg := &config.Config{}
+	cfg.Ingest.Interval = config.Duration{Duration: time.Minute}
+	pc := NewPriceController(slog.New(slog.NewTextHandler(io.Discard, nil)), config.NewStore(cfg), svc)
+
+	r := gin.New()
+	r.GET("/prices/history", pc.GetHistory)
+	return r
+}
+
+func get(r http.Handler, target string, headers map[string]string) *httptest.ResponseRecorder {
+	req := httptest.NewRequest(http.MethodGet, target, nil)
+	for k, v := range headers {
+		req.Header.Set(k, v)
+	}
+	w := httptest.NewRecorder()
+	r.ServeHTTP(w, req)
+	return w
+}
+
+// TestConditionalRollingWindow covers a history request without to: its range
+// resolves to a new second on every request, which must not change the ETag,
+// and it moves without the data changing, so If-Modified-Since can't answer it
+func TestConditionalRollingWindow(t *testing.T) {
+	svc := &versionedPrices{version: entity.DataVersion{LastTime: 1735689600, Revision: 1}}
+	r := newHistoryRouter(svc)
+	const target = "/prices/history?symbol=btc&interval=1h"
+
+	first := get(r, target, nil)
+	if first.Code != http.StatusOK {
+		t.Fatalf("status = %d, want 200", first.Code)
+	}
+	etag := first.Header().Get("ETag")
+	if lm := first.Header().Get("Last-Modified"); lm != "" {
+		t.Errorf("Last-Modified = %q on a rolling window", lm)
+	}
+
+	time.Sleep(time.Until(time.Now().Truncate(time.Second).Add(time.Second)))
+	if w := get(r, target, map[string]string{"If-None-Match": etag}); w.Code != http.StatusNotModified {
+		t.Errorf("unchanged data a second later: status = %d, want 304", w.Code)
+	}
+	future := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+	if w := get(r, target, map[string]string{"If-Modified-Since": future}); w.Code != http.StatusOK {
+		t.Errorf("If-Modified-Since on a rolling window: status = %d, want 200", w.Code)
+	}
+
+	svc.version.LastTime += 60
+	if w := get(r, target, map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("new data: status = %d, want 200", w.Code)
+	}
+}
+
+func TestConditionalExplicitRange(t *testing.T) {
+	svc := &versionedPrices{version: entity.DataVersion{LastTime: 1735689600, Revision: 1}}
+	r := newHistoryRouter(svc)
+	const target = "/prices/history?symbol=btc&interval=1h&from=1735603200&to=1735689600"
+
+	first := get(r, target, nil)
+	etag, lastModified := first.Header().Get("ETag"), first.Header().Get("Last-Modified")
+	if want := time.Unix(1735689600, 0).UTC().Format(http.TimeFormat); lastModified != want {
+		t.Fatalf("Last-Modified = %q, want %q", lastModified, want)
+	}
+
+	tests := []struct {
+		name    string
+		target  string
+		headers map[string]string
+		want    int
+	}{
+		{"same etag", target, map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"not modified since", target, map[string]string{"If-Modified-Since": lastModified}, http.StatusNotModified},
+		{"other range", "/prices/history?symbol=btc&interval=1h&from=1735606800&to=1735689600",
+			map[string]string{"If-None-Match": etag}, http.StatusOK},
+		{"same parameters reordered", "/prices/history?to=1735689600&from=1735603200&interval=1h&symbol=btc",
+			map[string]string{"If-None-Match": etag}, http.StatusNotModified},
+		{"other format", target, map[string]string{"If-None-Match": etag, "Accept": "text/csv"}, http.StatusOK},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := get(r, tt.target, tt.headers); w.Code != tt.want {
+				t.Errorf("status = %d, want %d", w.Code, tt.want)
+			}
+		})
+	}
+
+	// a backfill below the newest row bumps the revision and its time
+	svc.version.Revision, svc.version.RevisedAt = 2, 1735693200
+	if w := get(r, target, map[string]string{"If-None-Match": etag}); w.Code != http.StatusOK {
+		t.Errorf("revised data, same etag: status = %d, want 200", w.Code)
+	}
+	if w := get(r, target, map[string]string{"If-Modified-Since": lastModified}); w.Code != http.StatusOK {
+		t.Errorf("revised data, not modified since: status = %d, want 200", w.Code)
+	}
+}
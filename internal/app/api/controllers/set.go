@@ -6,4 +6,9 @@ var ProviderSet = wire.NewSet(
 	NewPriceController,
 	NewCronController,
 	NewHealthController,
+	NewAdminController,
+	NewBasketController,
+	NewAlertController,
+	NewAnomalyController,
+	NewCatalogController,
 )
@@ -0,0 +1,65 @@
+package controller
+
+import (
+	"context"
+	"time"
+
+	"github.com/gin-gonic/gin"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/app/api/response"
+	"github.com/milad-rasouli/price/internal/service"
+)
+
+// GetStatistics godoc
+// @Summary Get window statistics
+// @Description Summarizes the closing price of every bucket in a window, as returned by /prices/history:
+// @Description min/max, simple and log return, realized volatility of the bucket log returns and the maximum drawdown.
+// @Tags prices
+// @Accept json
+// @Produce json
+// @Param symbol query string true "Symbol (e.g., btc, eth)"
+// @Param interval query string false "Interval (e.g., 5m, 1h, 1d), default 1h"
+// @Param from query int false "Start time (unix timestamp, default to - 24h)"
+// @Param to query int false "End time (unix timestamp, default now)"
+// @Success 200 {object} response.Response[dto.StatisticsRes]
+// @Success 304 "Not Modified"
+// @Failure 400 {object} response.Response[any]
+// @Failure 404 {object} response.Response[any]
+// @Failure 408 {object} response.Response[any]
+// @Failure 504 {object} response.Response[any]
+// @Failure 500 {object} response.Response[any]
+// @Router /prices/statistics [get]
+func (pc *PriceController) GetStatistics(c *gin.Context) {
+	req := &dto.HistoryReq{}
+	if err := c.ShouldBindQuery(req); err != nil {
+		response.BadRequest(c, "invalid query params: "+err.Error())
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(c.Request.Context(), 10*time.Second)
+	defer cancel()
+
+	if req.To == 0 {
+		req.To = time.Now().Unix()
+	}
+	if req.From == 0 {
+		req.From = req.To - 86400
+	}
+	if err := service.ValidateStatistics(req); err != nil {
+		pc.httpError(err, c)
+		return
+	}
+	if pc.conditional(ctx, c, req.Symbol, historyEnd(req.Interval, req.To)) {
+		return
+	}
+
+	res, err := pc.service.GetStatistics(ctx, req)
+	if err != nil {
+		pc.logger.Error("failed to compute statistics", "error", err, "symbol", req.Symbol)
+		pc.httpError(err, c)
+		return
+	}
+
+	pc.logger.Info("statistics computed", "symbol", req.Symbol, "interval", req.Interval, "buckets", res.Buckets)
+	response.Ok(c, res, "")
+}
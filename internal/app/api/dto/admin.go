@@ -0,0 +1,13 @@
+package dto
+
+type ReloadRes struct {
+	File            string   `json:"file,omitempty"`
+	Interval        string   `json:"interval"`
+	Timeout         string   `json:"timeout"`
+	Symbols         []string `json:"symbols"`
+	Top             uint32   `json:"top"`
+	QuoteCurrencies []string `json:"quote_currencies"`
+	Providers       []string `json:"providers"`
+	// RestartRequired lists changed settings that are only read at startup
+	RestartRequired []string `json:"restart_required"`
+}
@@ -0,0 +1,52 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// AlertRuleReq defines a rule. crosses_above and crosses_below compare the
+// price with Threshold; change compares the absolute percentage change over
+// Window (e.g. "1h", "1d") with Threshold.
+type AlertRuleReq struct {
+	Symbol     string          `json:"symbol" binding:"required"`
+	Kind       string          `json:"kind" binding:"required"`
+	Threshold  decimal.Decimal `json:"threshold" swaggertype:"string"`
+	Window     string          `json:"window"`
+	WebhookURL string          `json:"webhook_url" binding:"required"`
+}
+
+type AlertRuleRes struct {
+	ID              int64           `json:"id"`
+	Symbol          string          `json:"symbol"`
+	Kind            string          `json:"kind"`
+	Threshold       decimal.Decimal `json:"threshold"`
+	WindowSeconds   int64           `json:"window_seconds,omitempty"`
+	WebhookURL      string          `json:"webhook_url"`
+	Active          *bool           `json:"active"` // null until the rule is first evaluated
+	CreatedAt       int64           `json:"created_at"`
+	LastTriggeredAt *int64          `json:"last_triggered_at"`
+}
+
+type AlertDeliveryRes struct {
+	ID         int64  `json:"id"`
+	EventID    string `json:"event_id"`
+	Attempt    int    `json:"attempt"`
+	StatusCode *int   `json:"status_code"`
+	Error      string `json:"error,omitempty"`
+	Delivered  bool   `json:"delivered"`
+	Payload    string `json:"payload"`
+	Time       int64  `json:"time"`
+}
+
+// AlertEvent is the JSON body posted to a rule's webhook. Reference and
+// ChangePct are only set for change rules.
+type AlertEvent struct {
+	ID            string           `json:"id"`
+	RuleID        int64            `json:"rule_id"`
+	Symbol        string           `json:"symbol"`
+	Kind          string           `json:"kind"`
+	Threshold     decimal.Decimal  `json:"threshold"`
+	WindowSeconds int64            `json:"window_seconds,omitempty"`
+	Price         decimal.Decimal  `json:"price"`
+	Time          int64            `json:"time"`
+	Reference     *PricePoint      `json:"reference,omitempty"`
+	ChangePct     *decimal.Decimal `json:"change_pct,omitempty"`
+}
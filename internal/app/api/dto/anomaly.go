@@ -0,0 +1,27 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// AnomaliesReq filters the anomaly review list; Status defaults to pending
+type AnomaliesReq struct {
+	Status string `form:"status"` // pending, approved, rejected or all
+	Symbol string `form:"symbol"`
+	Limit  int    `form:"limit"`
+}
+
+// AnomalyRes is a tick that deviated too far from the recent prices of its
+// symbol. Flagged ticks were stored as usual; quarantined ones are only
+// stored once approved.
+type AnomalyRes struct {
+	ID         int64           `json:"id"`
+	Symbol     string          `json:"symbol"`
+	Price      decimal.Decimal `json:"price"`
+	Time       int64           `json:"time"`
+	Action     string          `json:"action"`
+	Reference  decimal.Decimal `json:"reference"` // the last stored price before the tick
+	ChangePct  decimal.Decimal `json:"change_pct"`
+	ZScore     *float64        `json:"z_score"` // null when the history was too short
+	Status     string          `json:"status"`
+	DetectedAt int64           `json:"detected_at"`
+	ReviewedAt *int64          `json:"reviewed_at"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// BasketReq defines a basket's components. Weights are relative and are
+// normalized to add up to 1. BaseValue (default 100) only applies to a new
+// basket; a saved basket is rebalanced at its current value.
+type BasketReq struct {
+	Components []BasketComponentReq `json:"components" binding:"required,min=1,dive"`
+	BaseValue  decimal.NullDecimal  `json:"base_value" swaggertype:"string"`
+}
+
+type BasketComponentReq struct {
+	Symbol string          `json:"symbol" binding:"required"`
+	Weight decimal.Decimal `json:"weight" swaggertype:"string"`
+}
+
+type BasketRes struct {
+	Name       string               `json:"name"`
+	BaseValue  decimal.Decimal      `json:"base_value"`
+	CreatedAt  int64                `json:"created_at"`
+	UpdatedAt  int64                `json:"updated_at"`
+	Components []BasketComponentRes `json:"components"`
+}
+
+// BasketComponentRes holds the weight a component had when the basket was
+// saved and the units of it the basket holds since
+type BasketComponentRes struct {
+	Symbol string          `json:"symbol"`
+	Weight decimal.Decimal `json:"weight"`
+	Units  decimal.Decimal `json:"units"`
+}
@@ -0,0 +1,21 @@
+package dto
+
+// SymbolsReq filters the symbol catalog; a nil Ingesting lists every symbol
+type SymbolsReq struct {
+	Ingesting *bool `form:"ingesting"`
+}
+
+// SymbolRes is a symbol with stored prices. Baskets are listed too, without a
+// name or provider ids, and stay listed after they are deleted since their
+// names remain reserved.
+type SymbolRes struct {
+	Symbol         string            `json:"symbol"`
+	Name           string            `json:"name"`
+	ProviderIDs    map[string]string `json:"provider_ids"` // provider -> the coin's id there
+	FirstTime      *int64            `json:"first_time"`   // null while no price is stored
+	LastTime       *int64            `json:"last_time"`
+	Rows           int64             `json:"rows"`
+	Basket         bool              `json:"basket"`
+	Ingesting      bool              `json:"ingesting"` // part of the latest ingestion tick
+	LastIngestedAt *int64            `json:"last_ingested_at"`
+}
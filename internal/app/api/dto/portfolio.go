@@ -0,0 +1,42 @@
+package dto
+
+import "github.com/shopspring/decimal"
+
+// PortfolioReq values Holdings at At (default now) and compares them with
+// Since (default 24 hours before At)
+type PortfolioReq struct {
+	Holdings     []HoldingReq `json:"holdings" binding:"required,min=1,dive"`
+	At           int64        `json:"at"`
+	Since        int64        `json:"since"`
+	MaxStaleness string       `json:"max_staleness"` // e.g. 15m or 2d, default from the config
+}
+
+type HoldingReq struct {
+	Symbol   string          `json:"symbol" binding:"required"`
+	Quantity decimal.Decimal `json:"quantity" swaggertype:"string"`
+}
+
+// PortfolioRes holds the total and per asset value at At and at Since.
+// Percentages are null when the value at Since is zero.
+type PortfolioRes struct {
+	At         int64            `json:"at"`
+	Since      int64            `json:"since"`
+	Value      decimal.Decimal  `json:"value"`
+	SinceValue decimal.Decimal  `json:"since_value"`
+	PnL        decimal.Decimal  `json:"pnl"`
+	PnLPct     *decimal.Decimal `json:"pnl_pct"`
+	Assets     []*HoldingRes    `json:"assets"`
+}
+
+// HoldingRes values one holding; Weight is its share of the portfolio value at At
+type HoldingRes struct {
+	Symbol     string           `json:"symbol"`
+	Quantity   decimal.Decimal  `json:"quantity"`
+	Price      PricePoint       `json:"price"`
+	SincePrice PricePoint       `json:"since_price"`
+	Value      decimal.Decimal  `json:"value"`
+	SinceValue decimal.Decimal  `json:"since_value"`
+	Weight     decimal.Decimal  `json:"weight"`
+	PnL        decimal.Decimal  `json:"pnl"`
+	PnLPct     *decimal.Decimal `json:"pnl_pct"`
+}
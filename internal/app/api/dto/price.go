@@ -1,6 +1,8 @@
 package dto
 
 import (
+	"strconv"
+
 	"github.com/shopspring/decimal"
 )
 
@@ -11,15 +13,88 @@ type HistoryReq struct {
 	To       int64  `form:"to"`
 }
 
-type LatestReq struct {
+type TicksReq struct {
 	Symbol string `form:"symbol" binding:"required"`
+	From   int64  `form:"from"`
+	To     int64  `form:"to"`
+	Limit  int    `form:"limit"`
+	Order  string `form:"order"` // "asc" or "desc"
+	Cursor string `form:"cursor"`
+}
+
+type AverageReq struct {
+	Symbol string `form:"symbol" binding:"required"`
+	From   int64  `form:"from"`
+	To     int64  `form:"to"`
+}
+
+// IndicatorsReq lists indicators as name[:params], comma separated,
+// e.g. "sma:20,ema:50,rsi:14,bollinger:20:2,macd:12:26:9"
+type IndicatorsReq struct {
+	Symbol     string `form:"symbol" binding:"required"`
+	Interval   string `form:"interval"`
+	From       int64  `form:"from"`
+	To         int64  `form:"to"`
+	Indicators string `form:"indicators"`
+}
+
+// ConvertReq converts Amount (default 1) of From into To, at At or now
+type ConvertReq struct {
+	From         string `form:"from" binding:"required"`
+	To           string `form:"to" binding:"required"`
+	Amount       string `form:"amount"`
+	At           int64  `form:"at"`
+	MaxStaleness string `form:"max_staleness"` // e.g. 15m or 2d, default from the config
+}
+
+// AsOfReq looks up every symbol at every time; both are comma separated
+type AsOfReq struct {
+	Symbols      string `form:"symbols" binding:"required"`
+	At           string `form:"at"`            // unix seconds or RFC 3339, default now
+	MaxStaleness string `form:"max_staleness"` // e.g. 15m or 2d, default from the config
+}
+
+type LatestReq struct {
+	Symbol  string `form:"symbol" binding:"required"`
+	Windows string `form:"windows"` // comma separated, e.g. "1h,24h,7d,30d,ytd"
 }
 
+// LatestRes holds the change over every requested window, null when the
+// symbol has no price from before the window started.
+// Change24HPct is kept for older clients; use Changes["24h"] instead.
 type LatestRes struct {
-	Symbol       string          `json:"symbol"`
-	Price        decimal.Decimal `json:"price"`
-	Timestamp    int64           `json:"timestamp"`
-	Change24HPct float64         `json:"change_24h_pct"`
+	Symbol       string                `json:"symbol"`
+	Price        decimal.Decimal       `json:"price"`
+	Timestamp    int64                 `json:"timestamp"`
+	Change24HPct float64               `json:"change_24h_pct"`
+	Changes      map[string]*ChangeRes `json:"changes"`
+	Market       *MarketRes            `json:"market,omitempty"`
+}
+
+// ChangeRes compares the latest price with the reference price, the one in
+// effect when the window started
+type ChangeRes struct {
+	Reference PricePoint      `json:"reference"`
+	Change    decimal.Decimal `json:"change"`
+	ChangePct decimal.Decimal `json:"change_pct"`
+}
+
+// MarketRes is the latest market data at or before the price; null fields
+// were not reported by the provider
+type MarketRes struct {
+	Timestamp         int64               `json:"timestamp"`
+	MarketCap         decimal.NullDecimal `json:"market_cap" swaggertype:"string"`
+	TotalVolume       decimal.NullDecimal `json:"total_volume" swaggertype:"string"`
+	CirculatingSupply decimal.NullDecimal `json:"circulating_supply" swaggertype:"string"`
+	High24h           decimal.NullDecimal `json:"high_24h" swaggertype:"string"`
+	Low24h            decimal.NullDecimal `json:"low_24h" swaggertype:"string"`
+}
+
+// MarketHistoryRes holds the last reported market values of each bucket
+type MarketHistoryRes struct {
+	StartedAt int64  `json:"startedAt"`
+	Symbol    string `json:"symbol"`
+	MarketRes
 }
 
 type HistoryRes struct {
@@ -28,3 +103,144 @@ type HistoryRes struct {
 	AvgPrice  decimal.Decimal `json:"avg_price"`
 	LastPrice decimal.Decimal `json:"last_price"`
 }
+
+// AverageRes is a time or volume-proxy weighted average price over [From, To].
+// CoveredSeconds is shorter than the window when the first tick is later than From.
+type AverageRes struct {
+	Symbol         string          `json:"symbol"`
+	Method         string          `json:"method"` // twap or vwap
+	Price          decimal.Decimal `json:"price"`
+	From           int64           `json:"from"`
+	To             int64           `json:"to"`
+	Ticks          int64           `json:"ticks"`
+	CoveredSeconds int64           `json:"covered_seconds"`
+}
+
+type IndicatorsRes struct {
+	Symbol   string            `json:"symbol"`
+	Interval string            `json:"interval"`
+	Points   []*IndicatorPoint `json:"points"`
+}
+
+// IndicatorPoint holds every requested indicator at the close of a bucket, keyed
+// like "sma_20" or "macd_12_26_9_signal". Values are null while there isn't
+// enough history for them yet.
+type IndicatorPoint struct {
+	StartedAt int64                       `json:"startedAt"`
+	Close     decimal.Decimal             `json:"close"`
+	Values    map[string]*decimal.Decimal `json:"values" swaggertype:"object,string"`
+}
+
+// PricePoint is a bucket close and the start of its bucket
+type PricePoint struct {
+	Price     decimal.Decimal `json:"price"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+// Drawdown is the largest fall from a peak to a later trough, as a fraction of the peak
+type Drawdown struct {
+	Ratio  decimal.Decimal `json:"ratio"`
+	Peak   PricePoint      `json:"peak"`
+	Trough PricePoint      `json:"trough"`
+}
+
+// StatisticsRes describes the bucket closes of a window. Volatility is the
+// sample standard deviation of the bucket log returns, null with fewer than
+// two returns; the annualized figure scales it to 365 days of trading.
+type StatisticsRes struct {
+	Symbol               string          `json:"symbol"`
+	Interval             string          `json:"interval"`
+	Buckets              int             `json:"buckets"`
+	Open                 PricePoint      `json:"open"`
+	Close                PricePoint      `json:"close"`
+	Min                  PricePoint      `json:"min"`
+	Max                  PricePoint      `json:"max"`
+	SimpleReturn         decimal.Decimal `json:"simple_return"`
+	LogReturn            decimal.Decimal `json:"log_return"`
+	Volatility           *float64        `json:"volatility"`
+	AnnualizedVolatility *float64        `json:"annualized_volatility"`
+	MaxDrawdown          Drawdown        `json:"max_drawdown"`
+}
+
+// ConvertRes converts through a direct pair, its inverse or, failing those,
+// the USD prices of both currencies; Legs are the prices that were used
+type ConvertRes struct {
+	From   string          `json:"from"`
+	To     string          `json:"to"`
+	Amount decimal.Decimal `json:"amount"`
+	Rate   decimal.Decimal `json:"rate"`
+	Result decimal.Decimal `json:"result"`
+	At     int64           `json:"at"`
+	Method string          `json:"method"` // identity, direct, inverse or cross
+	Legs   []*ConvertLeg   `json:"legs"`
+}
+
+type ConvertLeg struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+// AsOfRes is the price in effect at At. Price is null unless Status is ok;
+// Timestamp and AgeSeconds still describe a stale price.
+type AsOfRes struct {
+	Symbol     string           `json:"symbol"`
+	At         int64            `json:"at"`
+	Status     string           `json:"status"` // ok, stale or missing
+	Price      *decimal.Decimal `json:"price"`
+	Timestamp  *int64           `json:"timestamp"`
+	AgeSeconds *int64           `json:"age_seconds"`
+}
+
+type TickRes struct {
+	Symbol    string          `json:"symbol"`
+	Price     decimal.Decimal `json:"price"`
+	Timestamp int64           `json:"timestamp"`
+}
+
+type TicksRes struct {
+	Ticks      []*TickRes `json:"ticks"`
+	NextCursor string     `json:"next_cursor,omitempty"`
+}
+
+var HistoryCSVHeader = []string{"started_at", "symbol", "avg_price", "last_price"}
+
+func (h *HistoryRes) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(h.StartedAt, 10),
+		h.Symbol,
+		h.AvgPrice.String(),
+		h.LastPrice.String(),
+	}
+}
+
+var TickCSVHeader = []string{"timestamp", "symbol", "price"}
+
+func (t *TickRes) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(t.Timestamp, 10),
+		t.Symbol,
+		t.Price.String(),
+	}
+}
+
+var MarketHistoryCSVHeader = []string{"started_at", "symbol", "market_cap", "total_volume", "circulating_supply", "high_24h", "low_24h"}
+
+func (m *MarketHistoryRes) CSVRecord() []string {
+	return []string{
+		strconv.FormatInt(m.StartedAt, 10),
+		m.Symbol,
+		nullString(m.MarketCap),
+		nullString(m.TotalVolume),
+		nullString(m.CirculatingSupply),
+		nullString(m.High24h),
+		nullString(m.Low24h),
+	}
+}
+
+func nullString(d decimal.NullDecimal) string {
+	if !d.Valid {
+		return ""
+	}
+	return d.Decimal.String()
+}
@@ -0,0 +1,63 @@
+package response
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ETag builds a weak entity tag from the given parts
+func ETag(parts ...any) string {
+	h := sha256.New()
+	for _, p := range parts {
+		_, _ = fmt.Fprintf(h, "%v|", p)
+	}
+	return `W/"` + hex.EncodeToString(h.Sum(nil))[:32] + `"`
+}
+
+// Cacheable sets ETag, Last-Modified and Cache-Control headers and answers
+// conditional requests. It returns true when a 304 Not Modified has been sent
+// and the caller must not write a body. A zero lastModified leaves out
+// Last-Modified and ignores If-Modified-Since.
+func Cacheable(c *gin.Context, etag string, lastModified time.Time, maxAge time.Duration) bool {
+	lastModified = lastModified.UTC().Truncate(time.Second)
+
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", fmt.Sprintf("public, max-age=%d", int64(maxAge.Seconds())))
+
+	if inm := c.GetHeader("If-None-Match"); inm != "" {
+		// If-None-Match takes precedence over If-Modified-Since (RFC 9110 13.1.3)
+		if etagMatch(inm, etag) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+		return false
+	}
+
+	if ims := c.GetHeader("If-Modified-Since"); ims != "" && !lastModified.IsZero() {
+		t, err := http.ParseTime(ims)
+		if err == nil && !lastModified.After(t) {
+			c.AbortWithStatus(http.StatusNotModified)
+			return true
+		}
+	}
+	return false
+}
+
+func etagMatch(header, etag string) bool {
+	for _, candidate := range strings.Split(header, ",") {
+		candidate = strings.TrimSpace(candidate)
+		if candidate == "*" || strings.TrimPrefix(candidate, "W/") == strings.TrimPrefix(etag, "W/") {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,101 @@
+package response
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+func TestETagMatch(t *testing.T) {
+	const etag = `W/"abc"`
+	tests := []struct {
+		name   string
+		header string
+		want   bool
+	}{
+		{"exact", `W/"abc"`, true},
+		{"strong form of a weak tag", `"abc"`, true},
+		{"wildcard", `*`, true},
+		{"one of a list", `"x", W/"abc" ,"y"`, true},
+		{"different tag", `W/"abd"`, false},
+		{"unquoted", `abc`, false},
+		{"empty", ``, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := etagMatch(tt.header, etag); got != tt.want {
+				t.Errorf("etagMatch(%q, %q) = %v, want %v", tt.header, etag, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestCacheable(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	const etag = `W/"abc"`
+	modified := time.Date(2025, 1, 2, 3, 4, 5, 600, time.UTC)
+	before := modified.Add(-time.Hour).Format(http.TimeFormat)
+	at := modified.Format(http.TimeFormat)
+
+	tests := []struct {
+		name    string
+		headers map[string]string
+		want    bool
+	}{
+		{"unconditional", nil, false},
+		{"etag matches", map[string]string{"If-None-Match": etag}, true},
+		{"etag differs", map[string]string{"If-None-Match": `W/"old"`}, false},
+		{"not modified since", map[string]string{"If-Modified-Since": at}, true},
+		{"modified since", map[string]string{"If-Modified-Since": before}, false},
+		{"invalid date", map[string]string{"If-Modified-Since": "yesterday"}, false},
+		// If-None-Match takes precedence over If-Modified-Since either way
+		{"etag differs, date matches", map[string]string{"If-None-Match": `W/"old"`, "If-Modified-Since": at}, false},
+		{"etag matches, date differs", map[string]string{"If-None-Match": etag, "If-Modified-Since": before}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			w := httptest.NewRecorder()
+			c, _ := gin.CreateTestContext(w)
+			c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+			for k, v := range tt.headers {
+				c.Request.Header.Set(k, v)
+			}
+
+			if got := Cacheable(c, etag, modified, time.Minute); got != tt.want {
+				t.Fatalf("Cacheable() = %v, want %v", got, tt.want)
+			}
+			if tt.want && c.Writer.Status() != http.StatusNotModified {
+				t.Errorf("status = %d, want %d", c.Writer.Status(), http.StatusNotModified)
+			}
+			if got := w.Header().Get("ETag"); got != etag {
+				t.Errorf("ETag = %q, want %q", got, etag)
+			}
+			if got := w.Header().Get("Last-Modified"); got != at {
+				t.Errorf("Last-Modified = %q, want %q", got, at)
+			}
+			if got := w.Header().Get("Cache-Control"); got != "public, max-age=60" {
+				t.Errorf("Cache-Control = %q", got)
+			}
+		})
+	}
+}
+
+func TestCacheableWithoutLastModified(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	w := httptest.NewRecorder()
+	c, _ := gin.CreateTestContext(w)
+	c.Request = httptest.NewRequest(http.MethodGet, "/", nil)
+	c.Request.Header.Set("If-Modified-Since", time.Now().Add(time.Hour).UTC().Format(http.TimeFormat))
+
+	if Cacheable(c, `W/"abc"`, time.Time{}, time.Minute) {
+		t.Error("Cacheable() answered If-Modified-Since without a Last-Modified")
+	}
+	if got := w.Header().Get("Last-Modified"); got != "" {
+		t.Errorf("Last-Modified = %q, want none", got)
+	}
+}
@@ -0,0 +1,109 @@
+package response
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"net/http"
+
+	"github.com/gin-gonic/gin"
+)
+
+const (
+	MIMEJSON   = "application/json"
+	MIMECSV    = "text/csv"
+	MIMENDJSON = "application/x-ndjson"
+
+	// flushEvery bounds how many rows are buffered before they are pushed to the client
+	flushEvery = 500
+)
+
+// Record is a row that can be written as CSV
+type Record interface {
+	CSVRecord() []string
+}
+
+// Stream writes rows as CSV or NDJSON directly to the response. Headers and
+// the status line are only sent with the first row, so callers can still
+// reply with an error while nothing has been written.
+type Stream struct {
+	c        *gin.Context
+	format   string
+	filename string
+	header   []string
+	csv      *csv.Writer
+	json     *json.Encoder
+	started  bool
+	rows     int
+}
+
+// NewStream prepares a stream; format is MIMECSV or MIMENDJSON.
+func NewStream(c *gin.Context, format, filename string, header []string) *Stream {
+	return &Stream{
+		c:        c,
+		format:   format,
+		filename: filename,
+		header:   header,
+	}
+}
+
+// Started reports whether anything has been written to the client
+func (s *Stream) Started() bool {
+	return s.started
+}
+
+// Write appends one row to the stream
+func (s *Stream) Write(r Record) error {
+	if !s.started {
+		if err := s.start(); err != nil {
+			return err
+		}
+	}
+
+	var err error
+	if s.format == MIMECSV {
+		err = s.csv.Write(r.CSVRecord())
+	} else {
+		err = s.json.Encode(r)
+	}
+	if err != nil {
+		return err
+	}
+
+	s.rows++
+	if s.rows%flushEvery == 0 {
+		return s.Flush()
+	}
+	return nil
+}
+
+// Flush pushes buffered rows to the client
+func (s *Stream) Flush() error {
+	if s.csv != nil {
+		s.csv.Flush()
+		if err := s.csv.Error(); err != nil {
+			return err
+		}
+	}
+	if s.started {
+		s.c.Writer.Flush()
+	}
+	return nil
+}
+
+func (s *Stream) start() error {
+	ext := "ndjson"
+	if s.format == MIMECSV {
+		ext = "csv"
+	}
+	s.c.Header("Content-Type", s.format+"; charset=utf-8")
+	s.c.Header("Content-Disposition", `attachment; filename="`+s.filename+"."+ext+`"`)
+	s.c.Status(http.StatusOK)
+	s.started = true
+
+	if s.format == MIMECSV {
+		s.csv = csv.NewWriter(s.c.Writer)
+		return s.csv.Write(s.header)
+	}
+	s.json = json.NewEncoder(s.c.Writer)
+	return nil
+}
@@ -0,0 +1,21 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+type AdminRouter struct {
+	adminController *controller.AdminController
+}
+
+func NewAdminRouter(adminController *controller.AdminController) *AdminRouter {
+	return &AdminRouter{adminController: adminController}
+}
+
+func (ar *AdminRouter) SetupRoutes(router *gin.Engine) {
+	g := router.Group("/admin", ar.adminController.Authorize)
+	{
+		g.POST("/reload", ar.adminController.Reload)
+	}
+}
@@ -0,0 +1,25 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+type AlertRouter struct {
+	alertController *controller.AlertController
+	adminController *controller.AdminController
+}
+
+func NewAlertRouter(alertController *controller.AlertController, adminController *controller.AdminController) *AlertRouter {
+	return &AlertRouter{alertController: alertController, adminController: adminController}
+}
+
+func (ar *AlertRouter) SetupRoutes(router *gin.Engine) {
+	g := router.Group("/admin/alerts", ar.adminController.Authorize)
+	{
+		g.GET("", ar.alertController.ListRules)
+		g.POST("", ar.alertController.CreateRule)
+		g.DELETE("/:id", ar.alertController.DeleteRule)
+		g.GET("/:id/deliveries", ar.alertController.ListDeliveries)
+	}
+}
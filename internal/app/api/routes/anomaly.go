@@ -0,0 +1,24 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+type AnomalyRouter struct {
+	anomalyController *controller.AnomalyController
+	adminController   *controller.AdminController
+}
+
+func NewAnomalyRouter(anomalyController *controller.AnomalyController, adminController *controller.AdminController) *AnomalyRouter {
+	return &AnomalyRouter{anomalyController: anomalyController, adminController: adminController}
+}
+
+func (ar *AnomalyRouter) SetupRoutes(router *gin.Engine) {
+	g := router.Group("/admin/anomalies", ar.adminController.Authorize)
+	{
+		g.GET("", ar.anomalyController.ListAnomalies)
+		g.POST("/:id/approve", ar.anomalyController.Approve)
+		g.POST("/:id/reject", ar.anomalyController.Reject)
+	}
+}
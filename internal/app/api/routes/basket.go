@@ -0,0 +1,29 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+type BasketRouter struct {
+	basketController *controller.BasketController
+	adminController  *controller.AdminController
+}
+
+func NewBasketRouter(basketController *controller.BasketController, adminController *controller.AdminController) *BasketRouter {
+	return &BasketRouter{basketController: basketController, adminController: adminController}
+}
+
+func (br *BasketRouter) SetupRoutes(router *gin.Engine) {
+	g := router.Group("/baskets")
+	{
+		g.GET("", br.basketController.ListBaskets)
+		g.GET("/:name", br.basketController.GetBasket)
+	}
+
+	admin := router.Group("/admin/baskets", br.adminController.Authorize)
+	{
+		admin.PUT("/:name", br.basketController.SaveBasket)
+		admin.DELETE("/:name", br.basketController.DeleteBasket)
+	}
+}
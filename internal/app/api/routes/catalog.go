@@ -0,0 +1,18 @@
+package routes
+
+import (
+	"github.com/gin-gonic/gin"
+	controller "github.com/milad-rasouli/price/internal/app/api/controllers"
+)
+
+type CatalogRouter struct {
+	catalogController *controller.CatalogController
+}
+
+func NewCatalogRouter(catalogController *controller.CatalogController) *CatalogRouter {
+	return &CatalogRouter{catalogController: catalogController}
+}
+
+func (cr *CatalogRouter) SetupRoutes(router *gin.Engine) {
+	router.GET("/symbols", cr.catalogController.ListSymbols)
+}
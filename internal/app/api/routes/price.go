@@ -18,5 +18,14 @@ func (pr *PriceRouter) SetupRoutes(router *gin.Engine) {
 	{
 		g.GET("/history", pr.priceController.GetHistory)
 		g.GET("/latest", pr.priceController.GetLatest)
+		g.GET("/ticks", pr.priceController.GetTicks)
+		g.GET("/market/history", pr.priceController.GetMarketHistory)
+		g.GET("/twap", pr.priceController.GetTWAP)
+		g.GET("/vwap", pr.priceController.GetVWAP)
+		g.GET("/indicators", pr.priceController.GetIndicators)
+		g.GET("/statistics", pr.priceController.GetStatistics)
+		g.GET("/asof", pr.priceController.GetAsOf)
 	}
+	router.GET("/convert", pr.priceController.Convert)
+	router.POST("/portfolio/value", pr.priceController.ValuePortfolio)
 }
@@ -12,10 +12,20 @@ func CreateRouters(
 	priceRouter *PriceRouter,
 	cron *CronRouter,
 	healthRouter *HealthRouter,
+	adminRouter *AdminRouter,
+	basketRouter *BasketRouter,
+	alertRouter *AlertRouter,
+	anomalyRouter *AnomalyRouter,
+	catalogRouter *CatalogRouter,
 ) []Router {
 	return []Router{
 		healthRouter,
 		priceRouter,
 		cron,
+		adminRouter,
+		basketRouter,
+		alertRouter,
+		anomalyRouter,
+		catalogRouter,
 	}
 }
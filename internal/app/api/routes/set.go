@@ -6,5 +6,10 @@ var ProviderSet = wire.NewSet(
 	NewPriceRouter,
 	NewCronRouter,
 	NewHealthRouter,
+	NewAdminRouter,
+	NewBasketRouter,
+	NewAlertRouter,
+	NewAnomalyRouter,
+	NewCatalogRouter,
 	CreateRouters,
 )
@@ -3,84 +3,147 @@ package coingecko
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/ratelimit"
 	"github.com/milad-rasouli/price/internal/providers/currency"
 	"github.com/shopspring/decimal"
 	"log/slog"
+	"net"
 	"net/http"
+	"strings"
+	"sync"
 	"time"
 )
 
+const (
+	PublicURL = "https://api.coingecko.com/api/v3"
+	ProURL    = "https://pro-api.coingecko.com/api/v3"
+)
+
 type CoinGecko struct {
-	client  *http.Client
-	baseURL string
-	logger  *slog.Logger
+	client    *http.Client
+	baseURL   string
+	keyHeader string
+	apiKey    string
+	userAgent string
+	retry     config.Retry
+	limiter   *ratelimit.Limiter
+	logger    *slog.Logger
+
+	mu  sync.Mutex
+	ids map[string]string // symbol -> coingecko coin id
 }
 
-func NewCoinGecko(logger *slog.Logger) *CoinGecko {
+func NewCoinGecko(cfg *config.Config, logger *slog.Logger) *CoinGecko {
+	cg := cfg.Providers.CoinGecko
+
+	baseURL, keyHeader := PublicURL, "x-cg-demo-api-key"
+	if cg.Plan == config.CoinGeckoPro {
+		baseURL, keyHeader = ProURL, "x-cg-pro-api-key"
+	}
+	if cg.BaseURL != "" {
+		baseURL = strings.TrimSuffix(cg.BaseURL, "/")
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.DialContext = (&net.Dialer{
+		Timeout:   cg.ConnectTimeout.Duration,
+		KeepAlive: 30 * time.Second,
+	}).DialContext
+	transport.TLSHandshakeTimeout = cg.ConnectTimeout.Duration
+
 	return &CoinGecko{
-		client:  &http.Client{Timeout: 5 * time.Second},
-		baseURL: "https://api.coingecko.com/api/v3",
-		logger:  logger.With("provider", "coingecko"),
+		client: &http.Client{
+			Timeout:   cg.Timeout.Duration,
+			Transport: transport,
+		},
+		baseURL:   baseURL,
+		keyHeader: keyHeader,
+		apiKey:    cg.APIKey,
+		userAgent: cg.UserAgent,
+		retry:     cg.Retry,
+		limiter:   ratelimit.New(cg.Rate(), cg.RateLimit.Burst),
+		logger:    logger.With("provider", "coingecko"),
+		ids:       make(map[string]string),
 	}
 }
 
+// Prices are decoded straight from the JSON number into decimals; going through
+// float64 would round micro-cap prices before they are stored.
 type coinResponse struct {
-	Symbol       string  `json:"symbol"`
-	CurrentPrice float64 `json:"current_price"`
-	LastUpdated  string  `json:"last_updated"`
+	ID                string              `json:"id"`
+	Symbol            string              `json:"symbol"`
+	Name              string              `json:"name"`
+	CurrentPrice      decimal.NullDecimal `json:"current_price"` // null for coins that stopped trading
+	MarketCap         decimal.NullDecimal `json:"market_cap"`
+	TotalVolume       decimal.NullDecimal `json:"total_volume"`
+	CirculatingSupply decimal.NullDecimal `json:"circulating_supply"`
+	High24h           decimal.NullDecimal `json:"high_24h"`
+	Low24h            decimal.NullDecimal `json:"low_24h"`
+	LastUpdated       string              `json:"last_updated"`
 }
 
-func (c *CoinGecko) Get(ctx context.Context, page, limit uint32) ([]*entity.Price, error) {
-	url := fmt.Sprintf(
-		"%s/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=%d&sparkline=false&price_change_percentage=24h",
-		c.baseURL, limit, page,
-	)
-
-	c.logger.Info("fetching prices from coingecko", "url", url, "page", page, "limit", limit)
-
-	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
-	if err != nil {
-		c.logger.Error("failed to create request", "error", err)
-		return nil, err
+// market returns nil when CoinGecko reported nothing beyond the price
+func (c *coinResponse) market() *entity.Market {
+	m := &entity.Market{
+		MarketCap:         c.MarketCap,
+		TotalVolume:       c.TotalVolume,
+		CirculatingSupply: c.CirculatingSupply,
+		High24h:           c.High24h,
+		Low24h:            c.Low24h,
 	}
-
-	resp, err := c.client.Do(req)
-	if err != nil {
-		c.logger.Error("failed to call coingecko API", "error", err)
-		return nil, err
+	if *m == (entity.Market{}) {
+		return nil
 	}
-	defer func() {
-		if cerr := resp.Body.Close(); cerr != nil {
-			c.logger.Warn("failed to close response body", "error", cerr)
-		}
-	}()
+	return m
+}
 
-	switch resp.StatusCode {
-	case http.StatusOK:
-		// continue
-	case http.StatusTooManyRequests:
-		c.logger.Error("rate limit exceeded from coingecko", "status", resp.StatusCode)
-		return nil, currency.ErrCurrencyTooManyRequests
-	default:
-		c.logger.Error("unexpected status code", "status", resp.StatusCode)
-		return nil, fmt.Errorf("unexpected status code: %d", resp.StatusCode)
+// marketChartResponse holds [unix millis, value] points; the three series share timestamps
+type marketChartResponse struct {
+	Prices       [][2]decimal.Decimal `json:"prices"`
+	MarketCaps   [][2]decimal.Decimal `json:"market_caps"`
+	TotalVolumes [][2]decimal.Decimal `json:"total_volumes"`
+}
+
+func (c *CoinGecko) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	url := fmt.Sprintf(
+		"%s/coins/markets?vs_currency=%s&order=market_cap_desc&per_page=%d&page=%d&sparkline=false&price_change_percentage=24h",
+		c.baseURL, q.Quote, q.Limit, q.Page,
+	)
+	if len(q.Symbols) > 0 {
+		url += "&symbols=" + strings.Join(q.Symbols, ",")
 	}
 
+	c.logger.Info("fetching prices from coingecko", "url", url, "page", q.Page, "limit", q.Limit)
+
 	var coins []coinResponse
-	if err := json.NewDecoder(resp.Body).Decode(&coins); err != nil {
-		c.logger.Error("failed to decode coingecko response", "error", err)
+	if err := c.get(ctx, url, &coins); err != nil {
 		return nil, err
 	}
 
 	if len(coins) == 0 {
-		c.logger.Warn("no currencies returned from coingecko", "page", page, "limit", limit)
+		c.logger.Warn("no currencies returned from coingecko", "page", q.Page, "limit", q.Limit)
 		return nil, currency.ErrCurrencyNotFound
 	}
 
 	result := make([]*entity.Price, 0, len(coins))
+	seen := make(map[string]bool, len(coins))
 	for _, coin := range coins {
+		// several tokens can share a ticker; results are ordered by market cap so keep the largest
+		if seen[coin.Symbol] {
+			continue
+		}
+		seen[coin.Symbol] = true
+		c.rememberID(coin.Symbol, coin.ID)
+
+		if !coin.CurrentPrice.Valid {
+			c.logger.Warn("coin has no price, skipping", "symbol", coin.Symbol, "id", coin.ID)
+			continue
+		}
+
 		t, err := time.Parse(time.RFC3339, coin.LastUpdated)
 		unixTime := time.Now().Unix()
 		if err == nil {
@@ -90,12 +153,211 @@ func (c *CoinGecko) Get(ctx context.Context, page, limit uint32) ([]*entity.Pric
 		}
 
 		result = append(result, &entity.Price{
-			Symbol: coin.Symbol,
-			Price:  decimal.NewFromFloat(coin.CurrentPrice),
+			Symbol: entity.PairSymbol(coin.Symbol, q.Quote),
+			Price:  coin.CurrentPrice.Decimal,
 			Time:   unixTime,
+			Market: coin.market(),
+			Asset:  &entity.Asset{Name: coin.Name, ProviderIDs: map[string]string{config.ProviderCoinGecko: coin.ID}},
 		})
 	}
 
 	c.logger.Info("fetched prices successfully", "count", len(result))
 	return result, nil
 }
+
+// GetRange returns past prices of one symbol. CoinGecko picks the granularity
+// from the range length: 5 minutes up to a day, hourly up to 90 days, daily beyond.
+func (c *CoinGecko) GetRange(ctx context.Context, symbol string, from, to int64) ([]*entity.Price, error) {
+	id, err := c.resolveID(ctx, symbol)
+	if err != nil {
+		return nil, err
+	}
+
+	url := fmt.Sprintf("%s/coins/%s/market_chart/range?vs_currency=usd&from=%d&to=%d", c.baseURL, id, from, to)
+	c.logger.Info("fetching price range from coingecko", "url", url, "symbol", symbol)
+
+	var chart marketChartResponse
+	if err := c.get(ctx, url, &chart); err != nil {
+		return nil, err
+	}
+
+	caps := seriesByTime(chart.MarketCaps)
+	volumes := seriesByTime(chart.TotalVolumes)
+
+	result := make([]*entity.Price, 0, len(chart.Prices))
+	for _, point := range chart.Prices {
+		p := &entity.Price{
+			Symbol: symbol,
+			Price:  point[1],
+			Time:   point[0].IntPart() / 1000,
+		}
+		mc, hasCap := caps[point[0].IntPart()]
+		vol, hasVolume := volumes[point[0].IntPart()]
+		if hasCap || hasVolume {
+			p.Market = &entity.Market{
+				MarketCap:   decimal.NullDecimal{Decimal: mc, Valid: hasCap},
+				TotalVolume: decimal.NullDecimal{Decimal: vol, Valid: hasVolume},
+			}
+		}
+		result = append(result, p)
+	}
+
+	c.logger.Info("fetched price range successfully", "symbol", symbol, "count", len(result))
+	return result, nil
+}
+
+func seriesByTime(points [][2]decimal.Decimal) map[int64]decimal.Decimal {
+	m := make(map[int64]decimal.Decimal, len(points))
+	for _, point := range points {
+		m[point[0].IntPart()] = point[1]
+	}
+	return m
+}
+
+// resolveID maps a ticker symbol to the coin id used in CoinGecko URLs.
+// When several coins share a symbol the one with the largest market cap wins.
+func (c *CoinGecko) resolveID(ctx context.Context, symbol string) (string, error) {
+	symbol = strings.ToLower(symbol)
+
+	c.mu.Lock()
+	id, ok := c.ids[symbol]
+	c.mu.Unlock()
+	if ok {
+		return id, nil
+	}
+
+	url := fmt.Sprintf("%s/coins/markets?vs_currency=usd&order=market_cap_desc&symbols=%s&per_page=1&page=1", c.baseURL, symbol)
+	var coins []coinResponse
+	if err := c.get(ctx, url, &coins); err != nil {
+		return "", err
+	}
+	if len(coins) == 0 {
+		return "", fmt.Errorf("%w: %s", currency.ErrCurrencyNotFound, symbol)
+	}
+
+	c.rememberID(symbol, coins[0].ID)
+	return coins[0].ID, nil
+}
+
+func (c *CoinGecko) rememberID(symbol, id string) {
+	if id == "" {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if _, ok := c.ids[symbol]; !ok {
+		c.ids[symbol] = id
+	}
+}
+
+// get fetches url into v. Every attempt waits for the rate limiter; network
+// errors, 5xx and 429 responses are retried with jittered exponential backoff,
+// or after Retry-After when CoinGecko sends it.
+func (c *CoinGecko) get(ctx context.Context, url string, v any) error {
+	for attempt := 1; ; attempt++ {
+		if err := c.limiter.Wait(ctx); err != nil {
+			if errors.Is(err, ratelimit.ErrBudgetExceeded) {
+				c.logger.Warn("no request budget left before the deadline", "attempt", attempt)
+				return fmt.Errorf("%w: %w", currency.ErrCurrencyTooManyRequests, err)
+			}
+			return err
+		}
+
+		res := c.do(ctx, url, v)
+		if res.err == nil {
+			c.limiter.Succeeded()
+			return nil
+		}
+		if !res.retryable || ctx.Err() != nil {
+			return res.err
+		}
+
+		wait := ratelimit.Backoff(c.retry.Backoff.Duration, c.retry.MaxBackoff.Duration, attempt)
+		throttled := errors.Is(res.err, currency.ErrCurrencyTooManyRequests)
+		if throttled {
+			if res.retryAfter > 0 {
+				wait = res.retryAfter
+			}
+			// pauses every caller of this client, not only this request
+			c.limiter.Throttled(wait)
+		}
+		if attempt >= c.retry.MaxAttempts {
+			c.logger.Error("giving up on coingecko request", "attempt", attempt, "error", res.err)
+			return res.err
+		}
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < wait {
+			c.logger.Warn("retry would outlast the deadline, giving up", "attempt", attempt, "wait", wait, "error", res.err)
+			return res.err
+		}
+
+		c.logger.Warn("coingecko request failed, retrying", "attempt", attempt, "wait", wait, "error", res.err)
+		if throttled {
+			continue // the limiter holds the next attempt back
+		}
+		select {
+		case <-time.After(wait):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+}
+
+type result struct {
+	err        error
+	retryable  bool
+	retryAfter time.Duration
+}
+
+// do sends one request and reports whether a failure is worth retrying
+func (c *CoinGecko) do(ctx context.Context, url string, v any) result {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		c.logger.Error("failed to create request", "error", err)
+		return result{err: err}
+	}
+	req.Header.Set("Accept", "application/json")
+	if c.userAgent != "" {
+		req.Header.Set("User-Agent", c.userAgent)
+	}
+	if c.apiKey != "" {
+		req.Header.Set(c.keyHeader, c.apiKey)
+	}
+
+	resp, err := c.client.Do(req)
+	if err != nil {
+		c.logger.Error("failed to call coingecko API", "error", err)
+		return result{err: err, retryable: true}
+	}
+	defer func() {
+		if cerr := resp.Body.Close(); cerr != nil {
+			c.logger.Warn("failed to close response body", "error", cerr)
+		}
+	}()
+
+	switch {
+	case resp.StatusCode == http.StatusOK:
+		// continue
+	case resp.StatusCode == http.StatusTooManyRequests:
+		retryAfter, _ := ratelimit.RetryAfter(resp.Header.Get("Retry-After"), time.Now())
+		c.logger.Error("rate limit exceeded from coingecko", "status", resp.StatusCode, "retry_after", retryAfter)
+		return result{err: currency.ErrCurrencyTooManyRequests, retryable: true, retryAfter: retryAfter}
+	case resp.StatusCode == http.StatusNotFound:
+		c.logger.Error("coingecko resource not found", "status", resp.StatusCode)
+		return result{err: currency.ErrCurrencyNotFound}
+	case resp.StatusCode == http.StatusUnauthorized || resp.StatusCode == http.StatusForbidden:
+		c.logger.Error("coingecko rejected the API key", "status", resp.StatusCode)
+		return result{err: fmt.Errorf("coingecko rejected the API key: status code %d", resp.StatusCode)}
+	default:
+		c.logger.Error("unexpected status code", "status", resp.StatusCode)
+		return result{
+			err:       fmt.Errorf("unexpected status code: %d", resp.StatusCode),
+			retryable: resp.StatusCode >= http.StatusInternalServerError,
+		}
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(v); err != nil {
+		c.logger.Error("failed to decode coingecko response", "error", err)
+		return result{err: err}
+	}
+	return result{}
+}
@@ -0,0 +1,308 @@
+// Package config loads the service configuration. Values come from built-in
+// defaults, then an optional YAML or TOML file, then environment variables
+// (including a .env file), each layer overriding the previous one.
+package config
+
+import (
+	"bytes"
+	"cmp"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/joho/godotenv"
+	"github.com/pelletier/go-toml/v2"
+	"gopkg.in/yaml.v3"
+)
+
+// DefaultFile is read when CONFIG_FILE is not set and the file exists
+const DefaultFile = "config.yaml"
+
+type Config struct {
+	Environment string    `yaml:"environment" toml:"environment"` //development,staging,production
+	HTTP        HTTP      `yaml:"http" toml:"http"`
+	Database    Database  `yaml:"database" toml:"database"`
+	Log         Log       `yaml:"log" toml:"log"`
+	Ingest      Ingest    `yaml:"ingest" toml:"ingest"`
+	Providers   Providers `yaml:"providers" toml:"providers"`
+	Admin       Admin     `yaml:"admin" toml:"admin"`
+	Query       Query     `yaml:"query" toml:"query"`
+	Alerts      Alerts    `yaml:"alerts" toml:"alerts"`
+	Anomaly     Anomaly   `yaml:"anomaly" toml:"anomaly"`
+
+	// File is the config file that was loaded, empty when none was
+	File string `yaml:"-" toml:"-"`
+}
+
+type HTTP struct {
+	Port            string   `yaml:"port" toml:"port"`
+	ReadTimeout     Duration `yaml:"read_timeout" toml:"read_timeout"`
+	ShutdownTimeout Duration `yaml:"shutdown_timeout" toml:"shutdown_timeout"`
+}
+
+type Database struct {
+	URL               string   `yaml:"url" toml:"url"`
+	MaxConns          int32    `yaml:"max_conns" toml:"max_conns"` // 0 keeps the pgxpool default
+	MinConns          int32    `yaml:"min_conns" toml:"min_conns"`
+	ConnectTimeout    Duration `yaml:"connect_timeout" toml:"connect_timeout"`
+	CompressAfterDays int64    `yaml:"compress_after_days" toml:"compress_after_days"` // 0 disables compression of raw prices
+	RawRetentionDays  int64    `yaml:"raw_retention_days" toml:"raw_retention_days"`   // 0 keeps raw prices forever
+}
+
+type Log struct {
+	Format string `yaml:"format" toml:"format"` // text or json
+	Level  string `yaml:"level" toml:"level"`   // debug, info, warn, error; empty derives it from the environment
+}
+
+type Ingest struct {
+	Interval Duration `yaml:"interval" toml:"interval"`
+	Timeout  Duration `yaml:"timeout" toml:"timeout"`
+	// Symbols to track; when empty the Top coins by market cap are tracked
+	Symbols         []string `yaml:"symbols" toml:"symbols"`
+	Top             uint32   `yaml:"top" toml:"top"`
+	QuoteCurrencies []string `yaml:"quote_currencies" toml:"quote_currencies"`
+	PageSize        uint32   `yaml:"page_size" toml:"page_size"`
+}
+
+type Providers struct {
+	// Priority lists the providers to try in order: coingecko, fake, replay, record
+	Priority   []string  `yaml:"priority" toml:"priority"`
+	ReplayFile string    `yaml:"replay_file" toml:"replay_file"` // read by replay, appended to by record
+	CoinGecko  CoinGecko `yaml:"coingecko" toml:"coingecko"`
+}
+
+type Admin struct {
+	// Token guards the /admin endpoints, which are disabled when it is empty
+	Token string `yaml:"token" toml:"token"`
+}
+
+type Query struct {
+	// MaxStaleness is how far before the requested time an as-of price may
+	// have been recorded; older prices are reported as stale
+	MaxStaleness Duration `yaml:"max_staleness" toml:"max_staleness"`
+}
+
+type Alerts struct {
+	// SigningSecret keys the HMAC-SHA256 signature of every webhook; alert
+	// rules can't be created while it is empty
+	SigningSecret string   `yaml:"signing_secret" toml:"signing_secret"`
+	Timeout       Duration `yaml:"timeout" toml:"timeout"` // per delivery attempt
+	Retry         Retry    `yaml:"retry" toml:"retry"`
+}
+
+// Anomaly screens every ingested tick against the recent prices of its symbol
+// before it is stored. A tick is anomalous when it moved more than
+// MaxChangePct from the last stored price, or when the return since that price
+// is more than ZScore standard deviations from the mean return over Lookback.
+// Either check is disabled by 0. A quarantined move is accepted once
+// Confirmations consecutive ticks agree on the new level.
+type Anomaly struct {
+	// Action is off, flag (store the tick and list it for review) or
+	// quarantine (hold it back until it is approved)
+	Action       string   `yaml:"action" toml:"action"`
+	Lookback     Duration `yaml:"lookback" toml:"lookback"`
+	MaxChangePct float64  `yaml:"max_change_pct" toml:"max_change_pct"`
+	ZScore       float64  `yaml:"z_score" toml:"z_score"`
+	MinSamples   int      `yaml:"min_samples" toml:"min_samples"` // fewer returns in Lookback skip the z-score check
+	// Confirmations is how many consecutive ticks, the held ones included, must
+	// agree before a new level is accepted; 0 keeps quarantining until review
+	Confirmations int `yaml:"confirmations" toml:"confirmations"`
+}
+
+type CoinGecko struct {
+	// BaseURL overrides the API root, e.g. for a local stand-in server.
+	// Empty uses the public or pro API depending on Plan.
+	BaseURL string `yaml:"base_url" toml:"base_url"`
+	Plan    string `yaml:"plan" toml:"plan"` // demo (also keyless) or pro
+	APIKey  string `yaml:"api_key" toml:"api_key"`
+	// Timeout bounds a whole request, ConnectTimeout only dialing
+	Timeout        Duration  `yaml:"timeout" toml:"timeout"`
+	ConnectTimeout Duration  `yaml:"connect_timeout" toml:"connect_timeout"`
+	UserAgent      string    `yaml:"user_agent" toml:"user_agent"`
+	Retry          Retry     `yaml:"retry" toml:"retry"`
+	RateLimit      RateLimit `yaml:"rate_limit" toml:"rate_limit"`
+}
+
+// Retry is applied to network errors, 5xx and 429 responses
+type Retry struct {
+	MaxAttempts int `yaml:"max_attempts" toml:"max_attempts"` // 1 disables retries
+	// Backoff doubles after each failure up to MaxBackoff; the actual wait is
+	// a random share of it. A 429 waits for Retry-After instead when it is sent.
+	Backoff    Duration `yaml:"backoff" toml:"backoff"`
+	MaxBackoff Duration `yaml:"max_backoff" toml:"max_backoff"`
+}
+
+// RateLimit is shared by everything one process fetches from the provider,
+// ingestion pages and backfill chunks alike
+type RateLimit struct {
+	// RequestsPerMinute of 0 uses the plan's limit
+	RequestsPerMinute int `yaml:"requests_per_minute" toml:"requests_per_minute"`
+	Burst             int `yaml:"burst" toml:"burst"`
+}
+
+// Plan limits of the CoinGecko API in calls per minute
+const (
+	CoinGeckoDemoRate = 30
+	CoinGeckoProRate  = 500
+)
+
+// Rate returns the calls per minute the client may make
+func (cg *CoinGecko) Rate() int {
+	switch {
+	case cg.RateLimit.RequestsPerMinute > 0:
+		return cg.RateLimit.RequestsPerMinute
+	case cg.Plan == CoinGeckoPro:
+		return CoinGeckoProRate
+	default:
+		return CoinGeckoDemoRate
+	}
+}
+
+func Default() *Config {
+	return &Config{
+		Environment: "development",
+		HTTP: HTTP{
+			Port:            "8080",
+			ReadTimeout:     Duration{30 * time.Second},
+			ShutdownTimeout: Duration{10 * time.Second},
+		},
+		Database: Database{
+			ConnectTimeout:    Duration{10 * time.Second},
+			CompressAfterDays: 7,
+		},
+		Log: Log{
+			Format: "text",
+		},
+		Ingest: Ingest{
+			Interval:        Duration{60 * time.Second},
+			Timeout:         Duration{30 * time.Second},
+			Top:             3,
+			QuoteCurrencies: []string{"usd"},
+			PageSize:        100,
+		},
+		Providers: Providers{
+			Priority:   []string{"coingecko"},
+			ReplayFile: "coingecko.ndjson",
+			CoinGecko: CoinGecko{
+				Plan:           CoinGeckoDemo,
+				Timeout:        Duration{5 * time.Second},
+				ConnectTimeout: Duration{3 * time.Second},
+				UserAgent:      "milad-rasouli/price",
+				Retry: Retry{
+					MaxAttempts: 3,
+					Backoff:     Duration{time.Second},
+					MaxBackoff:  Duration{30 * time.Second},
+				},
+				RateLimit: RateLimit{
+					Burst: 5,
+				},
+			},
+		},
+		Query: Query{
+			MaxStaleness: Duration{time.Hour},
+		},
+		Alerts: Alerts{
+			Timeout: Duration{5 * time.Second},
+			Retry: Retry{
+				MaxAttempts: 5,
+				Backoff:     Duration{2 * time.Second},
+				MaxBackoff:  Duration{time.Minute},
+			},
+		},
+		Anomaly: Anomaly{
+			Action:        AnomalyFlag,
+			Lookback:      Duration{time.Hour},
+			MaxChangePct:  25,
+			MinSamples:    20,
+			Confirmations: 3,
+		},
+	}
+}
+
+// Load builds the configuration from defaults, the config file and the
+// environment. It doesn't validate; call Validate for that.
+func Load() (*Config, error) {
+	loadDotenv()
+
+	c := Default()
+
+	path := os.Getenv("CONFIG_FILE")
+	if path == "" {
+		if _, err := os.Stat(DefaultFile); err == nil {
+			path = DefaultFile
+		}
+	}
+	if path != "" {
+		if err := c.loadFile(path); err != nil {
+			return nil, err
+		}
+	}
+
+	if err := c.loadEnv(); err != nil {
+		return nil, err
+	}
+	return c, nil
+}
+
+var (
+	dotenvMu sync.Mutex
+	// dotenvKeys are the variables set from .env, which a reload may change
+	// without overriding the real environment
+	dotenvKeys = map[string]bool{}
+)
+
+func loadDotenv() {
+	dotenvMu.Lock()
+	defer dotenvMu.Unlock()
+
+	vars, err := godotenv.Read(".env")
+	if err != nil {
+		return // using .env file is not mandatory
+	}
+	for k := range dotenvKeys {
+		if _, ok := vars[k]; !ok {
+			_ = os.Unsetenv(k)
+			delete(dotenvKeys, k)
+		}
+	}
+	for k, v := range vars {
+		if _, set := os.LookupEnv(k); set && !dotenvKeys[k] {
+			continue
+		}
+		_ = os.Setenv(k, v)
+		dotenvKeys[k] = true
+	}
+}
+
+func (c *Config) loadFile(path string) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch ext := filepath.Ext(path); ext {
+	case ".yaml", ".yml":
+		dec := yaml.NewDecoder(bytes.NewReader(data))
+		dec.KnownFields(true)
+		err = dec.Decode(c)
+		if errors.Is(err, io.EOF) {
+			err = nil // empty file
+		}
+	case ".toml":
+		dec := toml.NewDecoder(bytes.NewReader(data))
+		dec.DisallowUnknownFields()
+		err = dec.Decode(c)
+	default:
+		return fmt.Errorf("unsupported config file extension %q, use .yaml, .yml or .toml", cmp.Or(ext, path))
+	}
+	if err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+
+	c.File = path
+	return nil
+}
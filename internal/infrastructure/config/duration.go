@@ -0,0 +1,28 @@
+package config
+
+import (
+	"strconv"
+	"time"
+)
+
+// Duration reads "30s", "5m" or plain seconds from YAML, TOML and the environment
+type Duration struct {
+	time.Duration
+}
+
+func (d *Duration) UnmarshalText(text []byte) error {
+	if secs, err := strconv.ParseInt(string(text), 10, 64); err == nil {
+		d.Duration = time.Duration(secs) * time.Second
+		return nil
+	}
+	v, err := time.ParseDuration(string(text))
+	if err != nil {
+		return err
+	}
+	d.Duration = v
+	return nil
+}
+
+func (d Duration) MarshalText() ([]byte, error) {
+	return []byte(d.String()), nil
+}
@@ -0,0 +1,128 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+)
+
+// loadEnv applies environment overrides. Variables that are set but can't be
+// parsed are reported instead of silently falling back to a default.
+func (c *Config) loadEnv() error {
+	var errs []error
+	str := func(key string, dst *string) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			*dst = v
+		}
+	}
+	list := func(key string, dst *[]string) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			*dst = splitList(v)
+		}
+	}
+	integer := func(key string, bits int, set func(int64)) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			n, err := strconv.ParseInt(v, 10, bits)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not an integer", key, v))
+				return
+			}
+			set(n)
+		}
+	}
+	// unsigned rejects negative values, which would wrap around in the conversion
+	unsigned := func(key string, bits int, set func(uint64)) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			n, err := strconv.ParseUint(v, 10, bits)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a non-negative integer", key, v))
+				return
+			}
+			set(n)
+		}
+	}
+	number := func(key string, dst *float64) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			f, err := strconv.ParseFloat(v, 64)
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a number", key, v))
+				return
+			}
+			*dst = f
+		}
+	}
+	duration := func(key string, dst *Duration) {
+		if v, ok := os.LookupEnv(key); ok && v != "" {
+			if err := dst.UnmarshalText([]byte(v)); err != nil {
+				errs = append(errs, fmt.Errorf("%s: %q is not a duration", key, v))
+			}
+		}
+	}
+
+	str("ENVIRONMENT", &c.Environment)
+	str("HTTP_PORT", &c.HTTP.Port)
+	duration("HTTP_READ_TIMEOUT", &c.HTTP.ReadTimeout)
+	duration("HTTP_SHUTDOWN_TIMEOUT", &c.HTTP.ShutdownTimeout)
+
+	str("DATABASE_HOST", &c.Database.URL)
+	integer("DATABASE_MAX_CONNS", 32, func(n int64) { c.Database.MaxConns = int32(n) })
+	integer("DATABASE_MIN_CONNS", 32, func(n int64) { c.Database.MinConns = int32(n) })
+	duration("DATABASE_CONNECT_TIMEOUT", &c.Database.ConnectTimeout)
+	integer("COMPRESS_AFTER_DAYS", 64, func(n int64) { c.Database.CompressAfterDays = n })
+	integer("RAW_RETENTION_DAYS", 64, func(n int64) { c.Database.RawRetentionDays = n })
+
+	str("LOG_FORMAT", &c.Log.Format)
+	str("LOG_LEVEL", &c.Log.Level)
+
+	duration("READ_COIN_INTERVAL", &c.Ingest.Interval) // seconds, kept for existing .env files
+	duration("INGEST_TIMEOUT", &c.Ingest.Timeout)
+	list("INGEST_SYMBOLS", &c.Ingest.Symbols)
+	unsigned("INGEST_TOP", 32, func(n uint64) { c.Ingest.Top = uint32(n) })
+	list("INGEST_QUOTE_CURRENCIES", &c.Ingest.QuoteCurrencies)
+	unsigned("INGEST_PAGE_SIZE", 32, func(n uint64) { c.Ingest.PageSize = uint32(n) })
+
+	list("CURRENCY_PROVIDER", &c.Providers.Priority)
+	str("PROVIDER_REPLAY_FILE", &c.Providers.ReplayFile)
+	str("COINGECKO_BASE_URL", &c.Providers.CoinGecko.BaseURL)
+	str("COINGECKO_PLAN", &c.Providers.CoinGecko.Plan)
+	str("COINGECKO_API_KEY", &c.Providers.CoinGecko.APIKey)
+	duration("COINGECKO_TIMEOUT", &c.Providers.CoinGecko.Timeout)
+	duration("COINGECKO_CONNECT_TIMEOUT", &c.Providers.CoinGecko.ConnectTimeout)
+	str("COINGECKO_USER_AGENT", &c.Providers.CoinGecko.UserAgent)
+	integer("COINGECKO_RETRY_MAX_ATTEMPTS", 32, func(n int64) { c.Providers.CoinGecko.Retry.MaxAttempts = int(n) })
+	duration("COINGECKO_RETRY_BACKOFF", &c.Providers.CoinGecko.Retry.Backoff)
+	duration("COINGECKO_RETRY_MAX_BACKOFF", &c.Providers.CoinGecko.Retry.MaxBackoff)
+	integer("COINGECKO_RATE_LIMIT", 32, func(n int64) { c.Providers.CoinGecko.RateLimit.RequestsPerMinute = int(n) })
+	integer("COINGECKO_RATE_BURST", 32, func(n int64) { c.Providers.CoinGecko.RateLimit.Burst = int(n) })
+
+	str("ADMIN_TOKEN", &c.Admin.Token)
+
+	duration("QUERY_MAX_STALENESS", &c.Query.MaxStaleness)
+
+	str("ALERTS_SIGNING_SECRET", &c.Alerts.SigningSecret)
+	duration("ALERTS_TIMEOUT", &c.Alerts.Timeout)
+	integer("ALERTS_RETRY_MAX_ATTEMPTS", 32, func(n int64) { c.Alerts.Retry.MaxAttempts = int(n) })
+	duration("ALERTS_RETRY_BACKOFF", &c.Alerts.Retry.Backoff)
+	duration("ALERTS_RETRY_MAX_BACKOFF", &c.Alerts.Retry.MaxBackoff)
+
+	str("ANOMALY_ACTION", &c.Anomaly.Action)
+	duration("ANOMALY_LOOKBACK", &c.Anomaly.Lookback)
+	number("ANOMALY_MAX_CHANGE_PCT", &c.Anomaly.MaxChangePct)
+	number("ANOMALY_Z_SCORE", &c.Anomaly.ZScore)
+	integer("ANOMALY_MIN_SAMPLES", 32, func(n int64) { c.Anomaly.MinSamples = int(n) })
+	integer("ANOMALY_CONFIRMATIONS", 32, func(n int64) { c.Anomaly.Confirmations = int(n) })
+
+	return errors.Join(errs...)
+}
+
+func splitList(v string) []string {
+	var out []string
+	for _, item := range strings.Split(v, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			out = append(out, item)
+		}
+	}
+	return out
+}
@@ -0,0 +1,45 @@
+package config
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLoadEnvUnsigned(t *testing.T) {
+	tests := []struct {
+		key, value string
+		want       uint32 // ignored when invalid
+		invalid    bool
+	}{
+		{"INGEST_TOP", "25", 25, false},
+		{"INGEST_TOP", "0", 0, false},
+		{"INGEST_TOP", "-1", 0, true},
+		{"INGEST_TOP", "4294967296", 0, true},
+		{"INGEST_TOP", "ten", 0, true},
+		{"INGEST_PAGE_SIZE", "250", 250, false},
+		{"INGEST_PAGE_SIZE", "-250", 0, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.key+"="+tt.value, func(t *testing.T) {
+			t.Setenv(tt.key, tt.value)
+			c := Default()
+			err := c.loadEnv()
+			if tt.invalid {
+				if err == nil || !strings.Contains(err.Error(), tt.key) {
+					t.Fatalf("loadEnv() error = %v, want one naming %s", err, tt.key)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("loadEnv() error = %v", err)
+			}
+			got := c.Ingest.Top
+			if tt.key == "INGEST_PAGE_SIZE" {
+				got = c.Ingest.PageSize
+			}
+			if got != tt.want {
+				t.Errorf("%s = %d, want %d", tt.key, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package config
+
+import (
+	"net/url"
+
+	"gopkg.in/yaml.v3"
+)
+
+const redacted = "xxxxx" // url.URL.Redacted uses the same mask
+
+// Redacted returns a copy that is safe to print: API keys are masked and the
+// database password is removed from the connection URL.
+func (c *Config) Redacted() *Config {
+	cp := *c
+	cp.Ingest.Symbols = append([]string(nil), c.Ingest.Symbols...)
+	cp.Ingest.QuoteCurrencies = append([]string(nil), c.Ingest.QuoteCurrencies...)
+	cp.Providers.Priority = append([]string(nil), c.Providers.Priority...)
+
+	cp.Database.URL = redactURL(c.Database.URL)
+	if cp.Providers.CoinGecko.APIKey != "" {
+		cp.Providers.CoinGecko.APIKey = redacted
+	}
+	if cp.Admin.Token != "" {
+		cp.Admin.Token = redacted
+	}
+	if cp.Alerts.SigningSecret != "" {
+		cp.Alerts.SigningSecret = redacted
+	}
+	return &cp
+}
+
+// YAML renders the configuration in the config file format
+func (c *Config) YAML() ([]byte, error) {
+	return yaml.Marshal(c)
+}
+
+func redactURL(raw string) string {
+	if raw == "" {
+		return ""
+	}
+	u, err := url.Parse(raw)
+	if err != nil || u.Scheme == "" {
+		// key=value DSNs may carry a password anywhere
+		return redacted
+	}
+	if q := u.Query(); q.Has("password") {
+		q.Set("password", redacted)
+		u.RawQuery = q.Encode()
+	}
+	return u.Redacted()
+}
@@ -0,0 +1,94 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"reflect"
+	"sync"
+	"sync/atomic"
+)
+
+// Store holds the live configuration. Readers take a snapshot with Current and
+// use it for a whole unit of work, so a reload never shows up half applied.
+type Store struct {
+	mu      sync.Mutex // serializes reloads
+	current atomic.Pointer[Config]
+	checks  []func(*Config) error
+}
+
+func NewStore(cfg *Config) *Store {
+	s := &Store{}
+	s.current.Store(cfg)
+	return s
+}
+
+// Current returns the configuration in effect. It must not be modified.
+func (s *Store) Current() *Config {
+	return s.current.Load()
+}
+
+// OnReload registers a check run against the next configuration before it is
+// published; an error aborts the reload and keeps the current configuration.
+func (s *Store) OnReload(check func(next *Config) error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.checks = append(s.checks, check)
+}
+
+// Reload reads the config file and environment again and publishes the
+// settings that can change at runtime: the ingest, query, alerts and anomaly
+// sections and the provider priority. It returns the new configuration and the
+// changed settings that only take effect after a restart.
+func (s *Store) Reload() (*Config, []string, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	loaded, err := Load()
+	if err != nil {
+		return nil, nil, err
+	}
+	if err := loaded.Validate(); err != nil {
+		return nil, nil, err
+	}
+
+	cur := s.current.Load()
+	next := *cur
+	next.Ingest = loaded.Ingest
+	next.Providers.Priority = loaded.Providers.Priority
+	next.Query = loaded.Query
+	next.Alerts = loaded.Alerts
+	next.Anomaly = loaded.Anomaly
+	next.File = loaded.File
+
+	var errs []error
+	for _, check := range s.checks {
+		if err := check(&next); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	if err := errors.Join(errs...); err != nil {
+		return nil, nil, fmt.Errorf("reload rejected: %w", err)
+	}
+
+	s.current.Store(&next)
+	return &next, restartRequired(cur, loaded), nil
+}
+
+// restartRequired lists the settings that differ between the running and the
+// loaded configuration but are only read at startup
+func restartRequired(cur, loaded *Config) []string {
+	var changed []string
+	diff := func(name string, a, b any) {
+		if !reflect.DeepEqual(a, b) {
+			changed = append(changed, name)
+		}
+	}
+	diff("environment", cur.Environment, loaded.Environment)
+	diff("http", cur.HTTP, loaded.HTTP)
+	diff("database", cur.Database, loaded.Database)
+	diff("log", cur.Log, loaded.Log)
+	diff("admin", cur.Admin, loaded.Admin)
+	diff("providers.replay_file", cur.Providers.ReplayFile, loaded.Providers.ReplayFile)
+	diff("providers.coingecko", cur.Providers.CoinGecko, loaded.Providers.CoinGecko)
+	return changed
+}
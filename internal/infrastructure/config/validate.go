@@ -0,0 +1,223 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+)
+
+const (
+	ProviderCoinGecko = "coingecko"
+	ProviderFake      = "fake"
+	ProviderReplay    = "replay"
+	ProviderRecord    = "record" // coingecko, saving every response for replay
+
+	AnomalyOff        = "off"
+	AnomalyFlag       = "flag"
+	AnomalyQuarantine = "quarantine"
+
+	CoinGeckoDemo = "demo"
+	CoinGeckoPro  = "pro"
+
+	// MaxPageSize is the largest page CoinGecko serves
+	MaxPageSize = 250
+
+	// MinRawRetentionDays keeps raw rows around longer than the widest continuous
+	// aggregate refresh window, otherwise a refresh would wipe materialized buckets.
+	MinRawRetentionDays = 31
+)
+
+var (
+	environments   = []string{"development", "staging", "production"}
+	providers      = []string{ProviderCoinGecko, ProviderFake, ProviderReplay, ProviderRecord}
+	anomalyActions = []string{AnomalyOff, AnomalyFlag, AnomalyQuarantine}
+	logFormats     = []string{"text", "json"}
+	logLevels      = []string{"", "debug", "info", "warn", "error"}
+)
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...any) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(slices.Contains(environments, c.Environment),
+		"environment: %q must be one of %s", c.Environment, strings.Join(environments, ", "))
+
+	port, err := strconv.Atoi(c.HTTP.Port)
+	check(err == nil && port > 0 && port <= 65535, "http.port: %q is not a valid port", c.HTTP.Port)
+	check(c.HTTP.ReadTimeout.Duration >= 0, "http.read_timeout: must not be negative")
+	check(c.HTTP.ShutdownTimeout.Duration > 0, "http.shutdown_timeout: must be positive")
+
+	if c.Database.URL == "" {
+		errs = append(errs, errors.New("database.url (DATABASE_HOST): is required"))
+	} else if _, err := pgxpool.ParseConfig(c.Database.URL); err != nil {
+		// the parse error may echo the URL, so don't include it
+		errs = append(errs, errors.New("database.url (DATABASE_HOST): is not a valid PostgreSQL connection string"))
+	}
+	check(c.Database.MaxConns >= 0 && c.Database.MinConns >= 0, "database: pool sizes must not be negative")
+	check(c.Database.MaxConns == 0 || c.Database.MinConns <= c.Database.MaxConns,
+		"database.min_conns: %d is larger than max_conns %d", c.Database.MinConns, c.Database.MaxConns)
+	check(c.Database.ConnectTimeout.Duration > 0, "database.connect_timeout: must be positive")
+	check(c.Database.CompressAfterDays >= 0, "database.compress_after_days: must not be negative")
+	check(c.Database.RawRetentionDays == 0 || c.Database.RawRetentionDays >= MinRawRetentionDays,
+		"database.raw_retention_days: must be 0 or at least %d, got %d", MinRawRetentionDays, c.Database.RawRetentionDays)
+
+	check(slices.Contains(logFormats, c.Log.Format), "log.format: %q must be text or json", c.Log.Format)
+	check(slices.Contains(logLevels, c.Log.Level), "log.level: %q must be debug, info, warn or error", c.Log.Level)
+
+	check(c.Query.MaxStaleness.Duration >= time.Second, "query.max_staleness: %s is shorter than 1s", c.Query.MaxStaleness)
+
+	check(c.Alerts.Timeout.Duration > 0, "alerts.timeout: must be positive")
+	errs = append(errs, c.Alerts.Retry.validate("alerts.retry")...)
+
+	check(slices.Contains(anomalyActions, c.Anomaly.Action),
+		"anomaly.action: %q must be off, flag or quarantine", c.Anomaly.Action)
+	check(c.Anomaly.Lookback.Duration >= time.Minute, "anomaly.lookback: %s is shorter than 1m", c.Anomaly.Lookback)
+	check(c.Anomaly.MaxChangePct >= 0, "anomaly.max_change_pct: must not be negative")
+	check(c.Anomaly.ZScore >= 0, "anomaly.z_score: must not be negative")
+	check(c.Anomaly.MinSamples >= 2, "anomaly.min_samples: must be at least 2")
+	check(c.Anomaly.Confirmations == 0 || c.Anomaly.Confirmations >= 2,
+		"anomaly.confirmations: %d must be 0 or at least 2", c.Anomaly.Confirmations)
+
+	errs = append(errs, c.Ingest.validate()...)
+	errs = append(errs, c.Providers.validate()...)
+	if err := c.checkBudget(); err != nil {
+		errs = append(errs, err)
+	}
+
+	return errors.Join(errs...)
+}
+
+func (i *Ingest) validate() []error {
+	var errs []error
+	if i.Interval.Duration < time.Second {
+		errs = append(errs, fmt.Errorf("ingest.interval: %s is shorter than 1s", i.Interval))
+	}
+	if i.Timeout.Duration <= 0 {
+		errs = append(errs, errors.New("ingest.timeout: must be positive"))
+	}
+	if len(i.Symbols) == 0 && i.Top == 0 {
+		errs = append(errs, errors.New("ingest: set symbols or a positive top"))
+	}
+	for _, s := range i.Symbols {
+		if s == "" || s != strings.ToLower(s) {
+			errs = append(errs, fmt.Errorf("ingest.symbols: %q must be a lowercase ticker", s))
+		}
+	}
+	if len(i.QuoteCurrencies) == 0 {
+		errs = append(errs, errors.New("ingest.quote_currencies: at least one is required"))
+	}
+	for _, q := range i.QuoteCurrencies {
+		if q == "" || q != strings.ToLower(q) {
+			errs = append(errs, fmt.Errorf("ingest.quote_currencies: %q must be a lowercase currency code", q))
+		}
+	}
+	if i.PageSize == 0 || i.PageSize > MaxPageSize {
+		errs = append(errs, fmt.Errorf("ingest.page_size: %d must be between 1 and %d", i.PageSize, MaxPageSize))
+	}
+	return errs
+}
+
+func (p *Providers) validate() []error {
+	var errs []error
+	if len(p.Priority) == 0 {
+		errs = append(errs, errors.New("providers.priority (CURRENCY_PROVIDER): at least one provider is required"))
+	}
+	for _, name := range p.Priority {
+		if !slices.Contains(providers, name) {
+			errs = append(errs, fmt.Errorf("providers.priority: unknown provider %q, expected one of %s",
+				name, strings.Join(providers, ", ")))
+		}
+		if (name == ProviderReplay || name == ProviderRecord) && p.ReplayFile == "" {
+			errs = append(errs, fmt.Errorf("providers.replay_file: is required by the %s provider", name))
+		}
+	}
+	return append(errs, p.CoinGecko.validate()...)
+}
+
+func (cg *CoinGecko) validate() []error {
+	var errs []error
+	switch cg.Plan {
+	case CoinGeckoDemo:
+	case CoinGeckoPro:
+		if cg.APIKey == "" {
+			errs = append(errs, errors.New("providers.coingecko.api_key (COINGECKO_API_KEY): is required by the pro plan"))
+		}
+	default:
+		errs = append(errs, fmt.Errorf("providers.coingecko.plan: %q must be demo or pro", cg.Plan))
+	}
+	if cg.BaseURL != "" {
+		u, err := url.Parse(cg.BaseURL)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			errs = append(errs, fmt.Errorf("providers.coingecko.base_url: %q is not an http(s) URL", cg.BaseURL))
+		}
+	}
+	if cg.Timeout.Duration <= 0 {
+		errs = append(errs, errors.New("providers.coingecko.timeout: must be positive"))
+	}
+	if cg.ConnectTimeout.Duration <= 0 {
+		errs = append(errs, errors.New("providers.coingecko.connect_timeout: must be positive"))
+	}
+	errs = append(errs, cg.Retry.validate("providers.coingecko.retry")...)
+	if cg.RateLimit.RequestsPerMinute < 0 {
+		errs = append(errs, errors.New("providers.coingecko.rate_limit.requests_per_minute: must not be negative"))
+	}
+	if cg.RateLimit.Burst < 1 {
+		errs = append(errs, errors.New("providers.coingecko.rate_limit.burst: must be at least 1"))
+	}
+	return errs
+}
+
+func (r *Retry) validate(prefix string) []error {
+	var errs []error
+	if r.MaxAttempts < 1 {
+		errs = append(errs, fmt.Errorf("%s.max_attempts: must be at least 1", prefix))
+	}
+	if r.Backoff.Duration < 0 {
+		errs = append(errs, fmt.Errorf("%s.backoff: must not be negative", prefix))
+	}
+	if r.MaxBackoff.Duration < r.Backoff.Duration {
+		errs = append(errs, fmt.Errorf("%s.max_backoff: must not be shorter than backoff", prefix))
+	}
+	return errs
+}
+
+// CallsPerTick is how many provider requests one ingestion makes
+func (i *Ingest) CallsPerTick() int {
+	if i.PageSize == 0 {
+		return 0
+	}
+	pages := i.Top
+	if len(i.Symbols) > 0 {
+		pages = uint32(len(i.Symbols))
+	}
+	return len(i.QuoteCurrencies) * int((pages+i.PageSize-1)/i.PageSize)
+}
+
+// checkBudget rejects an ingest schedule that needs more CoinGecko calls per
+// interval than the rate limit allows, which would fall further behind every tick
+func (c *Config) checkBudget() error {
+	if !slices.Contains(c.Providers.Priority, ProviderCoinGecko) && !slices.Contains(c.Providers.Priority, ProviderRecord) {
+		return nil
+	}
+	if c.Ingest.Interval.Duration <= 0 {
+		return nil
+	}
+	calls := c.Ingest.CallsPerTick()
+	allowed := int(float64(c.Providers.CoinGecko.Rate()) * c.Ingest.Interval.Minutes())
+	if calls > allowed {
+		return fmt.Errorf("ingest: needs %d CoinGecko calls per %s but the rate limit allows %d, "+
+			"raise the interval or page_size or track fewer symbols", calls, c.Ingest.Interval, allowed)
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package fake
+
+import (
+	"context"
+	"log/slog"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/synthetic"
+	"github.com/milad-rasouli/price/internal/providers/currency"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// Seed keeps the generated series identical between runs
+	Seed = 42
+
+	// RangeStep is the spacing of ticks returned by GetRange
+	RangeStep = 5 * time.Minute
+
+	// startMarketCap is the market cap of every coin at its start price; the
+	// circulating supply follows from it and stays fixed
+	startMarketCap = 10_000_000_000
+	// volumeShare is the daily volume as a share of the market cap
+	volumeShare = "0.04"
+)
+
+// quoteRates converts the USD walks into the other supported quote currencies
+var quoteRates = map[string]decimal.Decimal{
+	"usd": decimal.NewFromInt(1),
+	"eur": decimal.RequireFromString("0.92"),
+	"gbp": decimal.RequireFromString("0.79"),
+}
+
+// Fake is an offline CurrencyProvider. The walk of each symbol advances one
+// step per ingestion interval, so every quote currency requested within the
+// same interval sees the same price.
+type Fake struct {
+	mu       sync.Mutex
+	assets   []synthetic.Asset
+	walks    []*synthetic.Walk
+	lastTick []int64
+	step     time.Duration
+	logger   *slog.Logger
+}
+
+func NewFake(cfg *config.Config, logger *slog.Logger) (*Fake, error) {
+	assets, err := synthetic.ParseSymbols(synthetic.DefaultSymbols)
+	if err != nil {
+		return nil, err
+	}
+
+	walks := make([]*synthetic.Walk, 0, len(assets))
+	for _, a := range assets {
+		walks = append(walks, synthetic.NewWalk(a.Symbol, a.Start, 0.05, 0.6, Seed))
+	}
+	return &Fake{
+		assets:   assets,
+		walks:    walks,
+		lastTick: make([]int64, len(walks)),
+		step:     cfg.Ingest.Interval.Duration,
+		logger:   logger.With("provider", "fake"),
+	}, nil
+}
+
+func (f *Fake) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	rate, ok := quoteRates[q.Quote]
+	if !ok {
+		return nil, currency.ErrCurrencyNotFound
+	}
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var selected []int
+	if len(q.Symbols) > 0 {
+		for i, w := range f.walks {
+			if slices.Contains(q.Symbols, w.Symbol) {
+				selected = append(selected, i)
+			}
+		}
+	} else {
+		start := int(q.Page-1) * int(q.Limit)
+		for i := start; q.Page > 0 && i < len(f.walks) && i < start+int(q.Limit); i++ {
+			selected = append(selected, i)
+		}
+	}
+	if len(selected) == 0 {
+		return nil, currency.ErrCurrencyNotFound
+	}
+
+	now := time.Now()
+	tick := now.UnixNano() / int64(f.step)
+	result := make([]*entity.Price, 0, len(selected))
+	for _, i := range selected {
+		w := f.walks[i]
+		if f.lastTick[i] != tick {
+			w.Next(f.step)
+			f.lastTick[i] = tick
+		}
+		price := decimal.NewFromFloat(w.Price).Mul(rate)
+		result = append(result, &entity.Price{
+			Symbol: entity.PairSymbol(w.Symbol, q.Quote),
+			Price:  price,
+			Time:   now.Unix(),
+			Market: market(price, f.assets[i].Start),
+			Asset:  &entity.Asset{Name: strings.ToUpper(w.Symbol), ProviderIDs: map[string]string{config.ProviderFake: w.Symbol}},
+		})
+	}
+
+	f.logger.Info("generated fake prices", "count", len(result))
+	return result, nil
+}
+
+// GetRange generates a deterministic walk for the range; the same request
+// always yields the same ticks.
+func (f *Fake) GetRange(ctx context.Context, symbol string, from, to int64) ([]*entity.Price, error) {
+	for _, a := range f.assets {
+		if a.Symbol != symbol {
+			continue
+		}
+
+		w := synthetic.NewWalk(a.Symbol, a.Start, 0.05, 0.6, Seed^uint64(from))
+		var result []*entity.Price
+		err := w.Generate(time.Unix(from, 0), time.Unix(to, 0), RangeStep, 1024, func(batch []*entity.Price) error {
+			result = append(result, batch...)
+			return nil
+		})
+		if err != nil {
+			return nil, err
+		}
+		f.logger.Info("generated fake price range", "symbol", symbol, "count", len(result))
+		return result, nil
+	}
+	return nil, currency.ErrCurrencyNotFound
+}
+
+func market(price decimal.Decimal, start float64) *entity.Market {
+	supply := decimal.NewFromInt(startMarketCap).Div(decimal.NewFromFloat(start)).Round(0)
+	marketCap := price.Mul(supply)
+	return &entity.Market{
+		MarketCap:         decimal.NewNullDecimal(marketCap),
+		TotalVolume:       decimal.NewNullDecimal(marketCap.Mul(decimal.RequireFromString(volumeShare))),
+		CirculatingSupply: decimal.NewNullDecimal(supply),
+	}
+}
@@ -0,0 +1,172 @@
+package postgresql
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+
+	"github.com/golang-migrate/migrate/v4"
+	pgxmigrate "github.com/golang-migrate/migrate/v4/database/pgx/v5"
+	"github.com/golang-migrate/migrate/v4/source/iofs"
+	"github.com/jackc/pgx/v5"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/migration"
+)
+
+var ErrSchemaOutdated = errors.New("database schema is outdated")
+
+// Migrator applies the embedded migrations. It keeps its state in the same
+// schema_migrations table as the migrate CLI, so both can be used on one database.
+type Migrator struct {
+	db *sql.DB
+	m  *migrate.Migrate
+}
+
+type MigrationStatus struct {
+	Version uint
+	Dirty   bool
+	Latest  uint
+}
+
+func NewMigrator(cfg *config.Config) (*Migrator, error) {
+	db, err := sql.Open("pgx", cfg.Database.URL)
+	if err != nil {
+		return nil, err
+	}
+
+	driver, err := pgxmigrate.WithInstance(db, &pgxmigrate.Config{})
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to create migration driver: %w", err)
+	}
+	source, err := iofs.New(migration.FS, ".")
+	if err != nil {
+		_ = db.Close()
+		return nil, fmt.Errorf("failed to read embedded migrations: %w", err)
+	}
+	m, err := migrate.NewWithInstance("iofs", source, "pgx", driver)
+	if err != nil {
+		_ = db.Close()
+		return nil, err
+	}
+	return &Migrator{db: db, m: m}, nil
+}
+
+// Up applies every pending migration
+func (mg *Migrator) Up() error {
+	return mg.applied(mg.m.Up())
+}
+
+// Down rolls back the given number of migrations
+func (mg *Migrator) Down(steps int) error {
+	return mg.applied(mg.m.Steps(-steps))
+}
+
+// Goto migrates up or down to the given version
+func (mg *Migrator) Goto(version uint) error {
+	return mg.applied(mg.m.Migrate(version))
+}
+
+// Force sets the version without running anything, to recover from a dirty state
+func (mg *Migrator) Force(version int) error {
+	return mg.m.Force(version)
+}
+
+func (mg *Migrator) Status() (*MigrationStatus, error) {
+	latest, err := migration.Latest()
+	if err != nil {
+		return nil, err
+	}
+	version, dirty, err := mg.m.Version()
+	if err != nil && !errors.Is(err, migrate.ErrNilVersion) {
+		return nil, err
+	}
+	return &MigrationStatus{Version: version, Dirty: dirty, Latest: latest}, nil
+}
+
+func (mg *Migrator) Close() error {
+	srcErr, dbErr := mg.m.Close()
+	return errors.Join(srcErr, dbErr, mg.db.Close())
+}
+
+// applied refreshes the price aggregates once migrations actually ran, since a
+// migration may have recreated them empty
+func (mg *Migrator) applied(err error) error {
+	if errors.Is(err, migrate.ErrNoChange) {
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	return mg.refreshAggregates()
+}
+
+// aggregateEndOffsets match the end_offset of each aggregate's refresh policy,
+// so buckets still filling up are left to real-time aggregation
+var aggregateEndOffsets = map[string]int64{
+	"coin_prices_1m": 60,
+	"coin_prices_1h": 3600,
+	"coin_prices_1d": 86400,
+}
+
+// refreshAggregates materializes every bucket that is not materialized yet.
+// Regions without invalidations are skipped by TimescaleDB, so this is cheap
+// when nothing was rebuilt. It can't run inside a migration's transaction.
+func (mg *Migrator) refreshAggregates() error {
+	rows, err := mg.db.Query(`SELECT view_name FROM timescaledb_information.continuous_aggregates
+		WHERE hypertable_name = 'coin_prices'`)
+	if err != nil {
+		return fmt.Errorf("failed to list price aggregates: %w", err)
+	}
+	var views []string
+	for rows.Next() {
+		var view string
+		if err := rows.Scan(&view); err != nil {
+			_ = rows.Close()
+			return err
+		}
+		views = append(views, view)
+	}
+	if err := errors.Join(rows.Err(), rows.Close()); err != nil {
+		return err
+	}
+
+	for _, view := range views {
+		offset, ok := aggregateEndOffsets[view]
+		if !ok {
+			continue
+		}
+		_, err := mg.db.Exec(`CALL refresh_continuous_aggregate($1::regclass, NULL, coin_prices_unix_now() - $2::BIGINT)`, view, offset)
+		if err != nil {
+			return fmt.Errorf("failed to refresh %s: %w", view, err)
+		}
+	}
+	return nil
+}
+
+// CheckSchema fails when the database is behind the migrations embedded in
+// this binary or a previous migration was left dirty.
+func (p *Postgres) CheckSchema(ctx context.Context) error {
+	latest, err := migration.Latest()
+	if err != nil {
+		return err
+	}
+
+	var (
+		version int64
+		dirty   bool
+	)
+	err = p.Pool.QueryRow(ctx, `SELECT version, dirty FROM schema_migrations LIMIT 1`).Scan(&version, &dirty)
+	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+		return fmt.Errorf("failed to read schema version: %w", err)
+	}
+
+	if dirty {
+		return fmt.Errorf("%w: version %d is dirty", ErrSchemaOutdated, version)
+	}
+	if version < int64(latest) {
+		return fmt.Errorf("%w: version %d, expected %d", ErrSchemaOutdated, version, latest)
+	}
+	return nil
+}
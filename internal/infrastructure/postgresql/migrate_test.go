@@ -0,0 +1,59 @@
+package postgresql_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+)
+
+// TestMigrationsRoundTrip rolls every migration back one at a time and applies
+// them again, so each down migration has to undo all of its up migration.
+func TestMigrationsRoundTrip(t *testing.T) {
+	cfg := pgtest.Config(t)
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	status, err := mg.Status()
+	if err != nil {
+		t.Fatalf("Status() error = %v", err)
+	}
+	if status.Version != status.Latest || status.Dirty {
+		t.Fatalf("after Up() at version %d (dirty %v), want %d", status.Version, status.Dirty, status.Latest)
+	}
+	db := pgtest.Connect(t, cfg)
+	if err := db.CheckSchema(context.Background()); err != nil {
+		t.Fatalf("CheckSchema() error = %v", err)
+	}
+
+	for v := status.Latest; v > 1; v-- {
+		if err := mg.Down(1); err != nil {
+			t.Fatalf("rolling back version %d: %v", v, err)
+		}
+		got, err := mg.Status()
+		if err != nil {
+			t.Fatalf("Status() error = %v", err)
+		}
+		if got.Version != v-1 || got.Dirty {
+			t.Fatalf("after rolling back version %d at %d (dirty %v)", v, got.Version, got.Dirty)
+		}
+	}
+	if err := db.CheckSchema(context.Background()); !errors.Is(err, postgresql.ErrSchemaOutdated) {
+		t.Fatalf("CheckSchema() at version 1 error = %v, want ErrSchemaOutdated", err)
+	}
+
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() after rolling back error = %v", err)
+	}
+	if err := db.CheckSchema(context.Background()); err != nil {
+		t.Fatalf("CheckSchema() error = %v", err)
+	}
+}
@@ -0,0 +1,89 @@
+// Package pgtest gives integration tests a database of their own on the
+// TimescaleDB server named by TEST_DATABASE_HOST, e.g. the one of docker-compose.yml.
+package pgtest
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+	"testing"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+)
+
+// EnvHost holds the URL of a server the tests may create databases on;
+// tests needing one are skipped while it is unset
+const EnvHost = "TEST_DATABASE_HOST"
+
+// Config creates an empty database for the test, dropped when it ends, and
+// returns a configuration pointing at it
+func Config(t testing.TB) *config.Config {
+	t.Helper()
+	host := os.Getenv(EnvHost)
+	if host == "" {
+		t.Skipf("%s is not set", EnvHost)
+	}
+	u, err := url.Parse(host)
+	if err != nil {
+		t.Fatalf("invalid %s: %v", EnvHost, err)
+	}
+
+	ctx := context.Background()
+	conn, err := pgx.Connect(ctx, host)
+	if err != nil {
+		t.Fatalf("failed to connect to %s: %v", EnvHost, err)
+	}
+	defer conn.Close(ctx)
+
+	// test binaries of several packages run at once
+	name := fmt.Sprintf("price_test_%d_%d", os.Getpid(), time.Now().UnixNano())
+	if _, err := conn.Exec(ctx, "CREATE DATABASE "+name); err != nil {
+		t.Fatalf("failed to create database %s: %v", name, err)
+	}
+	t.Cleanup(func() {
+		conn, err := pgx.Connect(ctx, host)
+		if err != nil {
+			t.Errorf("failed to connect to %s: %v", EnvHost, err)
+			return
+		}
+		defer conn.Close(ctx)
+		if _, err := conn.Exec(ctx, "DROP DATABASE "+name+" WITH (FORCE)"); err != nil {
+			t.Errorf("failed to drop database %s: %v", name, err)
+		}
+	})
+
+	u.Path = "/" + name
+	cfg := config.Default()
+	cfg.Database.URL = u.String()
+	return cfg
+}
+
+// Migrate creates a database for the test with every migration applied and connects to it
+func Migrate(t testing.TB) *postgresql.Postgres {
+	t.Helper()
+	cfg := Config(t)
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	return Connect(t, cfg)
+}
+
+// Connect opens a pool on the database of cfg, closed when the test ends
+func Connect(t testing.TB, cfg *config.Config) *postgresql.Postgres {
+	t.Helper()
+	db := postgresql.NewPostgre(cfg)
+	if err := db.Setup(context.Background()); err != nil {
+		t.Fatalf("Setup() error = %v", err)
+	}
+	t.Cleanup(db.Pool.Close)
+	return db
+}
@@ -3,25 +3,35 @@ package postgresql
 import (
 	"context"
 	"fmt"
-	"github.com/milad-rasouli/price/internal/infrastructure/godotenv"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 
 	"github.com/jackc/pgx/v5/pgxpool"
 	_ "github.com/jackc/pgx/v5/stdlib"
 )
 
 type Postgres struct {
-	env  *godotenv.Env
+	cfg  *config.Config
 	Pool *pgxpool.Pool
 }
 
-func NewPostgre(env *godotenv.Env) *Postgres {
+func NewPostgre(cfg *config.Config) *Postgres {
 	return &Postgres{
-		env: env,
+		cfg: cfg,
 	}
 }
 
 func (p *Postgres) Setup(ctx context.Context) error {
-	pool, err := pgxpool.New(ctx, p.env.DatabaseHost)
+	poolConfig, err := pgxpool.ParseConfig(p.cfg.Database.URL)
+	if err != nil {
+		return err
+	}
+	if p.cfg.Database.MaxConns > 0 {
+		poolConfig.MaxConns = p.cfg.Database.MaxConns
+	}
+	poolConfig.MinConns = p.cfg.Database.MinConns
+	poolConfig.ConnConfig.ConnectTimeout = p.cfg.Database.ConnectTimeout.Duration
+
+	pool, err := pgxpool.NewWithConfig(ctx, poolConfig)
 	if err != nil {
 		return err
 	}
@@ -42,6 +52,62 @@ func (p *Postgres) HealthCheck(ctx context.Context) error {
 	return nil
 }
 
+// policyTables are the raw hypertables that compression and retention apply to
+var policyTables = []string{"coin_prices", "coin_markets"}
+
+// retentionJobs are the raw hypertables whose retention runs a procedure from
+// the migrations instead of the plain policy; it gets drop_after in its config
+var retentionJobs = map[string]string{"coin_prices": "drop_raw_prices"}
+
+// ApplyPolicies (re)creates the compression and retention policies on the raw
+// hypertables from configuration. A zero value removes the corresponding policy.
+func (p *Postgres) ApplyPolicies(ctx context.Context) error {
+	for _, table := range policyTables {
+		if err := p.applyPolicies(ctx, table); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (p *Postgres) applyPolicies(ctx context.Context, table string) error {
+	if _, err := p.Pool.Exec(ctx, `SELECT remove_compression_policy($1::regclass, if_exists => true)`, table); err != nil {
+		return fmt.Errorf("failed to remove compression policy on %s: %w", table, err)
+	}
+	if p.cfg.Database.CompressAfterDays > 0 {
+		_, err := p.Pool.Exec(ctx, `SELECT add_compression_policy($1::regclass, compress_after => $2::BIGINT)`,
+			table, p.cfg.Database.CompressAfterDays*86400)
+		if err != nil {
+			return fmt.Errorf("failed to add compression policy on %s: %w", table, err)
+		}
+	}
+
+	if _, err := p.Pool.Exec(ctx, `SELECT remove_retention_policy($1::regclass, if_exists => true)`, table); err != nil {
+		return fmt.Errorf("failed to remove retention policy on %s: %w", table, err)
+	}
+	proc, job := retentionJobs[table]
+	if job {
+		_, err := p.Pool.Exec(ctx, `SELECT delete_job(job_id) FROM timescaledb_information.jobs WHERE proc_name = $1`, proc)
+		if err != nil {
+			return fmt.Errorf("failed to remove retention job on %s: %w", table, err)
+		}
+	}
+	if p.cfg.Database.RawRetentionDays > 0 {
+		var err error
+		if job {
+			_, err = p.Pool.Exec(ctx, `SELECT add_job($1::regproc, INTERVAL '1 day', config => jsonb_build_object('drop_after', $2::BIGINT))`,
+				proc, p.cfg.Database.RawRetentionDays*86400)
+		} else {
+			_, err = p.Pool.Exec(ctx, `SELECT add_retention_policy($1::regclass, drop_after => $2::BIGINT)`,
+				table, p.cfg.Database.RawRetentionDays*86400)
+		}
+		if err != nil {
+			return fmt.Errorf("failed to add retention policy on %s: %w", table, err)
+		}
+	}
+	return nil
+}
+
 func (p *Postgres) Close() {
 	if p.Pool != nil {
 		p.Pool.Close()
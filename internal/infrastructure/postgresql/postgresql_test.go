@@ -0,0 +1,99 @@
+package postgresql_test
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	pricepgx "github.com/milad-rasouli/price/internal/repository/repository/price/pgx"
+	"github.com/shopspring/decimal"
+)
+
+// TestRawRetentionJob runs the retention job ApplyPolicies schedules on coin_prices
+func TestRawRetentionJob(t *testing.T) {
+	ctx := context.Background()
+	cfg := pgtest.Config(t)
+	cfg.Database.CompressAfterDays = 0
+	cfg.Database.RawRetentionDays = 7
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	db := pgtest.Connect(t, cfg)
+
+	now := time.Now().Unix()
+	old := now - 30*86400
+	prices := []*entity.Price{
+		{Symbol: "btc", Price: decimal.NewFromInt(100), Time: old},
+		{Symbol: "btc", Price: decimal.NewFromInt(101), Time: now},
+		{Symbol: "ltc", Price: decimal.NewFromInt(1), Time: old - 60*86400},
+	}
+	if err := pricepgx.NewPriceRepository(db.Pool).BatchInsert(ctx, prices); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+	// ltc was dropped by an earlier run, so its chunk is gone while its catalog row is left
+	_, err = db.Pool.Exec(ctx, `SELECT drop_chunks('coin_prices', older_than => $1::BIGINT)`, old-50*86400)
+	if err != nil {
+		t.Fatalf("drop_chunks() error = %v", err)
+	}
+
+	// applied twice, as every start of the API does
+	for range 2 {
+		if err := db.ApplyPolicies(ctx); err != nil {
+			t.Fatalf("ApplyPolicies() error = %v", err)
+		}
+	}
+	var job int
+	err = db.Pool.QueryRow(ctx, `SELECT job_id FROM timescaledb_information.jobs WHERE proc_name = 'drop_raw_prices'`).Scan(&job)
+	if err != nil {
+		t.Fatalf("looking up the retention job: %v", err)
+	}
+	if _, err := db.Pool.Exec(ctx, `CALL run_job($1)`, job); err != nil {
+		t.Fatalf("run_job() error = %v", err)
+	}
+
+	var times []int64
+	rows, err := db.Pool.Query(ctx, `SELECT time FROM coin_prices ORDER BY time`)
+	if err != nil {
+		t.Fatalf("reading prices: %v", err)
+	}
+	for rows.Next() {
+		var at int64
+		if err := rows.Scan(&at); err != nil {
+			t.Fatalf("reading prices: %v", err)
+		}
+		times = append(times, at)
+	}
+	if err := rows.Err(); err != nil {
+		t.Fatalf("reading prices: %v", err)
+	}
+	if len(times) != 1 || times[0] != now {
+		t.Errorf("prices left at %v, want only %d", times, now)
+	}
+
+	tests := []struct {
+		symbol   string
+		revision int64
+		rows     int64
+	}{
+		{"btc", 1, 2}, // rows dropped by retention are still counted
+		{"ltc", 0, 1},
+	}
+	for _, tt := range tests {
+		var revision, count int64
+		err := db.Pool.QueryRow(ctx, `SELECT revision, row_count FROM symbols WHERE symbol = $1`, tt.symbol).Scan(&revision, &count)
+		if err != nil {
+			t.Fatalf("reading %s from the catalog: %v", tt.symbol, err)
+		}
+		if revision != tt.revision || count != tt.rows {
+			t.Errorf("%s revision %d with %d rows, want %d with %d", tt.symbol, revision, count, tt.revision, tt.rows)
+		}
+	}
+}
@@ -0,0 +1,130 @@
+// Package ratelimit paces calls to upstream price providers so that ingestion
+// and backfills together stay under the provider's plan limits.
+package ratelimit
+
+import (
+	"context"
+	"errors"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+)
+
+// ErrBudgetExceeded means the call can't be made before the caller's deadline
+var ErrBudgetExceeded = errors.New("rate limit budget exceeded")
+
+// slowdown is how far the rate may drop below the configured one after
+// repeated throttling
+const slowdown = 16
+
+// Limiter is a token bucket shared by every caller of one provider. A 429
+// pauses all callers until Retry-After and halves the rate, which then
+// recovers gradually with each successful call.
+type Limiter struct {
+	bucket *rate.Limiter
+	max    rate.Limit
+
+	mu    sync.Mutex
+	until time.Time // no calls before this
+}
+
+// New allows perMinute calls with bursts of up to burst calls. A perMinute of
+// 0 or less disables limiting, but Retry-After is still honoured.
+func New(perMinute, burst int) *Limiter {
+	limit := rate.Inf
+	if perMinute > 0 {
+		limit = rate.Limit(float64(perMinute) / 60)
+	}
+	return &Limiter{
+		bucket: rate.NewLimiter(limit, max(burst, 1)),
+		max:    limit,
+	}
+}
+
+// Wait blocks until a call is allowed. It fails right away when ctx would
+// expire before that, so a caller with a deadline doesn't sleep in vain.
+func (l *Limiter) Wait(ctx context.Context) error {
+	l.mu.Lock()
+	pause := time.Until(l.until)
+	l.mu.Unlock()
+
+	if pause > 0 {
+		if deadline, ok := ctx.Deadline(); ok && time.Until(deadline) < pause {
+			return ErrBudgetExceeded
+		}
+		t := time.NewTimer(pause)
+		select {
+		case <-t.C:
+		case <-ctx.Done():
+			t.Stop()
+			return ctx.Err()
+		}
+	}
+
+	if err := l.bucket.Wait(ctx); err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return ErrBudgetExceeded
+	}
+	return nil
+}
+
+// Throttled records a 429: nobody calls again for wait and the rate halves
+func (l *Limiter) Throttled(wait time.Duration) {
+	l.mu.Lock()
+	if until := time.Now().Add(wait); until.After(l.until) {
+		l.until = until
+	}
+	l.mu.Unlock()
+
+	if l.max == rate.Inf {
+		return
+	}
+	l.bucket.SetLimit(max(l.bucket.Limit()/2, l.max/slowdown))
+}
+
+// Succeeded lets a throttled rate recover by a tenth of the configured one
+func (l *Limiter) Succeeded() {
+	if l.max == rate.Inf {
+		return
+	}
+	if cur := l.bucket.Limit(); cur < l.max {
+		l.bucket.SetLimit(min(cur+l.max/10, l.max))
+	}
+}
+
+// Backoff returns a random wait in [0, base * 2^(attempt-1)], capped at
+// maxWait ("full jitter"), so callers that failed together don't retry together.
+func Backoff(base, maxWait time.Duration, attempt int) time.Duration {
+	if base <= 0 {
+		return 0
+	}
+	ceiling := maxWait
+	if attempt <= 30 {
+		if d := base << (attempt - 1); d > 0 && d < maxWait {
+			ceiling = d
+		}
+	}
+	return rand.N(ceiling + 1)
+}
+
+// RetryAfter parses a Retry-After header holding seconds or an HTTP date
+func RetryAfter(header string, now time.Time) (time.Duration, bool) {
+	header = strings.TrimSpace(header)
+	if header == "" {
+		return 0, false
+	}
+	if secs, err := strconv.Atoi(header); err == nil {
+		return time.Duration(max(secs, 0)) * time.Second, true
+	}
+	if t, err := http.ParseTime(header); err == nil {
+		return max(t.Sub(now), 0), true
+	}
+	return 0, false
+}
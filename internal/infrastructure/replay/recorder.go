@@ -0,0 +1,72 @@
+package replay
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/providers/currency"
+)
+
+// Recorder wraps a real provider and appends every successful response to
+// an NDJSON file that Replay can serve later.
+type Recorder struct {
+	mu     sync.Mutex
+	next   currency.CurrencyProvider
+	path   string
+	logger *slog.Logger
+}
+
+func NewRecorder(next currency.CurrencyProvider, path string, logger *slog.Logger) *Recorder {
+	return &Recorder{
+		next:   next,
+		path:   path,
+		logger: logger.With("provider", "recorder"),
+	}
+}
+
+func (r *Recorder) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	prices, err := r.next.Get(ctx, q)
+	if err != nil {
+		return nil, err
+	}
+
+	rec := &Record{
+		Quote:      q.Quote,
+		Symbols:    q.Symbols,
+		Page:       q.Page,
+		Limit:      q.Limit,
+		RecordedAt: time.Now().Unix(),
+		Prices:     prices,
+	}
+	if err := r.append(rec); err != nil {
+		// recording is best effort, the caller still gets its prices
+		r.logger.Error("failed to record response", "path", r.path, "error", err)
+	}
+	return prices, nil
+}
+
+func (r *Recorder) append(rec *Record) error {
+	line, err := json.Marshal(rec)
+	if err != nil {
+		return err
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0o644)
+	if err != nil {
+		return fmt.Errorf("failed to open record file: %w", err)
+	}
+	if _, err := f.Write(append(line, '\n')); err != nil {
+		_ = f.Close()
+		return err
+	}
+	return f.Close()
+}
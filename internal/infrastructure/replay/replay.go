@@ -0,0 +1,115 @@
+package replay
+
+import (
+	"bufio"
+	"bytes"
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log/slog"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/providers/currency"
+)
+
+// Record is one provider response as saved by the Recorder
+type Record struct {
+	Quote      string          `json:"quote,omitempty"` // empty in older recordings, meaning usd
+	Symbols    []string        `json:"symbols,omitempty"`
+	Page       uint32          `json:"page"`
+	Limit      uint32          `json:"limit"`
+	RecordedAt int64           `json:"recorded_at"`
+	Prices     []*entity.Price `json:"prices"`
+}
+
+func (r *Record) key() string {
+	return fmt.Sprintf("%s|%s|%d|%d", cmp.Or(r.Quote, entity.DefaultQuote), strings.Join(r.Symbols, ","), r.Page, r.Limit)
+}
+
+// Replay serves recorded responses in order and starts over at the end.
+// Timestamps are shifted so each replayed tick is as old, relative to now,
+// as it was when recorded; otherwise a second loop would collide with rows
+// stored by the first one.
+type Replay struct {
+	mu      sync.Mutex
+	records []*Record
+	next    map[string]int
+	logger  *slog.Logger
+}
+
+// NewReplay loads a JSON array or NDJSON file of records
+func NewReplay(path string, logger *slog.Logger) (*Replay, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read replay file: %w", err)
+	}
+
+	var records []*Record
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		err = json.Unmarshal(trimmed, &records)
+	} else {
+		records, err = decodeNDJSON(data)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode replay file %s: %w", path, err)
+	}
+	if len(records) == 0 {
+		return nil, fmt.Errorf("replay file %s has no records", path)
+	}
+
+	return &Replay{
+		records: records,
+		next:    make(map[string]int),
+		logger:  logger.With("provider", "replay"),
+	}, nil
+}
+
+func (r *Replay) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	key := (&Record{Quote: q.Quote, Symbols: q.Symbols, Page: q.Page, Limit: q.Limit}).key()
+	for i := 0; i < len(r.records); i++ {
+		idx := (r.next[key] + i) % len(r.records)
+		rec := r.records[idx]
+		if rec.key() != key {
+			continue
+		}
+		r.next[key] = idx + 1
+
+		shift := time.Now().Unix() - rec.RecordedAt
+		result := make([]*entity.Price, 0, len(rec.Prices))
+		for _, p := range rec.Prices {
+			shifted := *p
+			shifted.Time += shift
+			result = append(result, &shifted)
+		}
+		r.logger.Info("replayed prices", "record", idx, "count", len(result))
+		return result, nil
+	}
+
+	r.logger.Warn("no recorded response", "quote", q.Quote, "page", q.Page, "limit", q.Limit)
+	return nil, currency.ErrCurrencyNotFound
+}
+
+func decodeNDJSON(data []byte) ([]*Record, error) {
+	var records []*Record
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 16*1024*1024)
+	for line := 1; scanner.Scan(); line++ {
+		if len(bytes.TrimSpace(scanner.Bytes())) == 0 {
+			continue
+		}
+		var rec Record
+		if err := json.Unmarshal(scanner.Bytes(), &rec); err != nil {
+			return nil, fmt.Errorf("line %d: %w", line, err)
+		}
+		records = append(records, &rec)
+	}
+	return records, scanner.Err()
+}
@@ -0,0 +1,115 @@
+// Package synthetic generates realistic looking price series for local development.
+package synthetic
+
+import (
+	"fmt"
+	"math"
+	"math/rand/v2"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/shopspring/decimal"
+)
+
+const secondsPerYear = 365 * 24 * 60 * 60
+
+// DefaultSymbols mixes large caps with a micro-cap priced far below a cent
+const DefaultSymbols = "btc:65000,eth:3200,sol:150,doge:0.15,shib:0.000018"
+
+// Asset is a symbol with the price its walk starts from
+type Asset struct {
+	Symbol string
+	Start  float64
+}
+
+// ParseSymbols parses comma separated symbol:start_price pairs
+func ParseSymbols(spec string) ([]Asset, error) {
+	var assets []Asset
+	for _, pair := range strings.Split(spec, ",") {
+		symbol, start, ok := strings.Cut(strings.TrimSpace(pair), ":")
+		if !ok || symbol == "" {
+			return nil, fmt.Errorf("invalid symbol %q, expected symbol:start_price", pair)
+		}
+		price, err := strconv.ParseFloat(start, 64)
+		if err != nil || price <= 0 {
+			return nil, fmt.Errorf("invalid start price %q for %s", start, symbol)
+		}
+		assets = append(assets, Asset{Symbol: strings.ToLower(symbol), Start: price})
+	}
+	return assets, nil
+}
+
+// Walk is a geometric random walk. Drift and Volatility are annualized, so a
+// Volatility of 0.6 gives the ~60% yearly volatility typical for large coins.
+type Walk struct {
+	Symbol     string
+	Price      float64
+	Drift      float64
+	Volatility float64
+
+	// GapProbability is the chance that a step starts an outage of up to MaxGap
+	GapProbability float64
+	MaxGap         time.Duration
+
+	rng *rand.Rand
+}
+
+func NewWalk(symbol string, start, drift, volatility float64, seed uint64) *Walk {
+	return &Walk{
+		Symbol:     symbol,
+		Price:      start,
+		Drift:      drift,
+		Volatility: volatility,
+		rng:        rand.New(rand.NewPCG(seed, hash(symbol))),
+	}
+}
+
+// Next advances the walk by step and returns the new price
+func (w *Walk) Next(step time.Duration) decimal.Decimal {
+	dt := step.Seconds() / secondsPerYear
+	shock := w.rng.NormFloat64() * w.Volatility * math.Sqrt(dt)
+	w.Price *= math.Exp((w.Drift-w.Volatility*w.Volatility/2)*dt + shock)
+	return decimal.NewFromFloat(w.Price)
+}
+
+// Generate produces one tick every step in [from, to], skipping the gaps.
+// fn receives the ticks in batches of at most batchSize.
+func (w *Walk) Generate(from, to time.Time, step time.Duration, batchSize int, fn func([]*entity.Price) error) error {
+	batch := make([]*entity.Price, 0, batchSize)
+	for t := from; !t.After(to); t = t.Add(step) {
+		price := w.Next(step)
+
+		if w.GapProbability > 0 && w.MaxGap > 0 && w.rng.Float64() < w.GapProbability {
+			gap := time.Duration(w.rng.Int64N(int64(w.MaxGap)))
+			// the market keeps moving while we are not looking
+			for skipped := time.Duration(0); skipped < gap; skipped += step {
+				w.Next(step)
+			}
+			t = t.Add(gap)
+			continue
+		}
+
+		batch = append(batch, &entity.Price{Symbol: w.Symbol, Price: price, Time: t.Unix()})
+		if len(batch) == batchSize {
+			if err := fn(batch); err != nil {
+				return err
+			}
+			batch = make([]*entity.Price, 0, batchSize)
+		}
+	}
+	if len(batch) > 0 {
+		return fn(batch)
+	}
+	return nil
+}
+
+func hash(s string) uint64 {
+	var h uint64 = 14695981039346656037 // FNV-1a
+	for i := 0; i < len(s); i++ {
+		h ^= uint64(s[i])
+		h *= 1099511628211
+	}
+	return h
+}
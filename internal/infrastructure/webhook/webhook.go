@@ -0,0 +1,59 @@
+// Package webhook signs alert deliveries and verifies them on the receiving side.
+//
+// The signature is "sha256=" followed by the hex HMAC-SHA256 of the timestamp
+// header, a dot and the raw body, keyed with the shared secret. Receivers
+// should reject old timestamps so a captured delivery can't be replayed.
+package webhook
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	SignatureHeader = "X-Price-Signature"
+	TimestampHeader = "X-Price-Timestamp"
+	DeliveryHeader  = "X-Price-Delivery" // event id, the same for every attempt
+	AttemptHeader   = "X-Price-Attempt"
+
+	// DefaultTolerance is how far a delivery timestamp may be from the receiver's clock
+	DefaultTolerance = 5 * time.Minute
+)
+
+var (
+	ErrMissingSignature = errors.New("missing webhook signature")
+	ErrInvalidSignature = errors.New("invalid webhook signature")
+	ErrExpired          = errors.New("webhook timestamp outside the tolerance")
+)
+
+// Sign returns the signature header value for body sent at timestamp
+func Sign(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Verify checks the signature and timestamp headers of a delivery
+func Verify(secret, timestamp, signature string, body []byte, now time.Time, tolerance time.Duration) error {
+	if timestamp == "" || signature == "" {
+		return ErrMissingSignature
+	}
+	ts, err := strconv.ParseInt(strings.TrimSpace(timestamp), 10, 64)
+	if err != nil {
+		return ErrInvalidSignature
+	}
+	if d := now.Sub(time.Unix(ts, 0)); d > tolerance || d < -tolerance {
+		return ErrExpired
+	}
+	if !hmac.Equal([]byte(Sign(secret, ts, body)), []byte(strings.TrimSpace(signature))) {
+		return ErrInvalidSignature
+	}
+	return nil
+}
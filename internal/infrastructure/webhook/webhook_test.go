@@ -0,0 +1,65 @@
+package webhook
+
+import (
+	"errors"
+	"strconv"
+	"testing"
+	"time"
+)
+
+func TestSign(t *testing.T) {
+	// computed independently of this package
+	const want = "sha256=11b906526cade680ea8847fc6e85157631eb88280945f93825a4b987f57499c5"
+	if got := Sign("s3cret", 1735689600, []byte(`{"rule_id":1}`)); got != want {
+		t.Errorf("Sign() = %q, want %q", got, want)
+	}
+}
+
+func TestVerify(t *testing.T) {
+	const secret = "s3cret"
+	body := []byte(`{"rule_id":1}`)
+	now := time.Unix(1735689600, 0)
+	at := func(d time.Duration) string { return strconv.FormatInt(now.Add(d).Unix(), 10) }
+	sign := func(ts string) string {
+		n, _ := strconv.ParseInt(ts, 10, 64)
+		return Sign(secret, n, body)
+	}
+
+	tests := []struct {
+		name      string
+		secret    string
+		timestamp string
+		signature string
+		body      string
+		want      error
+	}{
+		{name: "valid", timestamp: at(0), signature: sign(at(0))},
+		{name: "padded headers", timestamp: " " + at(0) + " ", signature: " " + sign(at(0)) + " "},
+		{name: "within tolerance", timestamp: at(-DefaultTolerance), signature: sign(at(-DefaultTolerance))},
+		{name: "clock ahead", timestamp: at(DefaultTolerance), signature: sign(at(DefaultTolerance))},
+		{name: "too old", timestamp: at(-DefaultTolerance - time.Second), signature: sign(at(-DefaultTolerance - time.Second)), want: ErrExpired},
+		{name: "too far ahead", timestamp: at(DefaultTolerance + time.Second), signature: sign(at(DefaultTolerance + time.Second)), want: ErrExpired},
+		{name: "missing timestamp", signature: sign(at(0)), want: ErrMissingSignature},
+		{name: "missing signature", timestamp: at(0), want: ErrMissingSignature},
+		{name: "timestamp not a number", timestamp: "now", signature: sign(at(0)), want: ErrInvalidSignature},
+		{name: "other secret", secret: "other", timestamp: at(0), signature: sign(at(0)), want: ErrInvalidSignature},
+		{name: "tampered body", body: `{"rule_id":2}`, timestamp: at(0), signature: sign(at(0)), want: ErrInvalidSignature},
+		{name: "signed for another timestamp", timestamp: at(0), signature: sign(at(-time.Second)), want: ErrInvalidSignature},
+		{name: "without prefix", timestamp: at(0), signature: sign(at(0))[len("sha256="):], want: ErrInvalidSignature},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s, b := secret, body
+			if tt.secret != "" {
+				s = tt.secret
+			}
+			if tt.body != "" {
+				b = []byte(tt.body)
+			}
+			err := Verify(s, tt.timestamp, tt.signature, b, now, DefaultTolerance)
+			if !errors.Is(err, tt.want) || (tt.want == nil && err != nil) {
+				t.Errorf("Verify() error = %v, want %v", err, tt.want)
+			}
+		})
+	}
+}
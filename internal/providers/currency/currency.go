@@ -9,9 +9,24 @@ import (
 var (
 	ErrCurrencyNotFound        = errors.New("currency not found")
 	ErrCurrencyTooManyRequests = errors.New("too many requests")
+	ErrHistoryNotSupported     = errors.New("provider does not support historical prices")
 )
 
+// Query selects the prices a provider returns
+type Query struct {
+	Quote   string   // quote currency, e.g. "usd"
+	Symbols []string // when empty, the top coins by market cap
+	Page    uint32
+	Limit   uint32
+}
+
 //go:generate mockgen -source=currency.go -destination=../../../mock/providers/currency/currency.go
 type CurrencyProvider interface {
-	Get(ctx context.Context, page, limit uint32) ([]*entity.Price, error)
+	// Get returns prices with symbols built by entity.PairSymbol
+	Get(ctx context.Context, q *Query) ([]*entity.Price, error)
+}
+
+// HistoryProvider is implemented by providers that can return past prices for backfills
+type HistoryProvider interface {
+	GetRange(ctx context.Context, symbol string, from, to int64) ([]*entity.Price, error)
 }
@@ -0,0 +1,138 @@
+package providers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"sync"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/coingecko"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/fake"
+	"github.com/milad-rasouli/price/internal/infrastructure/replay"
+	"github.com/milad-rasouli/price/internal/providers/currency"
+)
+
+// NewCurrencyProvider builds the providers listed in providers.priority,
+// falling back from one to the next in that order. The priority is read from
+// the store on every call, so a reload can reorder, add or drop providers.
+func NewCurrencyProvider(
+	store *config.Store,
+	logger *slog.Logger,
+	cg *coingecko.CoinGecko,
+) (currency.CurrencyProvider, error) {
+	chain := &Chain{
+		store:     store,
+		cg:        cg,
+		providers: make(map[string]currency.CurrencyProvider),
+		logger:    logger.With("layer", "ProviderChain"),
+	}
+	if err := chain.build(store.Current()); err != nil {
+		return nil, err
+	}
+	// a provider that can't be built rejects the reload instead of failing ingestion later
+	store.OnReload(chain.build)
+	return chain, nil
+}
+
+// Chain tries its providers in order and returns the first successful answer
+type Chain struct {
+	store *config.Store
+	cg    *coingecko.CoinGecko
+
+	mu        sync.RWMutex
+	providers map[string]currency.CurrencyProvider // built once by name
+	logger    *slog.Logger
+}
+
+// build creates the providers of cfg that don't exist yet
+func (c *Chain) build(cfg *config.Config) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, name := range cfg.Providers.Priority {
+		if _, ok := c.providers[name]; ok {
+			continue
+		}
+		var (
+			p   currency.CurrencyProvider
+			err error
+		)
+		switch name {
+		case config.ProviderCoinGecko:
+			p = c.cg
+		case config.ProviderFake:
+			p, err = fake.NewFake(cfg, c.logger)
+		case config.ProviderReplay:
+			p, err = replay.NewReplay(cfg.Providers.ReplayFile, c.logger)
+		case config.ProviderRecord:
+			p = replay.NewRecorder(c.cg, cfg.Providers.ReplayFile, c.logger)
+		default:
+			err = fmt.Errorf("unknown currency provider %q", name)
+		}
+		if err != nil {
+			return err
+		}
+		c.providers[name] = p
+	}
+	return nil
+}
+
+type namedProvider struct {
+	name string
+	currency.CurrencyProvider
+}
+
+// ordered returns the providers of the current priority
+func (c *Chain) ordered() []namedProvider {
+	names := c.store.Current().Providers.Priority
+
+	c.mu.RLock()
+	defer c.mu.RUnlock()
+	out := make([]namedProvider, 0, len(names))
+	for _, name := range names {
+		out = append(out, namedProvider{name: name, CurrencyProvider: c.providers[name]})
+	}
+	return out
+}
+
+func (c *Chain) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	var errs []error
+	for _, p := range c.ordered() {
+		prices, err := p.Get(ctx, q)
+		if err == nil {
+			return prices, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		c.logger.Warn("provider failed, trying the next one", "provider", p.name, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+	}
+	return nil, errors.Join(errs...)
+}
+
+func (c *Chain) GetRange(ctx context.Context, symbol string, from, to int64) ([]*entity.Price, error) {
+	var errs []error
+	for _, p := range c.ordered() {
+		hp, ok := p.CurrencyProvider.(currency.HistoryProvider)
+		if !ok {
+			continue
+		}
+		prices, err := hp.GetRange(ctx, symbol, from, to)
+		if err == nil {
+			return prices, nil
+		}
+		if ctx.Err() != nil {
+			return nil, err
+		}
+		c.logger.Warn("provider failed, trying the next one", "provider", p.name, "error", err)
+		errs = append(errs, fmt.Errorf("%s: %w", p.name, err))
+	}
+	if len(errs) == 0 {
+		return nil, currency.ErrHistoryNotSupported
+	}
+	return nil, errors.Join(errs...)
+}
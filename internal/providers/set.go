@@ -3,10 +3,11 @@ package providers
 import (
 	"github.com/google/wire"
 	"github.com/milad-rasouli/price/internal/infrastructure/coingecko"
-	"github.com/milad-rasouli/price/internal/providers/currency"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 )
 
 var ProviderSet = wire.NewSet(
-	wire.Bind(new(currency.CurrencyProvider), new(*coingecko.CoinGecko)),
+	NewCurrencyProvider,
 	coingecko.NewCoinGecko,
+	config.NewStore,
 )
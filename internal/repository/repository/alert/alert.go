@@ -0,0 +1,35 @@
+package alert
+
+import (
+	"context"
+	"errors"
+
+	"github.com/milad-rasouli/price/entity"
+)
+
+var ErrRuleNotFound = errors.New("alert rule not found")
+
+//go:generate mockgen -source=alert.go -destination=../../../../mock/repository/alert/alert.go
+type AlertRepository interface {
+	// CreateRule stores the rule and sets its ID
+	CreateRule(ctx context.Context, rule *entity.AlertRule) error
+	GetRule(ctx context.Context, id int64) (*entity.AlertRule, error)
+	// ListRules returns the rules on any of symbols, or every rule when symbols is nil
+	ListRules(ctx context.Context, symbols []string) ([]*entity.AlertRule, error)
+	DeleteRule(ctx context.Context, id int64) error
+	// SetState records whether the condition holds
+	SetState(ctx context.Context, id int64, active bool) error
+	// Fire marks the rule active and triggered at triggeredAt, and queues the
+	// event in the outbox in the same transaction
+	Fire(ctx context.Context, id int64, triggeredAt int64, event *entity.AlertOutboxEvent) error
+	// ClaimDue returns the oldest outbox event due at now and holds it for
+	// lease seconds, so no other worker delivers it meanwhile; nil when none is due
+	ClaimDue(ctx context.Context, now, lease int64) (*entity.AlertOutboxEvent, error)
+	// Reschedule records a failed attempt and when to try the event again
+	Reschedule(ctx context.Context, eventID string, attempts int, at int64) error
+	// Dequeue removes an event that was delivered or given up on
+	Dequeue(ctx context.Context, eventID string) error
+	LogDelivery(ctx context.Context, d *entity.AlertDelivery) error
+	// ListDeliveries returns the latest delivery attempts of a rule, newest first
+	ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*entity.AlertDelivery, error)
+}
@@ -0,0 +1,208 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+)
+
+const (
+	CreateRuleQuery = `
+		INSERT INTO alert_rules (symbol, kind, threshold, window_seconds, webhook_url, created_at)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING id
+	`
+
+	// ListRulesQuery is formatted with an optional filter
+	ListRulesQuery = `
+		SELECT id, symbol, kind, threshold, window_seconds, webhook_url, active, created_at, last_triggered_at
+		FROM alert_rules
+		%s
+		ORDER BY id
+	`
+
+	DeleteRuleQuery = `DELETE FROM alert_rules WHERE id = $1`
+
+	SetRuleStateQuery = `UPDATE alert_rules SET active = $2 WHERE id = $1`
+
+	FireRuleQuery = `UPDATE alert_rules SET active = true, last_triggered_at = $2 WHERE id = $1`
+
+	// EnqueueEventQuery ignores an event that is already queued, as the event id
+	// is derived from the rule and the tick
+	EnqueueEventQuery = `
+		INSERT INTO alert_outbox (event_id, rule_id, payload, next_attempt_at, created_at)
+		VALUES ($1, $2, $3, $4, $4)
+		ON CONFLICT (event_id) DO NOTHING
+	`
+
+	// ClaimEventQuery skips events another worker is locking, and leases the
+	// claimed one by pushing out its next attempt
+	ClaimEventQuery = `
+		UPDATE alert_outbox o
+		SET next_attempt_at = $1 + $2
+		FROM alert_rules r
+		WHERE r.id = o.rule_id
+		  AND o.event_id = (
+			SELECT event_id FROM alert_outbox
+			WHERE next_attempt_at <= $1
+			ORDER BY next_attempt_at
+			LIMIT 1
+			FOR UPDATE SKIP LOCKED
+		  )
+		RETURNING o.event_id, o.rule_id, r.webhook_url, o.payload, o.attempts, o.next_attempt_at, o.created_at
+	`
+
+	RescheduleEventQuery = `UPDATE alert_outbox SET attempts = $2, next_attempt_at = $3 WHERE event_id = $1`
+
+	DequeueEventQuery = `DELETE FROM alert_outbox WHERE event_id = $1`
+
+	LogDeliveryQuery = `
+		INSERT INTO alert_deliveries (rule_id, event_id, attempt, status_code, error, delivered, payload, time)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING id
+	`
+
+	ListDeliveriesQuery = `
+		SELECT id, rule_id, event_id, attempt, status_code, error, delivered, payload, time
+		FROM alert_deliveries
+		WHERE rule_id = $1
+		ORDER BY id DESC
+		LIMIT $2
+	`
+)
+
+type AlertRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAlertRepository(pool *pgxpool.Pool) *AlertRepository {
+	return &AlertRepository{pool: pool}
+}
+
+func (r *AlertRepository) CreateRule(ctx context.Context, rule *entity.AlertRule) error {
+	return r.pool.QueryRow(ctx, CreateRuleQuery, rule.Symbol, rule.Kind, rule.Threshold, rule.Window,
+		rule.WebhookURL, rule.CreatedAt).Scan(&rule.ID)
+}
+
+func (r *AlertRepository) GetRule(ctx context.Context, id int64) (*entity.AlertRule, error) {
+	rules, err := r.listRules(ctx, "WHERE id = $1", id)
+	if err != nil {
+		return nil, err
+	}
+	if len(rules) == 0 {
+		return nil, alert.ErrRuleNotFound
+	}
+	return rules[0], nil
+}
+
+func (r *AlertRepository) ListRules(ctx context.Context, symbols []string) ([]*entity.AlertRule, error) {
+	if symbols == nil {
+		return r.listRules(ctx, "")
+	}
+	return r.listRules(ctx, "WHERE symbol = ANY($1)", symbols)
+}
+
+func (r *AlertRepository) listRules(ctx context.Context, filter string, args ...any) ([]*entity.AlertRule, error) {
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(ListRulesQuery, filter), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var rules []*entity.AlertRule
+	for rows.Next() {
+		var rule entity.AlertRule
+		if err := rows.Scan(&rule.ID, &rule.Symbol, &rule.Kind, &rule.Threshold, &rule.Window,
+			&rule.WebhookURL, &rule.Active, &rule.CreatedAt, &rule.LastTriggeredAt); err != nil {
+			return nil, err
+		}
+		rules = append(rules, &rule)
+	}
+	return rules, rows.Err()
+}
+
+func (r *AlertRepository) DeleteRule(ctx context.Context, id int64) error {
+	tag, err := r.pool.Exec(ctx, DeleteRuleQuery, id)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return alert.ErrRuleNotFound
+	}
+	return nil
+}
+
+func (r *AlertRepository) SetState(ctx context.Context, id int64, active bool) error {
+	_, err := r.pool.Exec(ctx, SetRuleStateQuery, id, active)
+	return err
+}
+
+func (r *AlertRepository) Fire(ctx context.Context, id int64, triggeredAt int64, event *entity.AlertOutboxEvent) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, FireRuleQuery, id, triggeredAt); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, EnqueueEventQuery, event.EventID, id, event.Payload, event.CreatedAt)
+		return err
+	})
+}
+
+func (r *AlertRepository) ClaimDue(ctx context.Context, now, lease int64) (*entity.AlertOutboxEvent, error) {
+	var e entity.AlertOutboxEvent
+	err := r.pool.QueryRow(ctx, ClaimEventQuery, now, lease).Scan(&e.EventID, &e.RuleID, &e.WebhookURL,
+		&e.Payload, &e.Attempts, &e.NextAttemptAt, &e.CreatedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return &e, nil
+}
+
+func (r *AlertRepository) Reschedule(ctx context.Context, eventID string, attempts int, at int64) error {
+	_, err := r.pool.Exec(ctx, RescheduleEventQuery, eventID, attempts, at)
+	return err
+}
+
+func (r *AlertRepository) Dequeue(ctx context.Context, eventID string) error {
+	_, err := r.pool.Exec(ctx, DequeueEventQuery, eventID)
+	return err
+}
+
+func (r *AlertRepository) LogDelivery(ctx context.Context, d *entity.AlertDelivery) error {
+	return r.pool.QueryRow(ctx, LogDeliveryQuery, d.RuleID, d.EventID, d.Attempt, d.StatusCode, d.Error,
+		d.Delivered, d.Payload, d.Time).Scan(&d.ID)
+}
+
+func (r *AlertRepository) ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*entity.AlertDelivery, error) {
+	rows, err := r.pool.Query(ctx, ListDeliveriesQuery, ruleID, limit)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var deliveries []*entity.AlertDelivery
+	for rows.Next() {
+		var d entity.AlertDelivery
+		if err := rows.Scan(&d.ID, &d.RuleID, &d.EventID, &d.Attempt, &d.StatusCode, &d.Error,
+			&d.Delivered, &d.Payload, &d.Time); err != nil {
+			return nil, err
+		}
+		deliveries = append(deliveries, &d)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if len(deliveries) == 0 {
+		if _, err := r.GetRule(ctx, ruleID); errors.Is(err, alert.ErrRuleNotFound) {
+			return nil, err
+		}
+	}
+	return deliveries, nil
+}
@@ -0,0 +1,152 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+	"github.com/shopspring/decimal"
+)
+
+const t0 = 1735689600
+
+func newRule(t *testing.T, r *AlertRepository) *entity.AlertRule {
+	t.Helper()
+	rule := &entity.AlertRule{Symbol: "btc", Kind: entity.AlertCrossesAbove, Threshold: decimal.NewFromInt(100000),
+		WebhookURL: "https://example.com/hook", CreatedAt: t0}
+	if err := r.CreateRule(context.Background(), rule); err != nil {
+		t.Fatalf("CreateRule() error = %v", err)
+	}
+	return rule
+}
+
+func TestOutbox(t *testing.T) {
+	ctx := context.Background()
+	r := NewAlertRepository(pgtest.Migrate(t).Pool)
+	rule := newRule(t, r)
+
+	fired := []*entity.AlertOutboxEvent{
+		{EventID: "e1", Payload: `{"n":1}`, CreatedAt: t0},
+		{EventID: "e1", Payload: `{"n":1}`, CreatedAt: t0}, // the same tick evaluated again
+		{EventID: "e2", Payload: `{"n":2}`, CreatedAt: t0 + 10},
+	}
+	for _, e := range fired {
+		if err := r.Fire(ctx, rule.ID, e.CreatedAt, e); err != nil {
+			t.Fatalf("Fire(%s) error = %v", e.EventID, err)
+		}
+	}
+	got, err := r.GetRule(ctx, rule.ID)
+	if err != nil {
+		t.Fatalf("GetRule() error = %v", err)
+	}
+	if got.Active == nil || !*got.Active || got.LastTriggeredAt == nil || *got.LastTriggeredAt != t0+10 {
+		t.Errorf("fired rule active %v, last triggered at %v, want active at %d", got.Active, got.LastTriggeredAt, t0+10)
+	}
+
+	const lease = 30
+	steps := []struct {
+		name     string
+		run      func() error // before claiming
+		now      int64
+		want     string // event claimed, empty for none
+		attempts int
+	}{
+		{name: "nothing due yet", now: t0 - 1},
+		{name: "oldest due", now: t0 + 10, want: "e1"},
+		{name: "next while the first is leased", now: t0 + 10, want: "e2"},
+		{name: "both leased", now: t0 + 10},
+		{
+			name: "rescheduled",
+			run:  func() error { return r.Reschedule(ctx, "e1", 1, t0+20) },
+			now:  t0 + 20, want: "e1", attempts: 1,
+		},
+		{name: "lease ran out", now: t0 + 10 + lease, want: "e2"},
+		{
+			name: "dequeued",
+			run: func() error {
+				return errors.Join(r.Dequeue(ctx, "e1"), r.Dequeue(ctx, "e2"))
+			},
+			now: t0 + 1000,
+		},
+	}
+	for _, step := range steps {
+		if step.run != nil {
+			if err := step.run(); err != nil {
+				t.Fatalf("%s: %v", step.name, err)
+			}
+		}
+		e, err := r.ClaimDue(ctx, step.now, lease)
+		if err != nil {
+			t.Fatalf("%s: ClaimDue() error = %v", step.name, err)
+		}
+		if step.want == "" {
+			if e != nil {
+				t.Fatalf("%s: ClaimDue() = %s, want none", step.name, e.EventID)
+			}
+			continue
+		}
+		if e == nil {
+			t.Fatalf("%s: ClaimDue() = none, want %s", step.name, step.want)
+		}
+		if e.EventID != step.want || e.Attempts != step.attempts || e.NextAttemptAt != step.now+lease ||
+			e.WebhookURL != rule.WebhookURL {
+			t.Errorf("%s: ClaimDue() = %+v, want %s after %d attempts leased until %d",
+				step.name, e, step.want, step.attempts, step.now+lease)
+		}
+	}
+}
+
+func TestDeletedRuleDropsItsEvents(t *testing.T) {
+	ctx := context.Background()
+	r := NewAlertRepository(pgtest.Migrate(t).Pool)
+	rule := newRule(t, r)
+
+	if err := r.Fire(ctx, rule.ID, t0, &entity.AlertOutboxEvent{EventID: "e1", Payload: "{}", CreatedAt: t0}); err != nil {
+		t.Fatalf("Fire() error = %v", err)
+	}
+	if err := r.LogDelivery(ctx, &entity.AlertDelivery{RuleID: rule.ID, EventID: "e1", Attempt: 1, Payload: "{}", Time: t0}); err != nil {
+		t.Fatalf("LogDelivery() error = %v", err)
+	}
+	if err := r.DeleteRule(ctx, rule.ID); err != nil {
+		t.Fatalf("DeleteRule() error = %v", err)
+	}
+
+	if e, err := r.ClaimDue(ctx, t0, 30); err != nil || e != nil {
+		t.Errorf("ClaimDue() = %v, %v, want no event", e, err)
+	}
+	if _, err := r.ListDeliveries(ctx, rule.ID, 10); !errors.Is(err, alert.ErrRuleNotFound) {
+		t.Errorf("ListDeliveries() error = %v, want ErrRuleNotFound", err)
+	}
+	if err := r.DeleteRule(ctx, rule.ID); !errors.Is(err, alert.ErrRuleNotFound) {
+		t.Errorf("DeleteRule() again error = %v, want ErrRuleNotFound", err)
+	}
+}
+
+func TestListDeliveries(t *testing.T) {
+	ctx := context.Background()
+	r := NewAlertRepository(pgtest.Migrate(t).Pool)
+	rule := newRule(t, r)
+
+	deliveries, err := r.ListDeliveries(ctx, rule.ID, 10)
+	if err != nil || len(deliveries) != 0 {
+		t.Fatalf("ListDeliveries() before any attempt = %v, %v, want none", deliveries, err)
+	}
+	status := 500
+	for attempt := 1; attempt <= 3; attempt++ {
+		d := &entity.AlertDelivery{RuleID: rule.ID, EventID: "e1", Attempt: attempt, StatusCode: &status,
+			Payload: "{}", Time: t0 + int64(attempt)}
+		if err := r.LogDelivery(ctx, d); err != nil {
+			t.Fatalf("LogDelivery() error = %v", err)
+		}
+	}
+	deliveries, err = r.ListDeliveries(ctx, rule.ID, 2)
+	if err != nil {
+		t.Fatalf("ListDeliveries() error = %v", err)
+	}
+	if len(deliveries) != 2 || deliveries[0].Attempt != 3 || deliveries[1].Attempt != 2 {
+		t.Errorf("ListDeliveries() = %+v, want attempts 3 and 2", deliveries)
+	}
+}
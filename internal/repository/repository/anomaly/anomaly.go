@@ -0,0 +1,27 @@
+package anomaly
+
+import (
+	"context"
+	"errors"
+
+	"github.com/milad-rasouli/price/entity"
+)
+
+var (
+	ErrAnomalyNotFound = errors.New("anomaly not found")
+	ErrAlreadyReviewed = errors.New("anomaly was already reviewed")
+)
+
+//go:generate mockgen -source=anomaly.go -destination=../../../../mock/repository/anomaly/anomaly.go
+type AnomalyRepository interface {
+	// Save records the anomalies and sets their IDs; ticks recorded before are skipped
+	Save(ctx context.Context, anomalies []*entity.Anomaly) error
+	Get(ctx context.Context, id int64) (*entity.Anomaly, error)
+	// List returns the latest anomalies, newest first; empty filters match everything
+	List(ctx context.Context, status, symbol string, limit int) ([]*entity.Anomaly, error)
+	// Quarantined returns the ticks of pending quarantined anomalies of symbols
+	// in [from, to), ordered by symbol and time
+	Quarantined(ctx context.Context, symbols []string, from, to int64) ([]*entity.Price, error)
+	// Review sets the status of a pending anomaly
+	Review(ctx context.Context, id int64, status string, reviewedAt int64) error
+}
@@ -0,0 +1,173 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/repository/repository/anomaly"
+)
+
+const (
+	SaveAnomalyQuery = `
+		INSERT INTO price_anomalies (symbol, price, time, market, action, reference, change_pct, z_score, status, detected_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10)
+		ON CONFLICT (symbol, time) DO NOTHING
+		RETURNING id
+	`
+
+	// ListAnomaliesQuery is formatted with an optional filter and takes the limit as the last argument
+	ListAnomaliesQuery = `
+		SELECT id, symbol, price, time, market, action, reference, change_pct, z_score, status, detected_at, reviewed_at
+		FROM price_anomalies
+		%s
+		ORDER BY id DESC
+		LIMIT $%d
+	`
+
+	QuarantinedTicksQuery = `
+		SELECT symbol, price, time
+		FROM price_anomalies
+		WHERE symbol = ANY($1)
+		  AND time >= $2 AND time < $3
+		  AND action = 'quarantine' AND status = 'pending'
+		ORDER BY symbol, time
+	`
+
+	ReviewAnomalyQuery = `
+		UPDATE price_anomalies
+		SET status = $2, reviewed_at = $3
+		WHERE id = $1 AND status = 'pending'
+	`
+)
+
+type AnomalyRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewAnomalyRepository(pool *pgxpool.Pool) *AnomalyRepository {
+	return &AnomalyRepository{pool: pool}
+}
+
+func (r *AnomalyRepository) Save(ctx context.Context, anomalies []*entity.Anomaly) error {
+	if len(anomalies) == 0 {
+		return nil
+	}
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		for _, a := range anomalies {
+			var market []byte
+			if a.Tick.Market != nil {
+				var err error
+				if market, err = json.Marshal(a.Tick.Market); err != nil {
+					return err
+				}
+			}
+			err := tx.QueryRow(ctx, SaveAnomalyQuery, a.Tick.Symbol, a.Tick.Price, a.Tick.Time, market, a.Action,
+				a.Reference, a.ChangePct, a.ZScore, a.Status, a.DetectedAt).Scan(&a.ID)
+			if err != nil && !errors.Is(err, pgx.ErrNoRows) {
+				return err
+			}
+		}
+		return nil
+	})
+}
+
+func (r *AnomalyRepository) Get(ctx context.Context, id int64) (*entity.Anomaly, error) {
+	anomalies, err := r.list(ctx, []string{"id = $1"}, []any{id}, 1)
+	if err != nil {
+		return nil, err
+	}
+	if len(anomalies) == 0 {
+		return nil, anomaly.ErrAnomalyNotFound
+	}
+	return anomalies[0], nil
+}
+
+func (r *AnomalyRepository) List(ctx context.Context, status, symbol string, limit int) ([]*entity.Anomaly, error) {
+	var (
+		conds []string
+		args  []any
+	)
+	if status != "" {
+		args = append(args, status)
+		conds = append(conds, fmt.Sprintf("status = $%d", len(args)))
+	}
+	if symbol != "" {
+		args = append(args, symbol)
+		conds = append(conds, fmt.Sprintf("symbol = $%d", len(args)))
+	}
+	return r.list(ctx, conds, args, limit)
+}
+
+func (r *AnomalyRepository) list(ctx context.Context, conds []string, args []any, limit int) ([]*entity.Anomaly, error) {
+	filter := ""
+	if len(conds) > 0 {
+		filter = "WHERE " + strings.Join(conds, " AND ")
+	}
+	args = append(args, limit)
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(ListAnomaliesQuery, filter, len(args)), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var anomalies []*entity.Anomaly
+	for rows.Next() {
+		var (
+			a      entity.Anomaly
+			market []byte
+		)
+		if err := rows.Scan(&a.ID, &a.Tick.Symbol, &a.Tick.Price, &a.Tick.Time, &market, &a.Action, &a.Reference,
+			&a.ChangePct, &a.ZScore, &a.Status, &a.DetectedAt, &a.ReviewedAt); err != nil {
+			return nil, err
+		}
+		if market != nil {
+			a.Tick.Market = &entity.Market{}
+			if err := json.Unmarshal(market, a.Tick.Market); err != nil {
+				return nil, err
+			}
+		}
+		anomalies = append(anomalies, &a)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return anomalies, nil
+}
+
+func (r *AnomalyRepository) Quarantined(ctx context.Context, symbols []string, from, to int64) ([]*entity.Price, error) {
+	rows, err := r.pool.Query(ctx, QuarantinedTicksQuery, symbols, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ticks []*entity.Price
+	for rows.Next() {
+		var tick entity.Price
+		if err := rows.Scan(&tick.Symbol, &tick.Price, &tick.Time); err != nil {
+			return nil, err
+		}
+		ticks = append(ticks, &tick)
+	}
+	return ticks, rows.Err()
+}
+
+func (r *AnomalyRepository) Review(ctx context.Context, id int64, status string, reviewedAt int64) error {
+	tag, err := r.pool.Exec(ctx, ReviewAnomalyQuery, id, status, reviewedAt)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() > 0 {
+		return nil
+	}
+	if _, err := r.Get(ctx, id); err != nil {
+		return err
+	}
+	return anomaly.ErrAlreadyReviewed
+}
@@ -0,0 +1,99 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	"github.com/milad-rasouli/price/internal/repository/repository/anomaly"
+	"github.com/shopspring/decimal"
+)
+
+const t0 = 1735689600
+
+func newAnomaly(symbol string, at int64, action string) *entity.Anomaly {
+	return &entity.Anomaly{
+		Tick:       entity.Price{Symbol: symbol, Price: decimal.NewFromInt(200), Time: at},
+		Action:     action,
+		Reference:  decimal.NewFromInt(100),
+		ChangePct:  decimal.NewFromInt(100),
+		Status:     entity.AnomalyPending,
+		DetectedAt: at,
+	}
+}
+
+func TestSaveSkipsRecordedTicks(t *testing.T) {
+	ctx := context.Background()
+	r := NewAnomalyRepository(pgtest.Migrate(t).Pool)
+
+	first := newAnomaly("btc", t0, config.AnomalyFlag)
+	first.Tick.Market = &entity.Market{TotalVolume: decimal.NewNullDecimal(decimal.NewFromInt(5))}
+	if err := r.Save(ctx, []*entity.Anomaly{first}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	again := newAnomaly("btc", t0, config.AnomalyQuarantine)
+	if err := r.Save(ctx, []*entity.Anomaly{again, newAnomaly("eth", t0, config.AnomalyFlag)}); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if first.ID == 0 || again.ID != 0 {
+		t.Errorf("saved IDs %d and %d, want the first set and the repeated tick skipped", first.ID, again.ID)
+	}
+
+	got, err := r.Get(ctx, first.ID)
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if got.Action != config.AnomalyFlag || got.Tick.Market == nil || !got.Tick.Market.TotalVolume.Decimal.Equal(decimal.NewFromInt(5)) {
+		t.Errorf("Get() = %+v, want the first anomaly with its market data", got)
+	}
+	listed, err := r.List(ctx, entity.AnomalyPending, "btc", 10)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(listed) != 1 || listed[0].ID != first.ID {
+		t.Errorf("List() of btc returned %d anomalies, want only the first", len(listed))
+	}
+}
+
+func TestQuarantinedAndReview(t *testing.T) {
+	ctx := context.Background()
+	r := NewAnomalyRepository(pgtest.Migrate(t).Pool)
+
+	held, reviewed := newAnomaly("btc", t0+60, config.AnomalyQuarantine), newAnomaly("btc", t0+120, config.AnomalyQuarantine)
+	anomalies := []*entity.Anomaly{
+		newAnomaly("btc", t0, config.AnomalyFlag),
+		held,
+		reviewed,
+		newAnomaly("btc", t0+180, config.AnomalyQuarantine), // outside the range
+		newAnomaly("eth", t0+60, config.AnomalyQuarantine),  // another symbol
+	}
+	if err := r.Save(ctx, anomalies); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+
+	if err := r.Review(ctx, reviewed.ID, entity.AnomalyRejected, t0+300); err != nil {
+		t.Fatalf("Review() error = %v", err)
+	}
+	if err := r.Review(ctx, reviewed.ID, entity.AnomalyApproved, t0+301); !errors.Is(err, anomaly.ErrAlreadyReviewed) {
+		t.Errorf("Review() of a reviewed anomaly error = %v, want ErrAlreadyReviewed", err)
+	}
+	if err := r.Review(ctx, -1, entity.AnomalyApproved, t0+301); !errors.Is(err, anomaly.ErrAnomalyNotFound) {
+		t.Errorf("Review() of an unknown anomaly error = %v, want ErrAnomalyNotFound", err)
+	}
+
+	ticks, err := r.Quarantined(ctx, []string{"btc"}, t0, t0+180)
+	if err != nil {
+		t.Fatalf("Quarantined() error = %v", err)
+	}
+	var times []int64
+	for _, tick := range ticks {
+		times = append(times, tick.Time)
+	}
+	if !slices.Equal(times, []int64{t0 + 60}) {
+		t.Errorf("Quarantined() ticks at %v, want only %d", times, t0+60)
+	}
+}
@@ -0,0 +1,24 @@
+package basket
+
+import (
+	"context"
+	"errors"
+
+	"github.com/milad-rasouli/price/entity"
+)
+
+var (
+	ErrBasketNotFound = errors.New("basket not found")
+	ErrNameTaken      = errors.New("name is taken by another symbol")
+)
+
+//go:generate mockgen -source=basket.go -destination=../../../../mock/repository/basket/basket.go
+type BasketRepository interface {
+	// Save creates the basket or replaces its components, keeping CreatedAt.
+	// A new basket reserves its name in the symbol catalog and fails with
+	// ErrNameTaken when a coin or a deleted basket already holds it.
+	Save(ctx context.Context, b *entity.Basket) error
+	Get(ctx context.Context, name string) (*entity.Basket, error)
+	List(ctx context.Context) ([]*entity.Basket, error)
+	Delete(ctx context.Context, name string) error
+}
@@ -0,0 +1,138 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/repository/repository/basket"
+)
+
+const (
+	// ClaimNameQuery returns no row when the name is held by a coin, or by a
+	// deleted basket whose stored series the new basket would continue
+	ClaimNameQuery = `
+		INSERT INTO symbols (symbol, basket) VALUES ($1, true)
+		ON CONFLICT (symbol) DO UPDATE SET basket = true
+		WHERE symbols.basket AND EXISTS (SELECT 1 FROM baskets WHERE name = $1)
+		RETURNING symbol
+	`
+
+	UpsertBasketQuery = `
+		INSERT INTO baskets (name, base_value, created_at, updated_at)
+		VALUES ($1, $2, $3, $3)
+		ON CONFLICT (name) DO UPDATE
+		SET base_value = EXCLUDED.base_value, updated_at = EXCLUDED.updated_at
+	`
+
+	DeleteComponentsQuery = `DELETE FROM basket_components WHERE basket = $1`
+
+	InsertComponentsQuery = `
+		INSERT INTO basket_components (basket, symbol, weight, units)
+		SELECT $1, c.symbol, c.weight, c.units
+		FROM unnest($2::TEXT[], $3::NUMERIC[], $4::NUMERIC[]) AS c(symbol, weight, units)
+	`
+
+	// ListBasketsQuery is formatted with an optional filter
+	ListBasketsQuery = `
+		SELECT b.name, b.base_value, b.created_at, b.updated_at, c.symbol, c.weight, c.units
+		FROM baskets b
+		JOIN basket_components c ON c.basket = b.name
+		%s
+		ORDER BY b.name, c.symbol
+	`
+
+	DeleteBasketQuery = `DELETE FROM baskets WHERE name = $1`
+)
+
+type BasketRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewBasketRepository(pool *pgxpool.Pool) *BasketRepository {
+	return &BasketRepository{pool: pool}
+}
+
+func (r *BasketRepository) Save(ctx context.Context, b *entity.Basket) error {
+	symbols := make([]string, len(b.Components))
+	weights := make([]string, len(b.Components))
+	units := make([]string, len(b.Components))
+	for i, c := range b.Components {
+		symbols[i], weights[i], units[i] = c.Symbol, c.Weight.String(), c.Units.String()
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		var claimed string
+		if err := tx.QueryRow(ctx, ClaimNameQuery, b.Name).Scan(&claimed); err != nil {
+			if errors.Is(err, pgx.ErrNoRows) {
+				return basket.ErrNameTaken
+			}
+			return err
+		}
+		if _, err := tx.Exec(ctx, UpsertBasketQuery, b.Name, b.BaseValue, b.UpdatedAt); err != nil {
+			return err
+		}
+		if _, err := tx.Exec(ctx, DeleteComponentsQuery, b.Name); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, InsertComponentsQuery, b.Name, symbols, weights, units)
+		return err
+	})
+}
+
+func (r *BasketRepository) Get(ctx context.Context, name string) (*entity.Basket, error) {
+	baskets, err := r.list(ctx, "WHERE b.name = $1", name)
+	if err != nil {
+		return nil, err
+	}
+	if len(baskets) == 0 {
+		return nil, basket.ErrBasketNotFound
+	}
+	return baskets[0], nil
+}
+
+func (r *BasketRepository) List(ctx context.Context) ([]*entity.Basket, error) {
+	return r.list(ctx, "")
+}
+
+func (r *BasketRepository) list(ctx context.Context, filter string, args ...any) ([]*entity.Basket, error) {
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(ListBasketsQuery, filter), args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var baskets []*entity.Basket
+	for rows.Next() {
+		var (
+			b entity.Basket
+			c entity.BasketComponent
+		)
+		if err := rows.Scan(&b.Name, &b.BaseValue, &b.CreatedAt, &b.UpdatedAt, &c.Symbol, &c.Weight, &c.Units); err != nil {
+			return nil, err
+		}
+		if n := len(baskets); n == 0 || baskets[n-1].Name != b.Name {
+			baskets = append(baskets, &b)
+		}
+		last := baskets[len(baskets)-1]
+		last.Components = append(last.Components, c)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return baskets, nil
+}
+
+func (r *BasketRepository) Delete(ctx context.Context, name string) error {
+	tag, err := r.pool.Exec(ctx, DeleteBasketQuery, name)
+	if err != nil {
+		return err
+	}
+	if tag.RowsAffected() == 0 {
+		return basket.ErrBasketNotFound
+	}
+	return nil
+}
@@ -0,0 +1,130 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	"github.com/milad-rasouli/price/internal/repository/repository/basket"
+	"github.com/shopspring/decimal"
+)
+
+func newBasket(name string, at int64, symbols ...string) *entity.Basket {
+	b := &entity.Basket{Name: name, BaseValue: decimal.NewFromInt(100), UpdatedAt: at}
+	for _, symbol := range symbols {
+		weight := decimal.NewFromInt(1).Div(decimal.NewFromInt(int64(len(symbols))))
+		b.Components = append(b.Components, entity.BasketComponent{Symbol: symbol, Weight: weight, Units: decimal.NewFromInt(1)})
+	}
+	return b
+}
+
+func TestSaveClaimsName(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.Migrate(t).Pool
+	r := NewBasketRepository(pool)
+	if _, err := pool.Exec(ctx, `INSERT INTO symbols (symbol) VALUES ('btc')`); err != nil {
+		t.Fatalf("adding a coin to the catalog: %v", err)
+	}
+
+	steps := []struct {
+		name string
+		run  func() error
+		err  error
+	}{
+		{"new basket", func() error { return r.Save(ctx, newBasket("defi", 1, "uni", "aave")) }, nil},
+		{"rebalanced basket", func() error { return r.Save(ctx, newBasket("defi", 2, "uni")) }, nil},
+		{"name of a coin", func() error { return r.Save(ctx, newBasket("btc", 3, "eth")) }, basket.ErrNameTaken},
+		{"deleted basket", func() error { return r.Delete(ctx, "defi") }, nil},
+		{"deleted again", func() error { return r.Delete(ctx, "defi") }, basket.ErrBasketNotFound},
+		{"name of a deleted basket", func() error { return r.Save(ctx, newBasket("defi", 4, "uni")) }, basket.ErrNameTaken},
+		{"another basket", func() error { return r.Save(ctx, newBasket("l1", 5, "eth", "sol")) }, nil},
+	}
+	for _, step := range steps {
+		if err := step.run(); !errors.Is(err, step.err) {
+			t.Fatalf("%s: error = %v, want %v", step.name, err, step.err)
+		}
+	}
+
+	baskets, err := r.List(ctx)
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	if len(baskets) != 1 || baskets[0].Name != "l1" || len(baskets[0].Components) != 2 {
+		t.Fatalf("List() = %+v, want only l1 with its 2 components", baskets)
+	}
+	if _, err := r.Get(ctx, "defi"); !errors.Is(err, basket.ErrBasketNotFound) {
+		t.Errorf("Get() of a deleted basket error = %v, want ErrBasketNotFound", err)
+	}
+
+	var names []string
+	rows, err := pool.Query(ctx, `SELECT symbol FROM symbols WHERE basket ORDER BY symbol`)
+	if err != nil {
+		t.Fatalf("reading the catalog: %v", err)
+	}
+	for rows.Next() {
+		var name string
+		if err := rows.Scan(&name); err != nil {
+			t.Fatalf("reading the catalog: %v", err)
+		}
+		names = append(names, name)
+	}
+	if !slices.Equal(names, []string{"defi", "l1"}) {
+		t.Errorf("reserved basket names = %v, want [defi l1]", names)
+	}
+}
+
+func TestSaveKeepsCreatedAt(t *testing.T) {
+	ctx := context.Background()
+	r := NewBasketRepository(pgtest.Migrate(t).Pool)
+
+	if err := r.Save(ctx, newBasket("defi", 1, "uni", "aave")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	if err := r.Save(ctx, newBasket("defi", 2, "uni")); err != nil {
+		t.Fatalf("Save() error = %v", err)
+	}
+	b, err := r.Get(ctx, "defi")
+	if err != nil {
+		t.Fatalf("Get() error = %v", err)
+	}
+	if b.CreatedAt != 1 || b.UpdatedAt != 2 || len(b.Components) != 1 || b.Components[0].Symbol != "uni" {
+		t.Errorf("Get() = %+v, want created at 1, updated at 2 with only uni", b)
+	}
+}
+
+// TestMigrationReservesBasketNames saves a basket before migration 011, which
+// has to reserve its name along with the names of later ones
+func TestMigrationReservesBasketNames(t *testing.T) {
+	ctx := context.Background()
+	cfg := pgtest.Config(t)
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+	if err := mg.Goto(10); err != nil {
+		t.Fatalf("Goto(10) error = %v", err)
+	}
+	pool := pgtest.Connect(t, cfg).Pool
+	if _, err := pool.Exec(ctx, `INSERT INTO baskets VALUES ('defi', 100, 1, 1)`); err != nil {
+		t.Fatalf("adding a basket: %v", err)
+	}
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	r := NewBasketRepository(pool)
+	if err := r.Save(ctx, newBasket("defi", 2, "uni")); err != nil {
+		t.Fatalf("Save() of the migrated basket error = %v", err)
+	}
+	if err := r.Delete(ctx, "defi"); err != nil {
+		t.Fatalf("Delete() error = %v", err)
+	}
+	if err := r.Save(ctx, newBasket("defi", 3, "uni")); !errors.Is(err, basket.ErrNameTaken) {
+		t.Errorf("Save() under the name of the deleted basket error = %v, want ErrNameTaken", err)
+	}
+}
@@ -0,0 +1,19 @@
+package catalog
+
+import (
+	"context"
+
+	"github.com/milad-rasouli/price/entity"
+)
+
+//go:generate mockgen -source=catalog.go -destination=../../../../mock/repository/catalog/catalog.go
+type CatalogRepository interface {
+	// Track records the assets of an ingestion tick's prices and marks their
+	// symbols as ingesting. Every other symbol stops ingesting unless it is in
+	// tracked or a live basket.
+	Track(ctx context.Context, prices []*entity.Price, tracked []string, at int64) error
+	// List returns every symbol with its coverage in coin_prices, ordered by symbol
+	List(ctx context.Context) ([]*entity.CatalogEntry, error)
+	// BasketNames returns the symbols reserved by baskets, including deleted ones
+	BasketNames(ctx context.Context) ([]string, error)
+}
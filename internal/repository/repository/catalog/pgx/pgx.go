@@ -0,0 +1,130 @@
+package pgx
+
+import (
+	"context"
+	"encoding/json"
+	"maps"
+	"slices"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/milad-rasouli/price/entity"
+)
+
+const (
+	// TrackSymbolsQuery keeps a known name when the provider sends none and
+	// merges the provider ids, so every provider that served a symbol is listed
+	TrackSymbolsQuery = `
+		INSERT INTO symbols (symbol, name, provider_ids, ingesting, last_ingested_at)
+		SELECT symbol, name, provider_ids::JSONB, true, $4
+		FROM unnest($1::VARCHAR[], $2::TEXT[], $3::TEXT[]) AS t(symbol, name, provider_ids)
+		ON CONFLICT (symbol) DO UPDATE
+		SET name = COALESCE(NULLIF(EXCLUDED.name, ''), symbols.name),
+			provider_ids = symbols.provider_ids || EXCLUDED.provider_ids,
+			ingesting = true,
+			last_ingested_at = EXCLUDED.last_ingested_at
+	`
+
+	// UntrackSymbolsQuery keeps the live baskets, which are valued rather than fetched
+	UntrackSymbolsQuery = `
+		UPDATE symbols
+		SET ingesting = false
+		WHERE ingesting AND symbol <> ALL($1::VARCHAR[])
+		  AND NOT EXISTS (SELECT 1 FROM baskets WHERE name = symbols.symbol)
+	`
+
+	// ListSymbolsQuery reads the first and last tick of each symbol through the primary key
+	ListSymbolsQuery = `
+		SELECT s.symbol, s.name, s.provider_ids, f.time, l.time, s.row_count, s.basket, s.ingesting, s.last_ingested_at
+		FROM symbols s
+		LEFT JOIN LATERAL (
+			SELECT time FROM coin_prices WHERE symbol = s.symbol ORDER BY time ASC LIMIT 1
+		) f ON true
+		LEFT JOIN LATERAL (
+			SELECT time FROM coin_prices WHERE symbol = s.symbol ORDER BY time DESC LIMIT 1
+		) l ON true
+		ORDER BY s.symbol
+	`
+
+	BasketNamesQuery = `SELECT symbol FROM symbols WHERE basket ORDER BY symbol`
+)
+
+type CatalogRepository struct {
+	pool *pgxpool.Pool
+}
+
+func NewCatalogRepository(pool *pgxpool.Pool) *CatalogRepository {
+	return &CatalogRepository{pool: pool}
+}
+
+func (r *CatalogRepository) Track(ctx context.Context, prices []*entity.Price, tracked []string, at int64) error {
+	assets := make(map[string]*entity.Asset, len(prices))
+	for _, p := range prices {
+		if a, ok := assets[p.Symbol]; !ok || a == nil {
+			assets[p.Symbol] = p.Asset
+		}
+	}
+	// sorted like the row counts of the price repository, so the two never lock rows in opposite orders
+	symbols := slices.Sorted(maps.Keys(assets))
+	names := make([]string, len(symbols))
+	ids := make([]string, len(symbols))
+	for i, symbol := range symbols {
+		ids[i] = "{}"
+		a := assets[symbol]
+		if a == nil {
+			continue
+		}
+		names[i] = a.Name
+		if len(a.ProviderIDs) > 0 {
+			encoded, err := json.Marshal(a.ProviderIDs)
+			if err != nil {
+				return err
+			}
+			ids[i] = string(encoded)
+		}
+	}
+
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		if _, err := tx.Exec(ctx, TrackSymbolsQuery, symbols, names, ids, at); err != nil {
+			return err
+		}
+		_, err := tx.Exec(ctx, UntrackSymbolsQuery, slices.Concat(symbols, tracked))
+		return err
+	})
+}
+
+func (r *CatalogRepository) List(ctx context.Context) ([]*entity.CatalogEntry, error) {
+	rows, err := r.pool.Query(ctx, ListSymbolsQuery)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var entries []*entity.CatalogEntry
+	for rows.Next() {
+		var (
+			e   entity.CatalogEntry
+			ids []byte
+		)
+		if err := rows.Scan(&e.Symbol, &e.Name, &ids, &e.FirstTime, &e.LastTime, &e.Rows, &e.Basket,
+			&e.Ingesting, &e.LastIngestedAt); err != nil {
+			return nil, err
+		}
+		if err := json.Unmarshal(ids, &e.ProviderIDs); err != nil {
+			return nil, err
+		}
+		entries = append(entries, &e)
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return entries, nil
+}
+
+func (r *CatalogRepository) BasketNames(ctx context.Context) ([]string, error) {
+	rows, err := r.pool.Query(ctx, BasketNamesQuery)
+	if err != nil {
+		return nil, err
+	}
+	return pgx.CollectRows(rows, pgx.RowTo[string])
+}
@@ -0,0 +1,136 @@
+package pgx
+
+import (
+	"context"
+	"maps"
+	"testing"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	pricepgx "github.com/milad-rasouli/price/internal/repository/repository/price/pgx"
+	"github.com/shopspring/decimal"
+)
+
+const t0 = 1735689600
+
+func tick(symbol string, at int64, asset *entity.Asset) *entity.Price {
+	return &entity.Price{Symbol: symbol, Price: decimal.NewFromInt(1), Time: at, Asset: asset}
+}
+
+func entries(t *testing.T, r *CatalogRepository) map[string]*entity.CatalogEntry {
+	t.Helper()
+	list, err := r.List(context.Background())
+	if err != nil {
+		t.Fatalf("List() error = %v", err)
+	}
+	bySymbol := make(map[string]*entity.CatalogEntry, len(list))
+	for _, e := range list {
+		bySymbol[e.Symbol] = e
+	}
+	return bySymbol
+}
+
+func TestTrack(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.Migrate(t).Pool
+	r := NewCatalogRepository(pool)
+	if _, err := pool.Exec(ctx, `INSERT INTO baskets VALUES ('defi', 100, $1, $1)`, t0); err != nil {
+		t.Fatalf("adding a basket: %v", err)
+	}
+
+	bitcoin := &entity.Asset{Name: "Bitcoin", ProviderIDs: map[string]string{"coingecko": "bitcoin"}}
+	first := []*entity.Price{tick("btc", t0, bitcoin), tick("eth", t0, nil), tick("btc-eur", t0, nil), tick("defi", t0, nil)}
+	if err := r.Track(ctx, first, nil, t0); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+	// the provider skipped btc-eur, which is still configured, and eth was removed from the config
+	unnamed := &entity.Asset{ProviderIDs: map[string]string{"binance": "BTCUSDT"}}
+	if err := r.Track(ctx, []*entity.Price{tick("btc", t0+60, unnamed)}, []string{"btc-eur"}, t0+60); err != nil {
+		t.Fatalf("Track() error = %v", err)
+	}
+
+	tests := []struct {
+		symbol         string
+		name           string
+		providerIDs    map[string]string
+		ingesting      bool
+		lastIngestedAt int64
+	}{
+		{"btc", "Bitcoin", map[string]string{"coingecko": "bitcoin", "binance": "BTCUSDT"}, true, t0 + 60},
+		{"btc-eur", "", map[string]string{}, true, t0},
+		{"eth", "", map[string]string{}, false, t0},
+		{"defi", "", map[string]string{}, true, t0}, // live baskets are valued, not fetched
+	}
+	got := entries(t, r)
+	for _, tt := range tests {
+		e, ok := got[tt.symbol]
+		if !ok {
+			t.Errorf("%s is not in the catalog", tt.symbol)
+			continue
+		}
+		if e.Name != tt.name || !maps.Equal(e.ProviderIDs, tt.providerIDs) || e.Ingesting != tt.ingesting ||
+			e.LastIngestedAt == nil || *e.LastIngestedAt != tt.lastIngestedAt {
+			t.Errorf("%s = %+v, want name %q, provider ids %v, ingesting %v at %d",
+				tt.symbol, e, tt.name, tt.providerIDs, tt.ingesting, tt.lastIngestedAt)
+		}
+	}
+}
+
+// TestMigrationCountsStoredRows stores prices before the catalog exists, which
+// migrations 009 and 010 have to count and date
+func TestMigrationCountsStoredRows(t *testing.T) {
+	ctx := context.Background()
+	cfg := pgtest.Config(t)
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+	if err := mg.Goto(8); err != nil {
+		t.Fatalf("Goto(8) error = %v", err)
+	}
+	pool := pgtest.Connect(t, cfg).Pool
+	_, err = pool.Exec(ctx, `INSERT INTO coin_prices (symbol, price, time) VALUES ('btc', 1, $1), ('btc', 2, $2), ('eth', 1, $1)`,
+		t0, t0+60)
+	if err != nil {
+		t.Fatalf("adding prices: %v", err)
+	}
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+
+	prices := pricepgx.NewPriceRepository(pool)
+	if err := prices.BatchInsert(ctx, []*entity.Price{tick("btc", t0+120, nil)}); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+	got := entries(t, NewCatalogRepository(pool))
+	tests := []struct {
+		symbol      string
+		rows        int64
+		first, last int64
+	}{
+		{"btc", 3, t0, t0 + 120},
+		{"eth", 1, t0, t0},
+	}
+	for _, tt := range tests {
+		e, ok := got[tt.symbol]
+		if !ok {
+			t.Errorf("%s is not in the catalog", tt.symbol)
+			continue
+		}
+		if e.Rows != tt.rows || e.FirstTime == nil || *e.FirstTime != tt.first || e.LastTime == nil || *e.LastTime != tt.last {
+			t.Errorf("%s = %d rows from %v to %v, want %d from %d to %d",
+				tt.symbol, e.Rows, e.FirstTime, e.LastTime, tt.rows, tt.first, tt.last)
+		}
+	}
+
+	// appending past the migrated last time is not a revision
+	v, err := prices.GetVersion(ctx, "btc", t0+120)
+	if err != nil {
+		t.Fatalf("GetVersion() error = %v", err)
+	}
+	if v.LastTime != t0+120 || v.Revision != 0 {
+		t.Errorf("GetVersion() = last time %d, revision %d, want %d, 0", v.LastTime, v.Revision, t0+120)
+	}
+}
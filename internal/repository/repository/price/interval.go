@@ -0,0 +1,48 @@
+package price
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var ErrInvalidInterval = errors.New("invalid interval")
+
+// DefaultInterval is the bucket used when a request doesn't ask for one
+const DefaultInterval = "1h"
+
+// ParseInterval parses bucket sizes such as "30s", "5m", "1h", "1d" or "1w".
+// Buckets must be a whole number of seconds.
+func ParseInterval(s string) (time.Duration, error) {
+	s = strings.TrimSpace(s)
+	var (
+		d   time.Duration
+		err error
+	)
+	switch {
+	case strings.HasSuffix(s, "d"), strings.HasSuffix(s, "w"):
+		unit := 24 * time.Hour
+		if strings.HasSuffix(s, "w") {
+			unit *= 7
+		}
+		var n int64
+		n, err = strconv.ParseInt(s[:len(s)-1], 10, 64)
+		d = time.Duration(n) * unit
+	default:
+		d, err = time.ParseDuration(s)
+	}
+	if err != nil || d < time.Second || d%time.Second != 0 {
+		return 0, fmt.Errorf("%w: %q", ErrInvalidInterval, s)
+	}
+	return d, nil
+}
+
+// Span is the range [start, end) history covers for [from, to]: every bucket
+// of the interval that overlaps it, whole. A bucket then reads the same in any
+// range that includes it, whether it's computed from raw rows or an aggregate.
+func Span(interval time.Duration, from, to int64) (start, end int64) {
+	bucket := int64(interval / time.Second)
+	return from - from%bucket, to - to%bucket + bucket
+}
@@ -3,7 +3,10 @@ package pgx
 import (
 	"context"
 	"errors"
+	"fmt"
 	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"maps"
+	"slices"
 	"time"
 
 	"github.com/jackc/pgx/v5"
@@ -14,19 +17,75 @@ import (
 )
 
 const (
-	DefaultInterval = "1h"
+	DefaultInterval = price.DefaultInterval
 	GetHistoryQuery = `
-		SELECT EXTRACT(EPOCH FROM time_bucket($1, to_timestamp(time)))::BIGINT AS bucket,
+		SELECT time_bucket($1::BIGINT, time) AS bucket,
 			   symbol,
 			   AVG(price) AS avg_price,
-			   LAST(price, to_timestamp(time)) AS last_price
+			   LAST(price, time) AS last_price
 		FROM coin_prices
 		WHERE symbol = $2
-		  AND time BETWEEN $3 AND $4
+		  AND time >= $3 AND time < $4
 		GROUP BY bucket, symbol
 		ORDER BY bucket ASC
 	`
 
+	// GetAggregateHistoryQuery is formatted with the continuous aggregate to read
+	// from. Buckets archived by migration 004 take the place of the aggregate's,
+	// which lack the raw rows retention had dropped.
+	GetAggregateHistoryQuery = `
+		WITH archived AS (
+			SELECT bucket, symbol, sum_price, samples, last_price
+			FROM coin_prices_archive
+			WHERE aggregate = '%[1]s' AND symbol = $2
+			  AND bucket >= $3 AND bucket < $4
+		)
+		SELECT time_bucket($1::BIGINT, bucket) AS b,
+			   symbol,
+			   SUM(sum_price) / SUM(samples) AS avg_price,
+			   LAST(last_price, bucket) AS last_price
+		FROM (
+			SELECT * FROM archived
+			UNION ALL
+			SELECT bucket, symbol, sum_price, samples, last_price
+			FROM %[1]s
+			WHERE symbol = $2
+			  AND bucket >= $3 AND bucket < $4
+			  AND bucket > (SELECT COALESCE(MAX(bucket), -1) FROM coin_prices_archive
+							WHERE aggregate = '%[1]s' AND symbol = $2)
+		) t
+		GROUP BY b, symbol
+		ORDER BY b ASC
+	`
+
+	// InsertIgnoreQuery skips rows that are already stored, for backfills over existing data
+	InsertIgnoreQuery = `
+		INSERT INTO coin_prices (symbol, price, time)
+		SELECT symbol, price::NUMERIC, time
+		FROM unnest($1::VARCHAR[], $2::TEXT[], $3::BIGINT[]) AS t(symbol, price, time)
+		ON CONFLICT (symbol, time) DO NOTHING
+		RETURNING symbol, time
+	`
+
+	AddSymbolsQuery = `
+		INSERT INTO symbols (symbol)
+		SELECT unnest($1::VARCHAR[])
+		ON CONFLICT (symbol) DO NOTHING
+	`
+
+	// CountRowsQuery adds written rows to the symbol catalog. A write at or
+	// below the newest stored time, or a removal, changes data that may have
+	// been served already and bumps the revision.
+	CountRowsQuery = `
+		UPDATE symbols s
+		SET row_count = s.row_count + t.n,
+			last_time = GREATEST(s.last_time, t.max_time),
+			revision = s.revision + CASE WHEN t.n < 0 OR t.min_time <= s.last_time THEN 1 ELSE 0 END,
+			revised_at = CASE WHEN t.n < 0 OR t.min_time <= s.last_time THEN $5 ELSE s.revised_at END
+		FROM unnest($1::VARCHAR[], $2::BIGINT[], $3::BIGINT[], $4::BIGINT[]) AS t(symbol, n, min_time, max_time)
+		WHERE s.symbol = t.symbol
+	`
+
 	GetLatestQuery = `
 		SELECT symbol, price, time
 		FROM coin_prices
@@ -35,14 +94,162 @@ const (
 		LIMIT 1
 	`
 
-	GetBeforeTimeQuery = `
-		SELECT price 
-		FROM coin_prices 
-		WHERE symbol = $1 AND time <= $2 
+	// GetPricesAtQuery returns the price in effect at each of the given times, NULL before the first tick
+	GetPricesAtQuery = `
+		SELECT at.time, p.price, p.time
+		FROM unnest($2::BIGINT[]) WITH ORDINALITY AS at(time, n)
+		LEFT JOIN LATERAL (
+			SELECT price, time
+			FROM coin_prices
+			WHERE symbol = $1 AND time <= at.time
+			ORDER BY time DESC LIMIT 1
+		) p ON true
+		ORDER BY at.n
+	`
+
+	// GetTicksQuery is formatted with the sort direction; a NULL limit returns every row
+	GetTicksQuery = `
+		SELECT symbol, price, time
+		FROM coin_prices
+		WHERE symbol = $1
+		  AND time BETWEEN $2 AND $3
+		ORDER BY time %s
+		LIMIT $4
+	`
+
+	GetRecentTicksQuery = `
+		SELECT symbol, price, time
+		FROM coin_prices
+		WHERE symbol = ANY($1)
+		  AND time >= $2 AND time < $3
+		ORDER BY symbol, time
+	`
+
+	// DeleteTickQuery only removes the row while it still holds the given price
+	DeleteTickQuery = `DELETE FROM coin_prices WHERE symbol = $1 AND time = $2 AND price = $3`
+
+	DeleteMarketQuery = `DELETE FROM coin_markets WHERE symbol = $1 AND time = $2`
+
+	// InsertIgnoreMarketsQuery takes the decimals as text; NULL elements are missing values
+	InsertIgnoreMarketsQuery = `
+		INSERT INTO coin_markets (symbol, time, market_cap, total_volume, circulating_supply, high_24h, low_24h)
+		SELECT symbol, time, market_cap::NUMERIC, total_volume::NUMERIC, circulating_supply::NUMERIC,
+			   high_24h::NUMERIC, low_24h::NUMERIC
+		FROM unnest($1::VARCHAR[], $2::BIGINT[], $3::TEXT[], $4::TEXT[], $5::TEXT[], $6::TEXT[], $7::TEXT[])
+			AS t(symbol, time, market_cap, total_volume, circulating_supply, high_24h, low_24h)
+		ON CONFLICT (symbol, time) DO NOTHING
+	`
+
+	GetLatestMarketQuery = `
+		SELECT time, market_cap, total_volume, circulating_supply, high_24h, low_24h
+		FROM coin_markets
+		WHERE symbol = $1 AND time <= $2
 		ORDER BY time DESC LIMIT 1
 	`
+
+	GetMarketHistoryQuery = `
+		SELECT time_bucket($1::BIGINT, time) AS bucket,
+			   symbol,
+			   MAX(time),
+			   LAST(market_cap, time),
+			   LAST(total_volume, time),
+			   LAST(circulating_supply, time),
+			   LAST(high_24h, time),
+			   LAST(low_24h, time)
+		FROM coin_markets
+		WHERE symbol = $2
+		  AND time >= $3 AND time < $4
+		GROUP BY bucket, symbol
+		ORDER BY bucket ASC
+	`
+
+	// GetTWAPQuery holds every tick's price until the next tick. The tick before
+	// the window is included because its price is in effect when the window opens.
+	GetTWAPQuery = `
+		WITH ticks AS (
+			(SELECT time, price FROM coin_prices
+			 WHERE symbol = $1 AND time < $2::BIGINT
+			 ORDER BY time DESC LIMIT 1)
+			UNION ALL
+			(SELECT time, price FROM coin_prices
+			 WHERE symbol = $1 AND time BETWEEN $2::BIGINT AND $3::BIGINT)
+		), held AS (
+			SELECT price,
+				   LEAD(time, 1, $3::BIGINT) OVER (ORDER BY time) - GREATEST(time, $2::BIGINT) AS seconds
+			FROM ticks
+		)
+		SELECT SUM(price * seconds) / NULLIF(SUM(seconds), 0), COUNT(*), COALESCE(SUM(seconds), 0)::BIGINT
+		FROM held
+	`
+
+	// GetVWAPQuery weights the held prices of GetTWAPQuery by the rolling 24h
+	// volume in effect, a proxy for the trading rate. It is not a true VWAP:
+	// providers don't report what traded per tick, and differences of a rolling
+	// total mix in what left the window, so they can't recover it either.
+	GetVWAPQuery = `
+		WITH ticks AS (
+			(SELECT p.time, p.price, m.total_volume AS volume
+			 FROM coin_prices p
+			 JOIN coin_markets m ON m.symbol = p.symbol AND m.time = p.time AND m.total_volume IS NOT NULL
+			 WHERE p.symbol = $1 AND p.time < $2::BIGINT
+			 ORDER BY p.time DESC LIMIT 1)
+			UNION ALL
+			(SELECT p.time, p.price, m.total_volume
+			 FROM coin_prices p
+			 JOIN coin_markets m ON m.symbol = p.symbol AND m.time = p.time AND m.total_volume IS NOT NULL
+			 WHERE p.symbol = $1 AND p.time BETWEEN $2::BIGINT AND $3::BIGINT)
+		), held AS (
+			SELECT price, volume,
+				   LEAD(time, 1, $3::BIGINT) OVER (ORDER BY time) - GREATEST(time, $2::BIGINT) AS seconds
+			FROM ticks
+		)
+		SELECT SUM(price * volume * seconds) / NULLIF(SUM(volume * seconds), 0), COUNT(*), COALESCE(SUM(seconds), 0)::BIGINT
+		FROM held
+	`
+
+	// GetVersionQuery falls back to the finest aggregate, which covers every raw
+	// row, and its archive for ranges whose raw rows retention dropped; the time
+	// is NULL when nothing is stored up to $2
+	GetVersionQuery = `
+		SELECT COALESCE(
+				   (SELECT time FROM coin_prices
+					WHERE symbol = $1 AND time <= $2
+					ORDER BY time DESC LIMIT 1),
+				   (SELECT bucket FROM coin_prices_1m
+					WHERE symbol = $1 AND bucket <= $2
+					ORDER BY bucket DESC LIMIT 1),
+				   (SELECT bucket FROM coin_prices_archive
+					WHERE aggregate = 'coin_prices_1m' AND symbol = $1 AND bucket <= $2
+					ORDER BY bucket DESC LIMIT 1)
+			   ),
+			   revision, revised_at
+		FROM symbols
+		WHERE symbol = $1
+	`
 )
 
+// priceAggregates lists the continuous aggregates from the coarsest bucket down
+var priceAggregates = []struct {
+	view   string
+	bucket int64
+}{
+	{view: "coin_prices_1d", bucket: 86400},
+	{view: "coin_prices_1h", bucket: 3600},
+	{view: "coin_prices_1m", bucket: 60},
+}
+
+// historyQuery picks the coarsest continuous aggregate whose buckets evenly
+// divide the requested one, falling back to the raw table. Both read the
+// whole buckets of price.Span, so the choice never changes the result.
+func historyQuery(bucket int64) string {
+	for _, agg := range priceAggregates {
+		if bucket%agg.bucket == 0 {
+			return fmt.Sprintf(GetAggregateHistoryQuery, agg.view)
+		}
+	}
+	return GetHistoryQuery
+}
+
 type PriceRepository struct {
 	pool *pgxpool.Pool
 }
@@ -51,25 +258,161 @@ func NewPriceRepository(pool *pgxpool.Pool) *PriceRepository {
 	return &PriceRepository{pool: pool}
 }
 
+// BatchInsert stores prices and their market data in one transaction
 func (r *PriceRepository) BatchInsert(ctx context.Context, prices []*entity.Price) error {
 	if len(prices) == 0 {
 		return nil
 	}
 
 	rows := make([][]interface{}, len(prices))
+	var markets [][]interface{}
 	for i, p := range prices {
 		rows[i] = []interface{}{p.Symbol, p.Price, p.Time}
+		if m := p.Market; m != nil {
+			markets = append(markets, []interface{}{
+				p.Symbol, p.Time, m.MarketCap, m.TotalVolume, m.CirculatingSupply, m.High24h, m.Low24h,
+			})
+		}
 	}
 
-	_, err := r.pool.CopyFrom(
-		ctx,
-		pgx.Identifier{"coin_prices"},
-		[]string{"symbol", "price", "time"},
-		pgx.CopyFromRows(rows),
-	)
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		_, err := tx.CopyFrom(
+			ctx,
+			pgx.Identifier{"coin_prices"},
+			[]string{"symbol", "price", "time"},
+			pgx.CopyFromRows(rows),
+		)
+		if err != nil {
+			return err
+		}
+		if err := countRows(ctx, tx, prices); err != nil {
+			return err
+		}
+		if len(markets) == 0 {
+			return nil
+		}
+		_, err = tx.CopyFrom(
+			ctx,
+			pgx.Identifier{"coin_markets"},
+			[]string{"symbol", "time", "market_cap", "total_volume", "circulating_supply", "high_24h", "low_24h"},
+			pgx.CopyFromRows(markets),
+		)
+		return err
+	})
+}
+
+func (r *PriceRepository) InsertIgnore(ctx context.Context, prices []*entity.Price) (int64, error) {
+	if len(prices) == 0 {
+		return 0, nil
+	}
+
+	symbols := make([]string, len(prices))
+	values := make([]string, len(prices))
+	times := make([]int64, len(prices))
+	var markets marketColumns
+	for i, p := range prices {
+		symbols[i], values[i], times[i] = p.Symbol, p.Price.String(), p.Time
+		if p.Market != nil {
+			markets.add(p)
+		}
+	}
+
+	var inserted int64
+	err := pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		rows, err := tx.Query(ctx, InsertIgnoreQuery, symbols, values, times)
+		if err != nil {
+			return err
+		}
+		stored, err := pgx.CollectRows(rows, func(row pgx.CollectableRow) (*entity.Price, error) {
+			var p entity.Price
+			return &p, row.Scan(&p.Symbol, &p.Time)
+		})
+		if err != nil {
+			return err
+		}
+		inserted = int64(len(stored))
+		if err := countRows(ctx, tx, stored); err != nil {
+			return err
+		}
+		if len(markets.symbols) == 0 {
+			return nil
+		}
+		_, err = tx.Exec(ctx, InsertIgnoreMarketsQuery, markets.symbols, markets.times, markets.marketCaps,
+			markets.totalVolumes, markets.circulatingSupplies, markets.highs, markets.lows)
+		return err
+	})
+	if err != nil {
+		return 0, err
+	}
+	return inserted, nil
+}
+
+// written sums up the rows of one symbol a write added, or removed when n is negative
+type written struct {
+	n                int64
+	minTime, maxTime int64
+}
+
+// countRows adds stored rows to the symbol catalog
+func countRows(ctx context.Context, tx pgx.Tx, prices []*entity.Price) error {
+	writes := make(map[string]*written)
+	for _, p := range prices {
+		w, ok := writes[p.Symbol]
+		if !ok {
+			writes[p.Symbol] = &written{n: 1, minTime: p.Time, maxTime: p.Time}
+			continue
+		}
+		w.n++
+		w.minTime, w.maxTime = min(w.minTime, p.Time), max(w.maxTime, p.Time)
+	}
+	return addRows(ctx, tx, writes)
+}
+
+func addRows(ctx context.Context, tx pgx.Tx, writes map[string]*written) error {
+	if len(writes) == 0 {
+		return nil
+	}
+	// a fixed order keeps concurrent writers from locking catalog rows in opposite orders
+	symbols := slices.Sorted(maps.Keys(writes))
+	ns := make([]int64, len(symbols))
+	minTimes := make([]int64, len(symbols))
+	maxTimes := make([]int64, len(symbols))
+	for i, symbol := range symbols {
+		w := writes[symbol]
+		ns[i], minTimes[i], maxTimes[i] = w.n, w.minTime, w.maxTime
+	}
+	if _, err := tx.Exec(ctx, AddSymbolsQuery, symbols); err != nil {
+		return err
+	}
+	_, err := tx.Exec(ctx, CountRowsQuery, symbols, ns, minTimes, maxTimes, time.Now().Unix())
 	return err
 }
 
+// marketColumns are the market data of a batch as arrays for unnest
+type marketColumns struct {
+	symbols                                       []string
+	times                                         []int64
+	marketCaps, totalVolumes, circulatingSupplies []*string
+	highs, lows                                   []*string
+}
+
+func (c *marketColumns) add(p *entity.Price) {
+	text := func(d decimal.NullDecimal) *string {
+		if !d.Valid {
+			return nil
+		}
+		s := d.Decimal.String()
+		return &s
+	}
+	c.symbols = append(c.symbols, p.Symbol)
+	c.times = append(c.times, p.Time)
+	c.marketCaps = append(c.marketCaps, text(p.Market.MarketCap))
+	c.totalVolumes = append(c.totalVolumes, text(p.Market.TotalVolume))
+	c.circulatingSupplies = append(c.circulatingSupplies, text(p.Market.CirculatingSupply))
+	c.highs = append(c.highs, text(p.Market.High24h))
+	c.lows = append(c.lows, text(p.Market.Low24h))
+}
+
 func (r *PriceRepository) GetLatest(ctx context.Context, req *dto.LatestReq) (*dto.LatestRes, error) {
 	var latest entity.Price
 	err := r.pool.QueryRow(ctx, GetLatestQuery, req.Symbol).Scan(&latest.Symbol, &latest.Price, &latest.Time)
@@ -80,49 +423,270 @@ func (r *PriceRepository) GetLatest(ctx context.Context, req *dto.LatestReq) (*d
 		return nil, err
 	}
 
-	var price24h decimal.Decimal
-	latesttime := time.Unix(latest.Time, 0)
-	from := latesttime.Add(-24 * time.Hour).Unix()
-	err = r.pool.QueryRow(ctx, GetBeforeTimeQuery, req.Symbol, from).Scan(&price24h)
+	var market dto.MarketRes
+	err = r.pool.QueryRow(ctx, GetLatestMarketQuery, req.Symbol, latest.Time).Scan(&market.Timestamp,
+		&market.MarketCap, &market.TotalVolume, &market.CirculatingSupply, &market.High24h, &market.Low24h)
 	if err != nil && !errors.Is(err, pgx.ErrNoRows) {
 		return nil, err
 	}
 
-	changePct := 0.0
-	if !price24h.IsZero() {
-		changePct = latest.Price.Sub(price24h).Div(price24h).Mul(decimal.NewFromInt(100)).InexactFloat64()
+	res := &dto.LatestRes{
+		Symbol:    latest.Symbol,
+		Price:     latest.Price,
+		Timestamp: latest.Time,
+	}
+	if err == nil {
+		res.Market = &market
 	}
+	return res, nil
+}
+
+// GetPricesAt returns the price in effect at each time in at, in the same
+// order; entries are nil when the symbol has no price that early
+func (r *PriceRepository) GetPricesAt(ctx context.Context, symbol string, at []int64) ([]*dto.PricePoint, error) {
+	rows, err := r.pool.Query(ctx, GetPricesAtQuery, symbol, at)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
 
-	return &dto.LatestRes{
-		Symbol:       latest.Symbol,
-		Price:        latest.Price,
-		Timestamp:    latest.Time,
-		Change24HPct: changePct,
-	}, nil
+	points := make([]*dto.PricePoint, 0, len(at))
+	for rows.Next() {
+		var (
+			requested int64
+			p         decimal.NullDecimal
+			t         *int64
+		)
+		if err := rows.Scan(&requested, &p, &t); err != nil {
+			return nil, err
+		}
+		if !p.Valid || t == nil {
+			points = append(points, nil)
+			continue
+		}
+		points = append(points, &dto.PricePoint{Price: p.Decimal, Timestamp: *t})
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	return points, nil
 }
 
 func (r *PriceRepository) GetHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.HistoryRes, error) {
+	var result []*dto.HistoryRes
+	err := r.StreamHistory(ctx, req, func(point *dto.HistoryRes) error {
+		result = append(result, point)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamHistory calls fn for every bucket as it is read from the cursor, so
+// callers can export long ranges without holding them in memory.
+func (r *PriceRepository) StreamHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.HistoryRes) error) error {
 	if req.Interval == "" {
 		req.Interval = DefaultInterval
 	}
+	interval, err := price.ParseInterval(req.Interval)
+	if err != nil {
+		return err
+	}
+	bucket := int64(interval / time.Second)
 
-	rows, err := r.pool.Query(ctx, GetHistoryQuery, req.Interval, req.Symbol, req.From, req.To)
+	from, to := price.Span(interval, req.From, req.To)
+	rows, err := r.pool.Query(ctx, historyQuery(bucket), bucket, req.Symbol, from, to)
 	if err != nil {
-		return nil, err
+		return err
 	}
 	defer rows.Close()
 
-	var result []*dto.HistoryRes
+	count := 0
 	for rows.Next() {
 		var point dto.HistoryRes
 		if err := rows.Scan(&point.StartedAt, &point.Symbol, &point.AvgPrice, &point.LastPrice); err != nil {
+			return err
+		}
+		if err := fn(&point); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return price.ErrPriceNotFound
+	}
+	return nil
+}
+
+func (r *PriceRepository) GetMarketHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.MarketHistoryRes, error) {
+	var result []*dto.MarketHistoryRes
+	err := r.StreamMarketHistory(ctx, req, func(point *dto.MarketHistoryRes) error {
+		result = append(result, point)
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// StreamMarketHistory calls fn for every bucket of market data, holding the
+// values last reported within it.
+func (r *PriceRepository) StreamMarketHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.MarketHistoryRes) error) error {
+	if req.Interval == "" {
+		req.Interval = DefaultInterval
+	}
+	interval, err := price.ParseInterval(req.Interval)
+	if err != nil {
+		return err
+	}
+	bucket := int64(interval / time.Second)
+
+	from, to := price.Span(interval, req.From, req.To)
+	rows, err := r.pool.Query(ctx, GetMarketHistoryQuery, bucket, req.Symbol, from, to)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var point dto.MarketHistoryRes
+		err := rows.Scan(&point.StartedAt, &point.Symbol, &point.Timestamp, &point.MarketCap,
+			&point.TotalVolume, &point.CirculatingSupply, &point.High24h, &point.Low24h)
+		if err != nil {
+			return err
+		}
+		if err := fn(&point); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return price.ErrPriceNotFound
+	}
+	return nil
+}
+
+// StreamTicks calls fn for every raw row in the range, ordered by req.Order.
+// A zero req.Limit streams the whole range.
+func (r *PriceRepository) StreamTicks(ctx context.Context, req *dto.TicksReq, fn func(*entity.Price) error) error {
+	direction := "ASC"
+	if req.Order == price.OrderDesc {
+		direction = "DESC"
+	}
+	var limit *int
+	if req.Limit > 0 {
+		limit = &req.Limit
+	}
+
+	rows, err := r.pool.Query(ctx, fmt.Sprintf(GetTicksQuery, direction), req.Symbol, req.From, req.To, limit)
+	if err != nil {
+		return err
+	}
+	defer rows.Close()
+
+	count := 0
+	for rows.Next() {
+		var tick entity.Price
+		if err := rows.Scan(&tick.Symbol, &tick.Price, &tick.Time); err != nil {
+			return err
+		}
+		if err := fn(&tick); err != nil {
+			return err
+		}
+		count++
+	}
+	if err := rows.Err(); err != nil {
+		return err
+	}
+
+	if count == 0 {
+		return price.ErrPriceNotFound
+	}
+	return nil
+}
+
+func (r *PriceRepository) GetRecentTicks(ctx context.Context, symbols []string, from, to int64) ([]*entity.Price, error) {
+	rows, err := r.pool.Query(ctx, GetRecentTicksQuery, symbols, from, to)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ticks []*entity.Price
+	for rows.Next() {
+		var tick entity.Price
+		if err := rows.Scan(&tick.Symbol, &tick.Price, &tick.Time); err != nil {
 			return nil, err
 		}
-		result = append(result, &point)
+		ticks = append(ticks, &tick)
 	}
+	return ticks, rows.Err()
+}
+
+func (r *PriceRepository) DeleteTick(ctx context.Context, p *entity.Price) error {
+	return pgx.BeginFunc(ctx, r.pool, func(tx pgx.Tx) error {
+		tag, err := tx.Exec(ctx, DeleteTickQuery, p.Symbol, p.Time, p.Price)
+		if err != nil || tag.RowsAffected() == 0 {
+			return err
+		}
+		if err := addRows(ctx, tx, map[string]*written{p.Symbol: {n: -1, minTime: p.Time, maxTime: p.Time}}); err != nil {
+			return err
+		}
+		_, err = tx.Exec(ctx, DeleteMarketQuery, p.Symbol, p.Time)
+		return err
+	})
+}
 
-	if len(result) == 0 {
+func (r *PriceRepository) GetVersion(ctx context.Context, symbol string, to int64) (*entity.DataVersion, error) {
+	var (
+		v    entity.DataVersion
+		last *int64
+	)
+	err := r.pool.QueryRow(ctx, GetVersionQuery, symbol, to).Scan(&last, &v.Revision, &v.RevisedAt)
+	if err != nil {
+		if errors.Is(err, pgx.ErrNoRows) {
+			return nil, price.ErrPriceNotFound
+		}
+		return nil, err
+	}
+	if last == nil {
 		return nil, price.ErrPriceNotFound
 	}
-	return result, nil
+	v.LastTime = *last
+	return &v, nil
+}
+
+func (r *PriceRepository) GetTWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error) {
+	return r.average(ctx, GetTWAPQuery, req, "twap", price.ErrPriceNotFound)
+}
+
+func (r *PriceRepository) GetVWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error) {
+	return r.average(ctx, GetVWAPQuery, req, "vwap", price.ErrVolumeNotFound)
+}
+
+// average runs a weighted average query, failing with notFound when there was nothing to weight
+func (r *PriceRepository) average(ctx context.Context, query string, req *dto.AverageReq, method string, notFound error) (*dto.AverageRes, error) {
+	var avg decimal.NullDecimal
+	res := &dto.AverageRes{Symbol: req.Symbol, Method: method, From: req.From, To: req.To}
+	err := r.pool.QueryRow(ctx, query, req.Symbol, req.From, req.To).Scan(&avg, &res.Ticks, &res.CoveredSeconds)
+	if err != nil {
+		return nil, err
+	}
+	if !avg.Valid {
+		return nil, notFound
+	}
+	res.Price = avg.Decimal
+	return res, nil
 }
@@ -0,0 +1,397 @@
+package pgx
+
+import (
+	"context"
+	"errors"
+	"slices"
+	"testing"
+
+	"github.com/jackc/pgx/v5/pgxpool"
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql"
+	"github.com/milad-rasouli/price/internal/infrastructure/postgresql/pgtest"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+// t0 starts a day, so it starts a bucket of every aggregate
+const t0 = 1735689600
+
+func tick(symbol string, p int64, at int64) *entity.Price {
+	return &entity.Price{Symbol: symbol, Price: decimal.NewFromInt(p), Time: at}
+}
+
+func exec(t *testing.T, pool *pgxpool.Pool, sql string, args ...any) {
+	t.Helper()
+	if _, err := pool.Exec(context.Background(), sql, args...); err != nil {
+		t.Fatalf("%s: %v", sql, err)
+	}
+}
+
+func TestGetVersionRevisions(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+
+	if _, err := r.GetVersion(ctx, "btc", t0); !errors.Is(err, price.ErrPriceNotFound) {
+		t.Fatalf("GetVersion() of an unknown symbol error = %v, want ErrPriceNotFound", err)
+	}
+
+	steps := []struct {
+		name     string
+		write    func() error
+		to       int64
+		lastTime int64
+		revision int64
+	}{
+		{
+			name: "first ticks",
+			write: func() error {
+				return r.BatchInsert(ctx, []*entity.Price{tick("btc", 100, t0), tick("btc", 101, t0+60)})
+			},
+			to:       t0 + 60,
+			lastTime: t0 + 60,
+		},
+		{
+			name:     "up to a time between ticks",
+			write:    func() error { return nil },
+			to:       t0 + 59,
+			lastTime: t0,
+		},
+		{
+			name:     "appended tick",
+			write:    func() error { return r.BatchInsert(ctx, []*entity.Price{tick("btc", 102, t0+120)}) },
+			to:       t0 + 120,
+			lastTime: t0 + 120,
+		},
+		{
+			name: "backfilled tick",
+			write: func() error {
+				_, err := r.InsertIgnore(ctx, []*entity.Price{tick("btc", 99, t0+30), tick("btc", 100, t0)})
+				return err
+			},
+			to:       t0 + 120,
+			lastTime: t0 + 120,
+			revision: 1,
+		},
+		{
+			name: "backfill of stored ticks only",
+			write: func() error {
+				_, err := r.InsertIgnore(ctx, []*entity.Price{tick("btc", 99, t0+30)})
+				return err
+			},
+			to:       t0 + 120,
+			lastTime: t0 + 120,
+			revision: 1,
+		},
+		{
+			name:     "deleted tick",
+			write:    func() error { return r.DeleteTick(ctx, tick("btc", 99, t0+30)) },
+			to:       t0 + 120,
+			lastTime: t0 + 120,
+			revision: 2,
+		},
+		{
+			name:     "deleted tick whose price changed",
+			write:    func() error { return r.DeleteTick(ctx, tick("btc", 1, t0+60)) },
+			to:       t0 + 120,
+			lastTime: t0 + 120,
+			revision: 2,
+		},
+	}
+	for _, step := range steps {
+		if err := step.write(); err != nil {
+			t.Fatalf("%s: %v", step.name, err)
+		}
+		v, err := r.GetVersion(ctx, "btc", step.to)
+		if err != nil {
+			t.Fatalf("%s: GetVersion() error = %v", step.name, err)
+		}
+		if v.LastTime != step.lastTime || v.Revision != step.revision {
+			t.Errorf("%s: GetVersion() = last time %d, revision %d, want %d, %d",
+				step.name, v.LastTime, v.Revision, step.lastTime, step.revision)
+		}
+	}
+}
+
+// TestGetVersionFallback drops raw rows behind the aggregates' back, as
+// retention does, and expects the version to come from what covers them.
+func TestGetVersionFallback(t *testing.T) {
+	ctx := context.Background()
+	pool := pgtest.Migrate(t).Pool
+	r := NewPriceRepository(pool)
+
+	if err := r.BatchInsert(ctx, []*entity.Price{tick("btc", 100, t0+5), tick("btc", 101, t0+65)}); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+	exec(t, pool, `CALL refresh_continuous_aggregate('coin_prices_1m', NULL, NULL)`)
+	exec(t, pool, `DELETE FROM coin_prices WHERE symbol = 'btc' AND time < $1`, t0+60)
+	exec(t, pool, `INSERT INTO coin_prices_archive VALUES ('coin_prices_1m', $1, 'btc', 90, 1, 90, 90, 90, 90)`, t0-3600)
+
+	tests := []struct {
+		name string
+		to   int64
+		want int64 // zero when nothing is stored up to to
+	}{
+		{"raw row", t0 + 70, t0 + 65},
+		{"aggregate bucket", t0 + 59, t0},
+		{"archived bucket", t0 - 1, t0 - 3600},
+		{"before everything", t0 - 3601, 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			v, err := r.GetVersion(ctx, "btc", tt.to)
+			if tt.want == 0 {
+				if !errors.Is(err, price.ErrPriceNotFound) {
+					t.Fatalf("GetVersion() error = %v, want ErrPriceNotFound", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("GetVersion() error = %v", err)
+			}
+			if v.LastTime != tt.want {
+				t.Errorf("GetVersion() last time = %d, want %d", v.LastTime, tt.want)
+			}
+		})
+	}
+}
+
+// TestStreamTicksKeyset pages through ticks the way the ticks cursor does,
+// resuming past the last time of the previous page.
+func TestStreamTicksKeyset(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+
+	var stored []*entity.Price
+	for i := range int64(5) {
+		stored = append(stored, tick("btc", 100+i, t0+60*i))
+	}
+	if err := r.BatchInsert(ctx, append(stored, tick("eth", 1, t0))); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+
+	tests := []struct {
+		order string
+		want  [][]int64 // times of each page
+	}{
+		{price.OrderAsc, [][]int64{{t0, t0 + 60}, {t0 + 120, t0 + 180}, {t0 + 240}}},
+		{price.OrderDesc, [][]int64{{t0 + 240, t0 + 180}, {t0 + 120, t0 + 60}, {t0}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.order, func(t *testing.T) {
+			req := &dto.TicksReq{Symbol: "btc", From: t0, To: t0 + 240, Limit: 2, Order: tt.order}
+			for i, want := range tt.want {
+				var got []int64
+				err := r.StreamTicks(ctx, req, func(p *entity.Price) error {
+					got = append(got, p.Time)
+					return nil
+				})
+				if err != nil {
+					t.Fatalf("page %d: StreamTicks() error = %v", i, err)
+				}
+				if !slices.Equal(got, want) {
+					t.Fatalf("page %d = %v, want %v", i, got, want)
+				}
+				if last := got[len(got)-1]; tt.order == price.OrderAsc {
+					req.From = last + 1
+				} else {
+					req.To = last - 1
+				}
+			}
+			err := r.StreamTicks(ctx, req, func(p *entity.Price) error { return nil })
+			if !errors.Is(err, price.ErrPriceNotFound) {
+				t.Errorf("StreamTicks() past the last page error = %v, want ErrPriceNotFound", err)
+			}
+		})
+	}
+}
+
+// TestHistoryReadsArchive migrates aggregates whose first raw row retention
+// already dropped: the whole bucket is archived by migration 004 and read
+// back in place of the one rebuilt from the rows that were left.
+func TestHistoryReadsArchive(t *testing.T) {
+	ctx := context.Background()
+	cfg := pgtest.Config(t)
+	mg, err := postgresql.NewMigrator(cfg)
+	if err != nil {
+		t.Fatalf("NewMigrator() error = %v", err)
+	}
+	defer mg.Close()
+	if err := mg.Goto(3); err != nil {
+		t.Fatalf("Goto(3) error = %v", err)
+	}
+	pool := pgtest.Connect(t, cfg).Pool
+	exec(t, pool, `INSERT INTO coin_prices (symbol, price, time) VALUES ('btc', 100, $1), ('btc', 110, $2), ('btc', 120, $3)`,
+		t0+5, t0+65, t0+125)
+	for _, view := range []string{"coin_prices_1m", "coin_prices_1h", "coin_prices_1d"} {
+		exec(t, pool, `CALL refresh_continuous_aggregate($1::regclass, NULL, NULL)`, view)
+	}
+	exec(t, pool, `DELETE FROM coin_prices WHERE time = $1`, t0+5)
+	if err := mg.Up(); err != nil {
+		t.Fatalf("Up() error = %v", err)
+	}
+	r := NewPriceRepository(pool)
+
+	tests := []struct {
+		interval string
+		to       int64
+		want     [][3]int64 // started at, average and last price of each bucket
+	}{
+		{"1m", t0 + 179, [][3]int64{{t0, 100, 100}, {t0 + 60, 110, 110}, {t0 + 120, 120, 120}}},
+		{"1h", t0, [][3]int64{{t0, 110, 120}}},
+		{"1d", t0, [][3]int64{{t0, 110, 120}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.interval, func(t *testing.T) {
+			got, err := r.GetHistory(ctx, &dto.HistoryReq{Symbol: "btc", Interval: tt.interval, From: t0, To: tt.to})
+			if err != nil {
+				t.Fatalf("GetHistory() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("GetHistory() returned %d buckets, want %d", len(got), len(tt.want))
+			}
+			for i, want := range tt.want {
+				b := got[i]
+				if b.StartedAt != want[0] || !b.AvgPrice.Equal(decimal.NewFromInt(want[1])) || !b.LastPrice.Equal(decimal.NewFromInt(want[2])) {
+					t.Errorf("bucket %d = %d avg %s last %s, want %v", i, b.StartedAt, b.AvgPrice, b.LastPrice, want)
+				}
+			}
+		})
+	}
+}
+
+func TestPricesKeepEveryDigit(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+
+	want := decimal.RequireFromString("0.000000000001234567890123")
+	if err := r.BatchInsert(ctx, []*entity.Price{{Symbol: "shib", Price: want, Time: t0}}); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+	got, err := r.GetLatest(ctx, &dto.LatestReq{Symbol: "shib"})
+	if err != nil {
+		t.Fatalf("GetLatest() error = %v", err)
+	}
+	if !got.Price.Equal(want) {
+		t.Errorf("GetLatest() price = %s, want %s", got.Price, want)
+	}
+}
+
+func TestMarketData(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+
+	value := func(n int64) decimal.NullDecimal { return decimal.NewNullDecimal(decimal.NewFromInt(n)) }
+	first := tick("btc", 100, t0)
+	first.Market = &entity.Market{MarketCap: value(1000), TotalVolume: value(10)}
+	if err := r.BatchInsert(ctx, []*entity.Price{first, tick("btc", 101, t0+60)}); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+	backfilled, restated := tick("btc", 99, t0+30), tick("btc", 100, t0)
+	backfilled.Market = &entity.Market{TotalVolume: value(20)}
+	restated.Market = &entity.Market{MarketCap: value(5)}
+	if _, err := r.InsertIgnore(ctx, []*entity.Price{backfilled, restated}); err != nil {
+		t.Fatalf("InsertIgnore() error = %v", err)
+	}
+
+	latest, err := r.GetLatest(ctx, &dto.LatestReq{Symbol: "btc"})
+	if err != nil {
+		t.Fatalf("GetLatest() error = %v", err)
+	}
+	if latest.Timestamp != t0+60 || latest.Market == nil || latest.Market.Timestamp != t0+30 ||
+		!latest.Market.TotalVolume.Decimal.Equal(decimal.NewFromInt(20)) || latest.Market.MarketCap.Valid {
+		t.Errorf("GetLatest() = %+v with market %+v, want the tick at %d with the market data of %d",
+			latest, latest.Market, t0+60, t0+30)
+	}
+
+	history, err := r.GetMarketHistory(ctx, &dto.HistoryReq{Symbol: "btc", Interval: "1m", From: t0, To: t0 + 119})
+	if err != nil {
+		t.Fatalf("GetMarketHistory() error = %v", err)
+	}
+	if len(history) != 1 {
+		t.Fatalf("GetMarketHistory() returned %d buckets, want 1", len(history))
+	}
+	if b := history[0]; b.StartedAt != t0 || b.Timestamp != t0+30 || !b.TotalVolume.Decimal.Equal(decimal.NewFromInt(20)) {
+		t.Errorf("GetMarketHistory() bucket = %+v, want the market data of %d", b, t0+30)
+	}
+}
+
+func TestWeightedAverages(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+
+	// the tick before the window is in effect when it opens
+	held := []*entity.Price{tick("btc", 100, t0), tick("btc", 200, t0+60), tick("btc", 400, t0+120)}
+	for i, volume := range []int64{1, 3, 0} {
+		if volume > 0 {
+			held[i].Market = &entity.Market{TotalVolume: decimal.NewNullDecimal(decimal.NewFromInt(volume))}
+		}
+	}
+	if err := r.BatchInsert(ctx, held); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+
+	tests := []struct {
+		name     string
+		average  func(context.Context, *dto.AverageReq) (*dto.AverageRes, error)
+		from, to int64
+		price    int64
+		ticks    int64
+		covered  int64
+		err      error
+	}{
+		{"twap", r.GetTWAP, t0 + 30, t0 + 90, 150, 2, 60, nil},
+		{"vwap", r.GetVWAP, t0 + 30, t0 + 90, 175, 2, 60, nil},
+		{"twap before the first tick", r.GetTWAP, t0 - 60, t0 - 1, 0, 0, 0, price.ErrPriceNotFound},
+		{"vwap before the first tick", r.GetVWAP, t0 - 60, t0 - 1, 0, 0, 0, price.ErrVolumeNotFound},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := tt.average(ctx, &dto.AverageReq{Symbol: "btc", From: tt.from, To: tt.to})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("error = %v", err)
+			}
+			if !got.Price.Equal(decimal.NewFromInt(tt.price)) || got.Ticks != tt.ticks || got.CoveredSeconds != tt.covered {
+				t.Errorf("got %s over %d ticks and %ds, want %d over %d ticks and %ds",
+					got.Price, got.Ticks, got.CoveredSeconds, tt.price, tt.ticks, tt.covered)
+			}
+		})
+	}
+}
+
+func TestGetPricesAt(t *testing.T) {
+	ctx := context.Background()
+	r := NewPriceRepository(pgtest.Migrate(t).Pool)
+	if err := r.BatchInsert(ctx, []*entity.Price{tick("btc", 100, t0), tick("btc", 200, t0+60), tick("eth", 1, t0-60)}); err != nil {
+		t.Fatalf("BatchInsert() error = %v", err)
+	}
+
+	// in the requested order, nil before the first tick
+	at := []int64{t0 + 90, t0 - 1, t0 + 60, t0 + 59}
+	want := []*dto.PricePoint{
+		{Price: decimal.NewFromInt(200), Timestamp: t0 + 60},
+		nil,
+		{Price: decimal.NewFromInt(200), Timestamp: t0 + 60},
+		{Price: decimal.NewFromInt(100), Timestamp: t0},
+	}
+	got, err := r.GetPricesAt(ctx, "btc", at)
+	if err != nil {
+		t.Fatalf("GetPricesAt() error = %v", err)
+	}
+	if len(got) != len(want) {
+		t.Fatalf("GetPricesAt() returned %d points, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if (got[i] == nil) != (want[i] == nil) ||
+			got[i] != nil && (got[i].Timestamp != want[i].Timestamp || !got[i].Price.Equal(want[i].Price)) {
+			t.Errorf("price at %d = %+v, want %+v", at[i], got[i], want[i])
+		}
+	}
+}
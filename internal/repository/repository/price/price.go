@@ -7,13 +7,33 @@ import (
 	"github.com/milad-rasouli/price/internal/app/api/dto"
 )
 
+const (
+	OrderAsc  = "asc"
+	OrderDesc = "desc"
+)
+
 var (
-	ErrPriceNotFound = errors.New("price not found")
+	ErrPriceNotFound  = errors.New("price not found")
+	ErrVolumeNotFound = errors.New("no volume recorded in the window")
 )
 
 //go:generate mockgen -source=price.go -destination=../../../../mock/repository/price/price.go
 type PriceRepository interface {
 	BatchInsert(ctx context.Context, p []*entity.Price) error
+	InsertIgnore(ctx context.Context, p []*entity.Price) (int64, error)
 	GetLatest(ctx context.Context, req *dto.LatestReq) (*dto.LatestRes, error)
+	GetPricesAt(ctx context.Context, symbol string, at []int64) ([]*dto.PricePoint, error)
 	GetHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.HistoryRes, error)
+	StreamHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.HistoryRes) error) error
+	GetMarketHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.MarketHistoryRes, error)
+	StreamMarketHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.MarketHistoryRes) error) error
+	StreamTicks(ctx context.Context, req *dto.TicksReq, fn func(*entity.Price) error) error
+	// GetRecentTicks returns the raw rows of symbols in [from, to), ordered by symbol and time
+	GetRecentTicks(ctx context.Context, symbols []string, from, to int64) ([]*entity.Price, error)
+	// DeleteTick removes a stored tick and its market data, unless its price changed since
+	DeleteTick(ctx context.Context, p *entity.Price) error
+	// GetVersion identifies the data of a symbol up to to, for conditional requests
+	GetVersion(ctx context.Context, symbol string, to int64) (*entity.DataVersion, error)
+	GetTWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error)
+	GetVWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error)
 }
@@ -2,6 +2,14 @@ package repository
 
 import (
 	"github.com/google/wire"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+	alertpgx "github.com/milad-rasouli/price/internal/repository/repository/alert/pgx"
+	"github.com/milad-rasouli/price/internal/repository/repository/anomaly"
+	anomalypgx "github.com/milad-rasouli/price/internal/repository/repository/anomaly/pgx"
+	"github.com/milad-rasouli/price/internal/repository/repository/basket"
+	basketpgx "github.com/milad-rasouli/price/internal/repository/repository/basket/pgx"
+	"github.com/milad-rasouli/price/internal/repository/repository/catalog"
+	catalogpgx "github.com/milad-rasouli/price/internal/repository/repository/catalog/pgx"
 	"github.com/milad-rasouli/price/internal/repository/repository/price"
 	"github.com/milad-rasouli/price/internal/repository/repository/price/pgx"
 )
@@ -9,4 +17,12 @@ import (
 var ProviderSet = wire.NewSet(
 	wire.Bind(new(price.PriceRepository), new(*pgx.PriceRepository)),
 	pgx.NewPriceRepository,
+	wire.Bind(new(basket.BasketRepository), new(*basketpgx.BasketRepository)),
+	basketpgx.NewBasketRepository,
+	wire.Bind(new(alert.AlertRepository), new(*alertpgx.AlertRepository)),
+	alertpgx.NewAlertRepository,
+	wire.Bind(new(anomaly.AnomalyRepository), new(*anomalypgx.AnomalyRepository)),
+	anomalypgx.NewAnomalyRepository,
+	wire.Bind(new(catalog.CatalogRepository), new(*catalogpgx.CatalogRepository)),
+	catalogpgx.NewCatalogRepository,
 )
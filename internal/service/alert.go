@@ -0,0 +1,420 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log/slog"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/infrastructure/ratelimit"
+	"github.com/milad-rasouli/price/internal/infrastructure/webhook"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+const (
+	DefaultDeliveriesLimit = 50
+	MaxDeliveriesLimit     = 500
+
+	// OutboxPollInterval is how often RunDeliveries looks for due events when
+	// no rule fired in between
+	OutboxPollInterval = 5 * time.Second
+	// outboxLease is how long a claimed event is held on top of the attempt's
+	// timeout before another worker may take it over
+	outboxLease = time.Minute
+)
+
+var ErrAlertsDisabled = errors.New("alerts are disabled until alerts.signing_secret is set")
+
+//go:generate mockgen -source=alert.go -destination=../../mock/service/alert/alert.go
+type AlertService interface {
+	CreateRule(ctx context.Context, req *dto.AlertRuleReq) (*dto.AlertRuleRes, error)
+	ListRules(ctx context.Context) ([]*dto.AlertRuleRes, error)
+	DeleteRule(ctx context.Context, id int64) error
+	ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*dto.AlertDeliveryRes, error)
+	// Evaluate checks the rules on the symbols of a freshly inserted batch and
+	// queues the events of the rules that fired for RunDeliveries
+	Evaluate(ctx context.Context, prices []*entity.Price) error
+	// RunDeliveries delivers queued events until ctx is done, finishing the
+	// attempt in flight first
+	RunDeliveries(ctx context.Context)
+}
+
+type alertService struct {
+	logger *slog.Logger
+	store  *config.Store
+	repo   alert.AlertRepository
+	prices price.PriceRepository
+	client *http.Client
+	// wake tells RunDeliveries that events were queued
+	wake chan struct{}
+}
+
+func NewAlertService(
+	logger *slog.Logger,
+	store *config.Store,
+	repo alert.AlertRepository,
+	prices price.PriceRepository,
+) AlertService {
+	return &alertService{
+		logger: logger.With("Layer", "AlertService"),
+		store:  store,
+		repo:   repo,
+		prices: prices,
+		// webhooks must answer directly, a redirect could leak the payload elsewhere
+		client: &http.Client{CheckRedirect: func(*http.Request, []*http.Request) error { return http.ErrUseLastResponse }},
+		wake:   make(chan struct{}, 1),
+	}
+}
+
+func (s *alertService) CreateRule(ctx context.Context, req *dto.AlertRuleReq) (*dto.AlertRuleRes, error) {
+	lg := s.logger.With("method", "CreateRule")
+
+	if s.store.Current().Alerts.SigningSecret == "" {
+		return nil, ErrAlertsDisabled
+	}
+	rule := &entity.AlertRule{
+		Symbol:     strings.ToLower(strings.TrimSpace(req.Symbol)),
+		Kind:       req.Kind,
+		Threshold:  req.Threshold,
+		WebhookURL: req.WebhookURL,
+		CreatedAt:  time.Now().Unix(),
+	}
+	if rule.Symbol == "" {
+		return nil, fmt.Errorf("%w: symbol is required", ErrInvalidRequest)
+	}
+	switch rule.Kind {
+	case entity.AlertCrossesAbove, entity.AlertCrossesBelow:
+		if req.Window != "" {
+			return nil, fmt.Errorf("%w: window only applies to change rules", ErrInvalidRequest)
+		}
+	case entity.AlertChange:
+		window, err := price.ParseInterval(req.Window)
+		if err != nil {
+			return nil, fmt.Errorf("%w: change rules need a window such as 1h or 1d", ErrInvalidRequest)
+		}
+		rule.Window = int64(window / time.Second)
+	default:
+		return nil, fmt.Errorf("%w: kind %q must be %s, %s or %s", ErrInvalidRequest, req.Kind,
+			entity.AlertCrossesAbove, entity.AlertCrossesBelow, entity.AlertChange)
+	}
+	if !rule.Threshold.IsPositive() {
+		return nil, fmt.Errorf("%w: threshold must be positive", ErrInvalidRequest)
+	}
+	if u, err := url.Parse(rule.WebhookURL); err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("%w: webhook_url %q is not an http(s) URL", ErrInvalidRequest, rule.WebhookURL)
+	}
+
+	if err := s.repo.CreateRule(ctx, rule); err != nil {
+		lg.Error("failed to create alert rule", "symbol", rule.Symbol, "error", err)
+		return nil, err
+	}
+
+	lg.Info("created alert rule", "id", rule.ID, "symbol", rule.Symbol, "kind", rule.Kind)
+	return alertRuleRes(rule), nil
+}
+
+func (s *alertService) ListRules(ctx context.Context) ([]*dto.AlertRuleRes, error) {
+	lg := s.logger.With("method", "ListRules")
+
+	rules, err := s.repo.ListRules(ctx, nil)
+	if err != nil {
+		lg.Error("failed to list alert rules", "error", err)
+		return nil, err
+	}
+	res := make([]*dto.AlertRuleRes, len(rules))
+	for i, rule := range rules {
+		res[i] = alertRuleRes(rule)
+	}
+	return res, nil
+}
+
+// DeleteRule removes the rule along with its delivery log
+func (s *alertService) DeleteRule(ctx context.Context, id int64) error {
+	lg := s.logger.With("method", "DeleteRule")
+
+	if err := s.repo.DeleteRule(ctx, id); err != nil {
+		if !errors.Is(err, alert.ErrRuleNotFound) {
+			lg.Error("failed to delete alert rule", "id", id, "error", err)
+		}
+		return err
+	}
+	lg.Info("deleted alert rule", "id", id)
+	return nil
+}
+
+func (s *alertService) ListDeliveries(ctx context.Context, ruleID int64, limit int) ([]*dto.AlertDeliveryRes, error) {
+	lg := s.logger.With("method", "ListDeliveries")
+
+	if limit == 0 {
+		limit = DefaultDeliveriesLimit
+	}
+	if limit < 0 || limit > MaxDeliveriesLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRequest, MaxDeliveriesLimit)
+	}
+
+	deliveries, err := s.repo.ListDeliveries(ctx, ruleID, limit)
+	if err != nil {
+		if !errors.Is(err, alert.ErrRuleNotFound) {
+			lg.Error("failed to list deliveries", "rule", ruleID, "error", err)
+		}
+		return nil, err
+	}
+	res := make([]*dto.AlertDeliveryRes, len(deliveries))
+	for i, d := range deliveries {
+		res[i] = &dto.AlertDeliveryRes{
+			ID:         d.ID,
+			EventID:    d.EventID,
+			Attempt:    d.Attempt,
+			StatusCode: d.StatusCode,
+			Error:      d.Error,
+			Delivered:  d.Delivered,
+			Payload:    d.Payload,
+			Time:       d.Time,
+		}
+	}
+	return res, nil
+}
+
+func (s *alertService) Evaluate(ctx context.Context, prices []*entity.Price) error {
+	lg := s.logger.With("method", "Evaluate")
+
+	latest := make(map[string]*entity.Price, len(prices))
+	symbols := make([]string, 0, len(prices))
+	for _, p := range prices {
+		if _, ok := latest[p.Symbol]; !ok {
+			symbols = append(symbols, p.Symbol)
+		}
+		latest[p.Symbol] = p
+	}
+	rules, err := s.repo.ListRules(ctx, symbols)
+	if err != nil || len(rules) == 0 {
+		return err
+	}
+
+	// a rule that fails is retried with the next tick, it must not hold back the others
+	var (
+		fired int
+		errs  []error
+	)
+	for _, rule := range rules {
+		ok, err := s.evaluate(ctx, rule, latest[rule.Symbol])
+		if err != nil {
+			lg.Error("failed to evaluate alert rule", "rule", rule.ID, "symbol", rule.Symbol, "error", err)
+			errs = append(errs, fmt.Errorf("rule %d: %w", rule.ID, err))
+			continue
+		}
+		if ok {
+			fired++
+		}
+	}
+
+	if fired > 0 {
+		select {
+		case s.wake <- struct{}{}:
+		default:
+		}
+	}
+	lg.Info("evaluated alert rules", "rules", len(rules), "fired", fired, "failed", len(errs))
+	return errors.Join(errs...)
+}
+
+// evaluate records the state of one rule at p and queues its event when it
+// fired; it reports whether it did
+func (s *alertService) evaluate(ctx context.Context, rule *entity.AlertRule, p *entity.Price) (bool, error) {
+	event, holds, err := s.check(ctx, rule, p)
+	if err != nil {
+		return false, err
+	}
+	if event == nil {
+		return false, nil // no reference price yet, keep the state
+	}
+
+	// fire on the transition into the condition, not on every tick it holds;
+	// the first evaluation only records the state
+	fire := holds && rule.Active != nil && !*rule.Active
+	if !fire {
+		if rule.Active == nil || *rule.Active != holds {
+			return false, s.repo.SetState(ctx, rule.ID, holds)
+		}
+		return false, nil
+	}
+
+	body, err := json.Marshal(event)
+	if err != nil {
+		return false, err
+	}
+	queued := &entity.AlertOutboxEvent{EventID: event.ID, RuleID: rule.ID, Payload: string(body), CreatedAt: time.Now().Unix()}
+	if err := s.repo.Fire(ctx, rule.ID, p.Time, queued); err != nil {
+		return false, err
+	}
+	return true, nil
+}
+
+// check builds the event the rule would send at p and whether its condition
+// holds; the event is nil when a change rule has no reference price yet
+func (s *alertService) check(ctx context.Context, rule *entity.AlertRule, p *entity.Price) (*dto.AlertEvent, bool, error) {
+	event := &dto.AlertEvent{
+		ID:            fmt.Sprintf("%d-%d", rule.ID, p.Time),
+		RuleID:        rule.ID,
+		Symbol:        rule.Symbol,
+		Kind:          rule.Kind,
+		Threshold:     rule.Threshold,
+		WindowSeconds: rule.Window,
+		Price:         p.Price,
+		Time:          p.Time,
+	}
+
+	switch rule.Kind {
+	case entity.AlertCrossesAbove:
+		return event, p.Price.GreaterThanOrEqual(rule.Threshold), nil
+	case entity.AlertCrossesBelow:
+		return event, p.Price.LessThanOrEqual(rule.Threshold), nil
+	}
+
+	points, err := s.prices.GetPricesAt(ctx, rule.Symbol, []int64{p.Time - rule.Window})
+	if err != nil {
+		return nil, false, err
+	}
+	if len(points) == 0 || points[0] == nil || points[0].Price.IsZero() {
+		return nil, false, nil
+	}
+	changePct := p.Price.Sub(points[0].Price).Mul(hundred).DivRound(points[0].Price, ratioPrecision)
+	event.Reference, event.ChangePct = points[0], &changePct
+	return event, changePct.Abs().GreaterThanOrEqual(rule.Threshold), nil
+}
+
+func (s *alertService) RunDeliveries(ctx context.Context) {
+	ticker := time.NewTicker(OutboxPollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.deliverDue(ctx)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		case <-s.wake:
+		}
+	}
+}
+
+// deliverDue attempts every due event once, oldest first
+func (s *alertService) deliverDue(ctx context.Context) {
+	lg := s.logger.With("method", "deliverDue")
+
+	for ctx.Err() == nil {
+		cfg := s.store.Current().Alerts
+		lease := int64((cfg.Timeout.Duration + outboxLease) / time.Second)
+		event, err := s.repo.ClaimDue(ctx, time.Now().Unix(), lease)
+		if err != nil {
+			if ctx.Err() == nil {
+				lg.Error("failed to claim alert event", "error", err)
+			}
+			return
+		}
+		if event == nil {
+			return
+		}
+		// a shutdown lets the attempt finish rather than leaving it to the lease
+		s.deliver(context.WithoutCancel(ctx), &cfg, event)
+	}
+}
+
+// deliver makes one attempt at posting the event and logs it. A failed event
+// is rescheduled with backoff; client errors other than 408 and 429 aren't
+// retried, and neither is the last attempt.
+func (s *alertService) deliver(ctx context.Context, cfg *config.Alerts, event *entity.AlertOutboxEvent) {
+	lg := s.logger.With("method", "deliver", "rule", event.RuleID, "event", event.EventID)
+
+	attempt := event.Attempts + 1
+	status, err := s.post(ctx, cfg, event.WebhookURL, event.EventID, attempt, []byte(event.Payload))
+	d := &entity.AlertDelivery{
+		RuleID:    event.RuleID,
+		EventID:   event.EventID,
+		Attempt:   attempt,
+		Delivered: err == nil && status >= 200 && status < 300,
+		Payload:   event.Payload,
+		Time:      time.Now().Unix(),
+	}
+	if status != 0 {
+		d.StatusCode = &status
+	}
+	if err != nil {
+		d.Error = err.Error()
+	} else if !d.Delivered {
+		d.Error = http.StatusText(status)
+	}
+	if err := s.repo.LogDelivery(ctx, d); err != nil {
+		lg.Error("failed to log delivery", "error", err)
+	}
+
+	switch {
+	case d.Delivered:
+		lg.Info("delivered alert", "attempt", attempt, "status", status)
+	case status >= 400 && status < 500 && status != http.StatusRequestTimeout && status != http.StatusTooManyRequests:
+		lg.Warn("webhook rejected alert", "status", status)
+	case attempt >= cfg.Retry.MaxAttempts:
+		lg.Error("giving up on alert delivery", "attempts", attempt)
+	default:
+		wait := ratelimit.Backoff(cfg.Retry.Backoff.Duration, cfg.Retry.MaxBackoff.Duration, attempt)
+		lg.Warn("alert delivery failed, retrying", "attempt", attempt, "status", status, "error", err, "wait", wait)
+		next := time.Now().Add(wait).Unix()
+		if err := s.repo.Reschedule(ctx, event.EventID, attempt, next); err != nil {
+			lg.Error("failed to reschedule alert", "error", err)
+		}
+		return
+	}
+	if err := s.repo.Dequeue(ctx, event.EventID); err != nil {
+		lg.Error("failed to dequeue alert", "error", err)
+	}
+}
+
+func (s *alertService) post(ctx context.Context, cfg *config.Alerts, target, eventID string, attempt int, body []byte) (int, error) {
+	ctx, cancel := context.WithTimeout(ctx, cfg.Timeout.Duration)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return 0, err
+	}
+	now := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set(webhook.TimestampHeader, strconv.FormatInt(now, 10))
+	req.Header.Set(webhook.SignatureHeader, webhook.Sign(cfg.SigningSecret, now, body))
+	req.Header.Set(webhook.DeliveryHeader, eventID)
+	req.Header.Set(webhook.AttemptHeader, strconv.Itoa(attempt))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, err
+	}
+	defer resp.Body.Close()
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, 1<<16))
+	return resp.StatusCode, nil
+}
+
+func alertRuleRes(rule *entity.AlertRule) *dto.AlertRuleRes {
+	return &dto.AlertRuleRes{
+		ID:              rule.ID,
+		Symbol:          rule.Symbol,
+		Kind:            rule.Kind,
+		Threshold:       rule.Threshold,
+		WindowSeconds:   rule.Window,
+		WebhookURL:      rule.WebhookURL,
+		Active:          rule.Active,
+		CreatedAt:       rule.CreatedAt,
+		LastTriggeredAt: rule.LastTriggeredAt,
+	}
+}
@@ -0,0 +1,97 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/repository/repository/alert"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+type ruleRecorder struct {
+	alert.AlertRepository
+	rules    []*entity.AlertRule
+	failing  map[int64]bool // SetState and Fire fail for these rules
+	fired    []int64
+	recorded []int64
+}
+
+func (r *ruleRecorder) ListRules(ctx context.Context, symbols []string) ([]*entity.AlertRule, error) {
+	return r.rules, nil
+}
+
+func (r *ruleRecorder) SetState(ctx context.Context, id int64, active bool) error {
+	if r.failing[id] {
+		return errors.New("constraint violation")
+	}
+	r.recorded = append(r.recorded, id)
+	return nil
+}
+
+func (r *ruleRecorder) Fire(ctx context.Context, id int64, triggeredAt int64, event *entity.AlertOutboxEvent) error {
+	if r.failing[id] {
+		return errors.New("constraint violation")
+	}
+	r.fired = append(r.fired, id)
+	return nil
+}
+
+// referenceless fails every reference price lookup
+type referenceless struct{ price.PriceRepository }
+
+func (referenceless) GetPricesAt(ctx context.Context, symbol string, at []int64) ([]*dto.PricePoint, error) {
+	return nil, errors.New("connection reset")
+}
+
+func TestEvaluateContinuesPastFailingRules(t *testing.T) {
+	active, inactive := true, false
+	rule := func(id int64, kind string, threshold int64, state *bool) *entity.AlertRule {
+		return &entity.AlertRule{ID: id, Symbol: "btc", Kind: kind, Threshold: decimal.NewFromInt(threshold), Window: 3600, Active: state}
+	}
+	repo := &ruleRecorder{
+		rules: []*entity.AlertRule{
+			rule(1, entity.AlertChange, 5, &inactive),        // reference lookup fails
+			rule(2, entity.AlertCrossesAbove, 90, &inactive), // fires
+			rule(3, entity.AlertCrossesAbove, 90, &inactive), // fire fails
+			rule(4, entity.AlertCrossesBelow, 90, &active),   // records the state
+			rule(5, entity.AlertCrossesBelow, 90, nil),       // state update fails
+			rule(6, entity.AlertCrossesAbove, 90, &inactive), // fires after the failures
+		},
+		failing: map[int64]bool{3: true, 5: true},
+	}
+	s := NewAlertService(slog.New(slog.NewTextHandler(io.Discard, nil)), config.NewStore(config.Default()), repo, referenceless{})
+
+	err := s.Evaluate(context.Background(), []*entity.Price{{Symbol: "btc", Price: decimal.NewFromInt(100), Time: 1735689600}})
+	if err == nil {
+		t.Fatal("Evaluate() error = nil, want the failures of rules 1, 3 and 5")
+	}
+	var failed []string
+	for _, line := range strings.Split(err.Error(), "\n") {
+		id, _, _ := strings.Cut(line, ":")
+		failed = append(failed, id)
+	}
+	if want := []string{"rule 1", "rule 3", "rule 5"}; !slices.Equal(failed, want) {
+		t.Errorf("Evaluate() reported %v, want %v", failed, want)
+	}
+	if !slices.Equal(repo.fired, []int64{2, 6}) {
+		t.Errorf("fired rules = %v, want [2 6]", repo.fired)
+	}
+	if !slices.Equal(repo.recorded, []int64{4}) {
+		t.Errorf("recorded states = %v, want [4]", repo.recorded)
+	}
+
+	select {
+	case <-s.(*alertService).wake:
+	default:
+		t.Error("deliveries were not woken for the rules that fired")
+	}
+}
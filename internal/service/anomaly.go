@@ -0,0 +1,319 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"math"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/repository/repository/anomaly"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultAnomaliesLimit = 50
+	MaxAnomaliesLimit     = 500
+)
+
+//go:generate mockgen -source=anomaly.go -destination=../../mock/service/anomaly/anomaly.go
+type AnomalyService interface {
+	// Screen compares a freshly fetched batch with the recent prices of each
+	// symbol and returns the ticks to store and the anomalous ones
+	Screen(ctx context.Context, prices []*entity.Price) ([]*entity.Price, []*entity.Anomaly, error)
+	// Record lists anomalies returned by Screen for review; it is called once
+	// the accepted ticks are stored, so a failed insert leaves no flag behind
+	Record(ctx context.Context, anomalies []*entity.Anomaly) error
+	ListAnomalies(ctx context.Context, req *dto.AnomaliesReq) ([]*dto.AnomalyRes, error)
+	// Approve stores a quarantined tick; a flagged one is already stored
+	Approve(ctx context.Context, id int64) (*dto.AnomalyRes, error)
+	// Reject removes a flagged tick from the stored prices; a quarantined one never was
+	Reject(ctx context.Context, id int64) (*dto.AnomalyRes, error)
+}
+
+type anomalyService struct {
+	logger *slog.Logger
+	store  *config.Store
+	repo   anomaly.AnomalyRepository
+	prices price.PriceRepository
+}
+
+func NewAnomalyService(
+	logger *slog.Logger,
+	store *config.Store,
+	repo anomaly.AnomalyRepository,
+	prices price.PriceRepository,
+) AnomalyService {
+	return &anomalyService{
+		logger: logger.With("Layer", "AnomalyService"),
+		store:  store,
+		repo:   repo,
+		prices: prices,
+	}
+}
+
+func (s *anomalyService) Screen(ctx context.Context, prices []*entity.Price) ([]*entity.Price, []*entity.Anomaly, error) {
+	lg := s.logger.With("method", "Screen")
+
+	cfg := s.store.Current().Anomaly
+	if cfg.Action == config.AnomalyOff || len(prices) == 0 {
+		return prices, nil, nil
+	}
+	lookback := int64(cfg.Lookback.Duration / time.Second)
+
+	symbols := make([]string, 0, len(prices))
+	first, last := prices[0].Time, prices[0].Time
+	for _, p := range prices {
+		symbols = append(symbols, p.Symbol)
+		first, last = min(first, p.Time), max(last, p.Time)
+	}
+	ticks, err := s.prices.GetRecentTicks(ctx, symbols, first-lookback, last)
+	if err != nil {
+		lg.Error("failed to get recent prices", "error", err)
+		return nil, nil, err
+	}
+	history := make(map[string][]*entity.Price)
+	for _, t := range ticks {
+		history[t.Symbol] = append(history[t.Symbol], t)
+	}
+	held := make(map[string][]*entity.Price)
+	if cfg.Confirmations > 1 {
+		ticks, err := s.repo.Quarantined(ctx, symbols, first-lookback, last)
+		if err != nil {
+			lg.Error("failed to get quarantined ticks", "error", err)
+			return nil, nil, err
+		}
+		for _, t := range ticks {
+			held[t.Symbol] = append(held[t.Symbol], t)
+		}
+	}
+
+	now := time.Now().Unix()
+	accepted := make([]*entity.Price, 0, len(prices))
+	var anomalies []*entity.Anomaly
+	for _, p := range prices {
+		a := screen(&cfg, p, history[p.Symbol], held[p.Symbol])
+		if a == nil {
+			accepted = append(accepted, p)
+			continue
+		}
+		a.Action, a.Status, a.DetectedAt = cfg.Action, entity.AnomalyPending, now
+		anomalies = append(anomalies, a)
+		if cfg.Action == config.AnomalyFlag {
+			accepted = append(accepted, p)
+		}
+	}
+	return accepted, anomalies, nil
+}
+
+func (s *anomalyService) Record(ctx context.Context, anomalies []*entity.Anomaly) error {
+	lg := s.logger.With("method", "Record")
+
+	if len(anomalies) == 0 {
+		return nil
+	}
+	if err := s.repo.Save(ctx, anomalies); err != nil {
+		lg.Error("failed to save anomalies", "error", err)
+		return err
+	}
+	for _, a := range anomalies {
+		attrs := []any{"symbol", a.Tick.Symbol, "price", a.Tick.Price, "time", a.Tick.Time,
+			"reference", a.Reference, "change_pct", a.ChangePct, "action", a.Action}
+		if a.ZScore != nil {
+			attrs = append(attrs, "z_score", *a.ZScore)
+		}
+		lg.Warn("anomalous tick", attrs...)
+	}
+	return nil
+}
+
+// screen returns the anomaly p is, or nil when it is in line with the ticks of
+// its symbol within the lookback before it. A symbol without history passes.
+// held are the quarantined ticks of the symbol that are still pending.
+func screen(cfg *config.Anomaly, p *entity.Price, history, held []*entity.Price) *entity.Anomaly {
+	from := p.Time - int64(cfg.Lookback.Duration/time.Second)
+	var window []*entity.Price
+	for _, t := range history {
+		if t.Time >= from && t.Time < p.Time {
+			window = append(window, t)
+		}
+	}
+	if len(window) == 0 {
+		return nil
+	}
+	ref := window[len(window)-1]
+	if !ref.Price.IsPositive() {
+		return nil
+	}
+
+	// returns are log returns between consecutive ticks, so the z-score doesn't
+	// depend on the price level and a steady trend isn't flagged
+	var returns []float64
+	for i := 1; i < len(window); i++ {
+		prev, cur := window[i-1].Price.InexactFloat64(), window[i].Price.InexactFloat64()
+		if prev > 0 && cur > 0 {
+			returns = append(returns, math.Log(cur/prev))
+		}
+	}
+	a := compare(cfg, p, ref.Price, returns)
+	if a == nil || confirmed(cfg, p, ref.Time, held, returns) {
+		return nil
+	}
+	return a
+}
+
+// compare returns the anomaly p is against ref and the recent returns, or nil
+func compare(cfg *config.Anomaly, p *entity.Price, ref decimal.Decimal, returns []float64) *entity.Anomaly {
+	a := &entity.Anomaly{Tick: *p, Reference: ref, ChangePct: *pct(p.Price.Sub(ref), ref)}
+	anomalous := cfg.MaxChangePct > 0 && a.ChangePct.Abs().InexactFloat64() > cfg.MaxChangePct
+
+	if len(returns) >= cfg.MinSamples && p.Price.IsPositive() {
+		var mean float64
+		for _, r := range returns {
+			mean += r
+		}
+		mean /= float64(len(returns))
+		if sd := stddev(returns); sd > 0 {
+			z := (math.Log(p.Price.InexactFloat64()/ref.InexactFloat64()) - mean) / sd
+			a.ZScore = &z
+			anomalous = anomalous || (cfg.ZScore > 0 && math.Abs(z) > cfg.ZScore)
+		}
+	}
+	if !anomalous {
+		return nil
+	}
+	return a
+}
+
+// confirmed reports whether p settles a new level: quarantined ticks are never
+// stored, so a genuine move keeps failing against the last stored price. It is
+// accepted once the ticks held back since that price, together with p, make
+// Confirmations consecutive ticks that agree with each other.
+func confirmed(cfg *config.Anomaly, p *entity.Price, since int64, held []*entity.Price, returns []float64) bool {
+	n := cfg.Confirmations - 1
+	if n < 1 {
+		return false
+	}
+	var recent []*entity.Price
+	for _, t := range held {
+		if t.Time > since && t.Time < p.Time {
+			recent = append(recent, t)
+		}
+	}
+	if len(recent) < n {
+		return false
+	}
+	for _, t := range recent[len(recent)-n:] {
+		if !t.Price.IsPositive() || compare(cfg, p, t.Price, returns) != nil {
+			return false
+		}
+	}
+	return true
+}
+
+func (s *anomalyService) ListAnomalies(ctx context.Context, req *dto.AnomaliesReq) ([]*dto.AnomalyRes, error) {
+	lg := s.logger.With("method", "ListAnomalies")
+
+	status := req.Status
+	switch status {
+	case "":
+		status = entity.AnomalyPending
+	case "all":
+		status = ""
+	case entity.AnomalyPending, entity.AnomalyApproved, entity.AnomalyRejected:
+	default:
+		return nil, fmt.Errorf("%w: status %q must be pending, approved, rejected or all", ErrInvalidRequest, req.Status)
+	}
+	limit := req.Limit
+	if limit == 0 {
+		limit = DefaultAnomaliesLimit
+	}
+	if limit < 0 || limit > MaxAnomaliesLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRequest, MaxAnomaliesLimit)
+	}
+
+	anomalies, err := s.repo.List(ctx, status, strings.ToLower(strings.TrimSpace(req.Symbol)), limit)
+	if err != nil {
+		lg.Error("failed to list anomalies", "error", err)
+		return nil, err
+	}
+	res := make([]*dto.AnomalyRes, len(anomalies))
+	for i, a := range anomalies {
+		res[i] = anomalyRes(a)
+	}
+	return res, nil
+}
+
+func (s *anomalyService) Approve(ctx context.Context, id int64) (*dto.AnomalyRes, error) {
+	return s.review(ctx, "Approve", id, entity.AnomalyApproved, func(a *entity.Anomaly) error {
+		if a.Action != config.AnomalyQuarantine {
+			return nil
+		}
+		_, err := s.prices.InsertIgnore(ctx, []*entity.Price{&a.Tick})
+		return err
+	})
+}
+
+func (s *anomalyService) Reject(ctx context.Context, id int64) (*dto.AnomalyRes, error) {
+	return s.review(ctx, "Reject", id, entity.AnomalyRejected, func(a *entity.Anomaly) error {
+		if a.Action != config.AnomalyFlag {
+			return nil
+		}
+		return s.prices.DeleteTick(ctx, &a.Tick)
+	})
+}
+
+// review applies a decision to the stored prices, then records it. Applying
+// it is idempotent, so a failure in between can be fixed by reviewing again.
+func (s *anomalyService) review(ctx context.Context, method string, id int64, status string, apply func(*entity.Anomaly) error) (*dto.AnomalyRes, error) {
+	lg := s.logger.With("method", method)
+
+	a, err := s.repo.Get(ctx, id)
+	if err != nil {
+		if !errors.Is(err, anomaly.ErrAnomalyNotFound) {
+			lg.Error("failed to get anomaly", "id", id, "error", err)
+		}
+		return nil, err
+	}
+	if a.Status != entity.AnomalyPending {
+		return nil, anomaly.ErrAlreadyReviewed
+	}
+	if err := apply(a); err != nil {
+		lg.Error("failed to apply review", "id", id, "error", err)
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	if err := s.repo.Review(ctx, id, status, now); err != nil {
+		if !errors.Is(err, anomaly.ErrAlreadyReviewed) {
+			lg.Error("failed to review anomaly", "id", id, "error", err)
+		}
+		return nil, err
+	}
+	a.Status, a.ReviewedAt = status, &now
+
+	lg.Info("reviewed anomaly", "id", id, "symbol", a.Tick.Symbol, "time", a.Tick.Time, "status", status)
+	return anomalyRes(a), nil
+}
+
+func anomalyRes(a *entity.Anomaly) *dto.AnomalyRes {
+	return &dto.AnomalyRes{
+		ID:         a.ID,
+		Symbol:     a.Tick.Symbol,
+		Price:      a.Tick.Price,
+		Time:       a.Tick.Time,
+		Action:     a.Action,
+		Reference:  a.Reference,
+		ChangePct:  a.ChangePct,
+		ZScore:     a.ZScore,
+		Status:     a.Status,
+		DetectedAt: a.DetectedAt,
+		ReviewedAt: a.ReviewedAt,
+	}
+}
@@ -0,0 +1,120 @@
+package service
+
+import (
+	"testing"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/shopspring/decimal"
+)
+
+func TestScreen(t *testing.T) {
+	const now = 1735689600
+	tick := func(ago time.Duration, p string) *entity.Price {
+		return &entity.Price{Symbol: "btc", Price: decimal.RequireFromString(p), Time: now - int64(ago/time.Second)}
+	}
+	ticks := func(ts ...*entity.Price) []*entity.Price { return ts }
+	pctOnly := config.Anomaly{
+		Action:        config.AnomalyQuarantine,
+		Lookback:      config.Duration{Duration: time.Hour},
+		MaxChangePct:  25,
+		MinSamples:    20,
+		Confirmations: 3,
+	}
+	withConfirmations := func(n int) config.Anomaly {
+		cfg := pctOnly
+		cfg.Confirmations = n
+		return cfg
+	}
+	zOnly := config.Anomaly{
+		Action:     config.AnomalyFlag,
+		Lookback:   config.Duration{Duration: time.Hour},
+		ZScore:     3,
+		MinSamples: 4,
+	}
+	// alternating returns of about ±1%
+	wiggle := ticks(tick(6*time.Minute, "100"), tick(5*time.Minute, "101"), tick(4*time.Minute, "100"),
+		tick(3*time.Minute, "101"), tick(2*time.Minute, "100"), tick(time.Minute, "101"))
+
+	tests := []struct {
+		name      string
+		cfg       config.Anomaly
+		p         *entity.Price
+		history   []*entity.Price
+		held      []*entity.Price
+		reference string // empty when p passes
+		zScore    bool
+	}{
+		{name: "no history", cfg: pctOnly, p: tick(0, "200")},
+		{name: "history outside the lookback", cfg: pctOnly, p: tick(0, "200"),
+			history: ticks(tick(2*time.Hour, "100"))},
+		{name: "within the change", cfg: pctOnly, p: tick(0, "125"),
+			history: ticks(tick(time.Minute, "100"))},
+		{name: "above the change", cfg: pctOnly, p: tick(0, "126"),
+			history: ticks(tick(time.Minute, "100")), reference: "100"},
+		{name: "drop", cfg: pctOnly, p: tick(0, "70"),
+			history: ticks(tick(time.Minute, "100")), reference: "100"},
+		{name: "compared with the last tick before it", cfg: pctOnly, p: tick(0, "160"),
+			history: ticks(tick(2*time.Minute, "100"), tick(time.Minute, "120"), tick(-time.Minute, "10")), reference: "120"},
+		{name: "reference without a price", cfg: pctOnly, p: tick(0, "130"),
+			history: ticks(tick(time.Minute, "0"))},
+
+		{name: "one held tick agrees", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(time.Minute, "151")), reference: "100"},
+		{name: "two held ticks agree", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(2*time.Minute, "149"), tick(time.Minute, "151"))},
+		{name: "only the latest held ticks count", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(4*time.Minute, "100")),
+			held:    ticks(tick(3*time.Minute, "10"), tick(2*time.Minute, "149"), tick(time.Minute, "151"))},
+		{name: "a held tick disagrees", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(2*time.Minute, "400"), tick(time.Minute, "151")), reference: "100"},
+		{name: "held before the reference", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(time.Minute, "100")),
+			held:    ticks(tick(3*time.Minute, "149"), tick(2*time.Minute, "151")), reference: "100"},
+		{name: "held after it", cfg: pctOnly, p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(2*time.Minute, "149"), tick(-time.Minute, "151")), reference: "100"},
+		{name: "confirmations disabled", cfg: withConfirmations(0), p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(2*time.Minute, "149"), tick(time.Minute, "151")), reference: "100"},
+		{name: "two confirmations", cfg: withConfirmations(2), p: tick(0, "150"),
+			history: ticks(tick(3*time.Minute, "100")),
+			held:    ticks(tick(time.Minute, "151"))},
+
+		{name: "z-score in line", cfg: zOnly, p: tick(0, "100"), history: wiggle},
+		{name: "z-score outlier", cfg: zOnly, p: tick(0, "110"), history: wiggle, reference: "101", zScore: true},
+		{name: "too few samples for a z-score", cfg: zOnly, p: tick(0, "110"), history: wiggle[3:]},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := screen(&tt.cfg, tt.p, tt.history, tt.held)
+			if tt.reference == "" {
+				if a != nil {
+					t.Fatalf("screen() = anomaly against %s, want none", a.Reference)
+				}
+				return
+			}
+			if a == nil {
+				t.Fatalf("screen() = nil, want an anomaly against %s", tt.reference)
+			}
+			ref := decimal.RequireFromString(tt.reference)
+			if !a.Reference.Equal(ref) {
+				t.Errorf("reference = %s, want %s", a.Reference, ref)
+			}
+			change := tt.p.Price.Sub(ref).Mul(hundred).DivRound(ref, ratioPrecision)
+			if !a.ChangePct.Equal(change) {
+				t.Errorf("change = %s, want %s", a.ChangePct, change)
+			}
+			if (a.ZScore != nil) != tt.zScore {
+				t.Errorf("z-score = %v, want one: %v", a.ZScore, tt.zScore)
+			}
+			if a.Tick != *tt.p {
+				t.Errorf("tick = %+v, want %+v", a.Tick, *tt.p)
+			}
+		})
+	}
+}
@@ -0,0 +1,118 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+// MaxAsOfLookups bounds symbols times timestamps in one as-of request
+const MaxAsOfLookups = 100
+
+const (
+	AsOfOK      = "ok"
+	AsOfStale   = "stale"
+	AsOfMissing = "missing"
+)
+
+// asOfLayouts are accepted for timestamps besides unix seconds
+var asOfLayouts = []string{time.RFC3339, "2006-01-02T15:04Z07:00", "2006-01-02"}
+
+// GetAsOf returns the price of every symbol in effect at every requested
+// time. Prices recorded more than the maximum staleness earlier are reported
+// as stale without a price, so a gap in the data isn't mistaken for a quote.
+func (s *priceService) GetAsOf(ctx context.Context, req *dto.AsOfReq) ([]*dto.AsOfRes, error) {
+	lg := s.logger.With("method", "GetAsOf")
+
+	symbols := splitList(strings.ToLower(req.Symbols))
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("%w: symbols is required", ErrInvalidRequest)
+	}
+	at, err := parseTimes(req.At)
+	if err != nil {
+		return nil, err
+	}
+	if len(symbols)*len(at) > MaxAsOfLookups {
+		return nil, fmt.Errorf("%w: at most %d symbol and time combinations", ErrInvalidRequest, MaxAsOfLookups)
+	}
+	maxAge, err := s.maxStaleness(req.MaxStaleness)
+	if err != nil {
+		return nil, err
+	}
+
+	res := make([]*dto.AsOfRes, 0, len(symbols)*len(at))
+	for _, symbol := range symbols {
+		points, err := s.repo.GetPricesAt(ctx, symbol, at)
+		if err != nil {
+			lg.Error("failed to get prices", "symbol", symbol, "error", err)
+			return nil, err
+		}
+		for i, point := range points {
+			r := &dto.AsOfRes{Symbol: symbol, At: at[i], Status: AsOfMissing}
+			if point != nil {
+				age := at[i] - point.Timestamp
+				r.Timestamp, r.AgeSeconds = &point.Timestamp, &age
+				r.Status = AsOfStale
+				if age <= maxAge {
+					r.Status, r.Price = AsOfOK, &point.Price
+				}
+			}
+			res = append(res, r)
+		}
+	}
+
+	lg.Info("fetched as-of prices", "symbols", len(symbols), "times", len(at))
+	return res, nil
+}
+
+// maxStaleness is the requested maximum price age in seconds, or the configured one
+func (s *priceService) maxStaleness(override string) (int64, error) {
+	d := s.store.Current().Query.MaxStaleness.Duration
+	if override != "" {
+		var err error
+		if d, err = price.ParseInterval(override); err != nil {
+			return 0, fmt.Errorf("%w: max_staleness %q, expected a duration such as 15m or 2d", ErrInvalidRequest, override)
+		}
+	}
+	return int64(d / time.Second), nil
+}
+
+// parseTimes parses comma separated unix seconds or dates, defaulting to now
+func parseTimes(spec string) ([]int64, error) {
+	parts := splitList(spec)
+	if len(parts) == 0 {
+		return []int64{time.Now().Unix()}, nil
+	}
+
+	out := make([]int64, 0, len(parts))
+next:
+	for _, p := range parts {
+		if n, err := strconv.ParseInt(p, 10, 64); err == nil {
+			out = append(out, n)
+			continue
+		}
+		for _, layout := range asOfLayouts {
+			if t, err := time.Parse(layout, p); err == nil {
+				out = append(out, t.Unix())
+				continue next
+			}
+		}
+		return nil, fmt.Errorf("%w: time %q, expected unix seconds or RFC 3339", ErrInvalidRequest, p)
+	}
+	return out, nil
+}
+
+func splitList(s string) []string {
+	var out []string
+	for _, p := range strings.Split(s, ",") {
+		if p = strings.TrimSpace(p); p != "" {
+			out = append(out, p)
+		}
+	}
+	return out
+}
@@ -0,0 +1,57 @@
+package service
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func TestParseTimes(t *testing.T) {
+	tests := []struct {
+		spec string
+		want []int64 // nil when the spec is invalid
+	}{
+		{"1735689600", []int64{1735689600}},
+		{"0,-60", []int64{0, -60}},
+		{" 1735689600 , ,1735693200 ", []int64{1735689600, 1735693200}},
+		{"2025-01-01", []int64{1735689600}},
+		{"2025-01-01T01:00:00Z", []int64{1735693200}},
+		{"2025-01-01T03:30:00+02:30", []int64{1735693200}},
+		{"2025-01-01T01:00Z", []int64{1735693200}},
+		{"2025-01-01,1735693200", []int64{1735689600, 1735693200}},
+		{"yesterday", nil},
+		{"1735689600,2025-13-01", nil},
+		{"1.5", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			got, err := parseTimes(tt.spec)
+			if tt.want == nil {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Fatalf("parseTimes(%q) error = %v, want ErrInvalidRequest", tt.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseTimes(%q) error = %v", tt.spec, err)
+			}
+			if !slices.Equal(got, tt.want) {
+				t.Errorf("parseTimes(%q) = %v, want %v", tt.spec, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseTimesDefaultsToNow(t *testing.T) {
+	for _, spec := range []string{"", " , "} {
+		before := time.Now().Unix()
+		got, err := parseTimes(spec)
+		if err != nil {
+			t.Fatalf("parseTimes(%q) error = %v", spec, err)
+		}
+		if len(got) != 1 || got[0] < before || got[0] > time.Now().Unix() {
+			t.Errorf("parseTimes(%q) = %v, want now", spec, got)
+		}
+	}
+}
@@ -0,0 +1,42 @@
+package service
+
+import (
+	"context"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+)
+
+// GetTWAP returns the time-weighted average price over [req.From, req.To]
+func (s *priceService) GetTWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error) {
+	lg := s.logger.With("method", "GetTWAP")
+
+	if err := validateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+	res, err := s.repo.GetTWAP(ctx, req)
+	if err != nil {
+		lg.Error("failed to compute twap", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	lg.Info("computed twap", "symbol", req.Symbol, "ticks", res.Ticks)
+	return res, nil
+}
+
+// GetVWAP returns the price over [req.From, req.To] weighted by time held and
+// the reported 24h volume, a volume-proxy rather than a true VWAP
+func (s *priceService) GetVWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error) {
+	lg := s.logger.With("method", "GetVWAP")
+
+	if err := validateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+	res, err := s.repo.GetVWAP(ctx, req)
+	if err != nil {
+		lg.Error("failed to compute vwap", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	lg.Info("computed vwap", "symbol", req.Symbol, "ticks", res.Ticks)
+	return res, nil
+}
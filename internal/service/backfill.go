@@ -0,0 +1,55 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/providers/currency"
+)
+
+// BackfillChunk keeps each provider request within its finest granularity
+const BackfillChunk = 24 * time.Hour
+
+// Backfill loads past prices for symbol from the provider, one chunk at a
+// time, skipping rows that are already stored. It returns the number of new rows.
+func (s *priceService) Backfill(ctx context.Context, symbol string, from, to int64) (int64, error) {
+	lg := s.logger.With("method", "Backfill", "symbol", symbol)
+
+	history, ok := s.currencyProvider.(currency.HistoryProvider)
+	if !ok {
+		return 0, currency.ErrHistoryNotSupported
+	}
+	if from >= to {
+		return 0, fmt.Errorf("%w: from must be before to", ErrInvalidRequest)
+	}
+	baskets, err := s.catalog.BasketNames(ctx)
+	if err != nil {
+		return 0, err
+	}
+	if baskets[symbol] {
+		return 0, fmt.Errorf("%w: %s is a basket's name", ErrInvalidRequest, symbol)
+	}
+
+	var inserted int64
+	chunk := int64(BackfillChunk / time.Second)
+	for start := from; start < to; start += chunk {
+		end := min(start+chunk, to)
+
+		prices, err := history.GetRange(ctx, symbol, start, end)
+		if err != nil {
+			return inserted, fetchErr(lg, err)
+		}
+
+		n, err := s.repo.InsertIgnore(ctx, prices)
+		if err != nil {
+			lg.Error("failed to insert backfilled prices", "error", err)
+			return inserted, fmt.Errorf("%w: %s", ErrFailedToInsertBatchPrice, err)
+		}
+		inserted += n
+		lg.Info("backfilled chunk", "from", start, "to", end, "fetched", len(prices), "inserted", n)
+	}
+
+	lg.Info("backfill finished", "inserted", inserted)
+	return inserted, nil
+}
@@ -0,0 +1,289 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/repository/repository/basket"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	MaxBasketComponents = 50
+	DefaultBasketValue  = 100
+)
+
+// basketName fits the symbol column of coin_prices
+var basketName = regexp.MustCompile(`^[a-z0-9][a-z0-9-]{0,15}$`)
+
+//go:generate mockgen -source=basket.go -destination=../../mock/service/basket/basket.go
+type BasketService interface {
+	SaveBasket(ctx context.Context, name string, req *dto.BasketReq) (*dto.BasketRes, error)
+	GetBasket(ctx context.Context, name string) (*dto.BasketRes, error)
+	ListBaskets(ctx context.Context) ([]*dto.BasketRes, error)
+	DeleteBasket(ctx context.Context, name string) error
+	// ValueBaskets stores and returns the value of every basket at the prices of a freshly inserted batch
+	ValueBaskets(ctx context.Context, prices []*entity.Price) ([]*entity.Price, error)
+}
+
+type basketService struct {
+	logger *slog.Logger
+	store  *config.Store
+	repo   basket.BasketRepository
+	prices price.PriceRepository
+}
+
+func NewBasketService(
+	logger *slog.Logger,
+	store *config.Store,
+	repo basket.BasketRepository,
+	prices price.PriceRepository,
+) BasketService {
+	return &basketService{
+		logger: logger.With("Layer", "BasketService"),
+		store:  store,
+		repo:   repo,
+		prices: prices,
+	}
+}
+
+// SaveBasket creates or rebalances a basket at the latest prices of its
+// components, and stores its value right away so it can be queried like a symbol
+func (s *basketService) SaveBasket(ctx context.Context, name string, req *dto.BasketReq) (*dto.BasketRes, error) {
+	lg := s.logger.With("method", "SaveBasket")
+
+	name = strings.ToLower(strings.TrimSpace(name))
+	if !basketName.MatchString(name) {
+		return nil, fmt.Errorf("%w: basket name %q must be up to 16 lowercase letters, digits and dashes", ErrInvalidRequest, name)
+	}
+	components, err := s.components(ctx, name, req.Components)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now().Unix()
+	maxAge := int64(s.store.Current().Query.MaxStaleness.Duration / time.Second)
+	latest := func(symbol string) (*dto.PricePoint, error) {
+		points, err := s.prices.GetPricesAt(ctx, symbol, []int64{now})
+		if err != nil {
+			return nil, err
+		}
+		if len(points) == 0 || points[0] == nil || now-points[0].Timestamp > maxAge {
+			return nil, nil
+		}
+		return points[0], nil
+	}
+
+	b := &entity.Basket{Name: name, CreatedAt: now, UpdatedAt: now, BaseValue: decimal.NewFromInt(DefaultBasketValue)}
+	existing, err := s.repo.Get(ctx, name)
+	switch {
+	case err == nil:
+		// rebalance at the current value so the stored series doesn't jump
+		b.CreatedAt = existing.CreatedAt
+		current, err := latest(name)
+		if err != nil {
+			lg.Error("failed to get basket value", "basket", name, "error", err)
+			return nil, err
+		}
+		if current == nil {
+			return nil, fmt.Errorf("%w: basket %s has no value within %ds to rebalance at", ErrStalePrice, name, maxAge)
+		}
+		b.BaseValue = current.Price
+	case errors.Is(err, basket.ErrBasketNotFound):
+		// the repository reserves the name, or refuses it when another symbol holds it
+		if req.BaseValue.Valid {
+			if !req.BaseValue.Decimal.IsPositive() {
+				return nil, fmt.Errorf("%w: base_value must be positive", ErrInvalidRequest)
+			}
+			b.BaseValue = req.BaseValue.Decimal
+		}
+	default:
+		lg.Error("failed to get basket", "basket", name, "error", err)
+		return nil, err
+	}
+
+	for _, c := range components {
+		p, err := latest(c.Symbol)
+		if err != nil {
+			lg.Error("failed to get component price", "symbol", c.Symbol, "error", err)
+			return nil, err
+		}
+		if p == nil || !p.Price.IsPositive() {
+			return nil, fmt.Errorf("%w: %s has no price within %ds", ErrStalePrice, c.Symbol, maxAge)
+		}
+		c.Units = b.BaseValue.Mul(c.Weight).DivRound(p.Price, ratePrecision)
+		b.Components = append(b.Components, c)
+	}
+
+	if err := s.repo.Save(ctx, b); err != nil {
+		if errors.Is(err, basket.ErrNameTaken) {
+			return nil, fmt.Errorf("%w: %q", err, name)
+		}
+		lg.Error("failed to save basket", "basket", name, "error", err)
+		return nil, err
+	}
+	if _, err := s.prices.InsertIgnore(ctx, []*entity.Price{{Symbol: name, Price: b.BaseValue, Time: now}}); err != nil {
+		lg.Error("failed to store basket value", "basket", name, "error", err)
+		return nil, err
+	}
+
+	lg.Info("saved basket", "basket", name, "components", len(b.Components), "value", b.BaseValue)
+	return basketRes(b), nil
+}
+
+// components validates and normalizes the requested components, weights adding up to 1
+func (s *basketService) components(ctx context.Context, name string, in []dto.BasketComponentReq) ([]entity.BasketComponent, error) {
+	if len(in) == 0 || len(in) > MaxBasketComponents {
+		return nil, fmt.Errorf("%w: between 1 and %d components", ErrInvalidRequest, MaxBasketComponents)
+	}
+	baskets, err := s.repo.List(ctx)
+	if err != nil {
+		return nil, err
+	}
+	isBasket := make(map[string]bool, len(baskets))
+	for _, b := range baskets {
+		isBasket[b.Name] = true
+	}
+
+	total := decimal.Zero
+	seen := make(map[string]bool, len(in))
+	out := make([]entity.BasketComponent, 0, len(in))
+	for _, c := range in {
+		symbol := strings.ToLower(strings.TrimSpace(c.Symbol))
+		switch {
+		case symbol == "":
+			return nil, fmt.Errorf("%w: component without a symbol", ErrInvalidRequest)
+		case symbol == name || isBasket[symbol]:
+			return nil, fmt.Errorf("%w: %s is a basket, baskets can't contain baskets", ErrInvalidRequest, symbol)
+		case seen[symbol]:
+			return nil, fmt.Errorf("%w: %s is listed twice", ErrInvalidRequest, symbol)
+		case !c.Weight.IsPositive():
+			return nil, fmt.Errorf("%w: weight of %s must be positive", ErrInvalidRequest, symbol)
+		}
+		seen[symbol] = true
+		total = total.Add(c.Weight)
+		out = append(out, entity.BasketComponent{Symbol: symbol, Weight: c.Weight})
+	}
+	for i := range out {
+		out[i].Weight = out[i].Weight.DivRound(total, ratioPrecision)
+	}
+	return out, nil
+}
+
+func (s *basketService) GetBasket(ctx context.Context, name string) (*dto.BasketRes, error) {
+	lg := s.logger.With("method", "GetBasket")
+
+	b, err := s.repo.Get(ctx, strings.ToLower(name))
+	if err != nil {
+		if !errors.Is(err, basket.ErrBasketNotFound) {
+			lg.Error("failed to get basket", "basket", name, "error", err)
+		}
+		return nil, err
+	}
+	return basketRes(b), nil
+}
+
+func (s *basketService) ListBaskets(ctx context.Context) ([]*dto.BasketRes, error) {
+	lg := s.logger.With("method", "ListBaskets")
+
+	baskets, err := s.repo.List(ctx)
+	if err != nil {
+		lg.Error("failed to list baskets", "error", err)
+		return nil, err
+	}
+	res := make([]*dto.BasketRes, len(baskets))
+	for i, b := range baskets {
+		res[i] = basketRes(b)
+	}
+	return res, nil
+}
+
+// DeleteBasket stops valuing the basket; its stored prices and its name are kept
+func (s *basketService) DeleteBasket(ctx context.Context, name string) error {
+	lg := s.logger.With("method", "DeleteBasket")
+
+	if err := s.repo.Delete(ctx, strings.ToLower(name)); err != nil {
+		if !errors.Is(err, basket.ErrBasketNotFound) {
+			lg.Error("failed to delete basket", "basket", name, "error", err)
+		}
+		return err
+	}
+	lg.Info("deleted basket", "basket", name)
+	return nil
+}
+
+func (s *basketService) ValueBaskets(ctx context.Context, prices []*entity.Price) ([]*entity.Price, error) {
+	lg := s.logger.With("method", "ValueBaskets")
+
+	baskets, err := s.repo.List(ctx)
+	if err != nil || len(baskets) == 0 {
+		return nil, err
+	}
+
+	var at int64
+	known := make(map[string]decimal.Decimal, len(prices))
+	for _, p := range prices {
+		known[p.Symbol] = p.Price
+		at = max(at, p.Time)
+	}
+
+	// components not in the batch fall back to their last stored price, if recent enough
+	maxAge := int64(s.store.Current().Query.MaxStaleness.Duration / time.Second)
+	missing := make(map[string]bool)
+	for _, b := range baskets {
+		for _, c := range b.Components {
+			if _, ok := known[c.Symbol]; ok || missing[c.Symbol] {
+				continue
+			}
+			points, err := s.prices.GetPricesAt(ctx, c.Symbol, []int64{at})
+			if err != nil {
+				return nil, err
+			}
+			if len(points) == 0 || points[0] == nil || at-points[0].Timestamp > maxAge {
+				missing[c.Symbol] = true
+				continue
+			}
+			known[c.Symbol] = points[0].Price
+		}
+	}
+
+	var values []*entity.Price
+	for _, b := range baskets {
+		value, ok := b.Value(known)
+		if !ok {
+			lg.Warn("skipped basket with unpriced components", "basket", b.Name)
+			continue
+		}
+		// units carry ratePrecision places, the value doesn't need them
+		values = append(values, &entity.Price{Symbol: b.Name, Price: value.Round(ratioPrecision), Time: at})
+	}
+	if _, err := s.prices.InsertIgnore(ctx, values); err != nil {
+		return nil, err
+	}
+
+	lg.Info("valued baskets", "count", len(values))
+	return values, nil
+}
+
+func basketRes(b *entity.Basket) *dto.BasketRes {
+	res := &dto.BasketRes{
+		Name:       b.Name,
+		BaseValue:  b.BaseValue,
+		CreatedAt:  b.CreatedAt,
+		UpdatedAt:  b.UpdatedAt,
+		Components: make([]dto.BasketComponentRes, len(b.Components)),
+	}
+	for i, c := range b.Components {
+		res.Components[i] = dto.BasketComponentRes{Symbol: c.Symbol, Weight: c.Weight, Units: c.Units}
+	}
+	return res
+}
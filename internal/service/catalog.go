@@ -0,0 +1,80 @@
+package service
+
+import (
+	"context"
+	"log/slog"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/catalog"
+)
+
+//go:generate mockgen -source=catalog.go -destination=../../mock/service/catalog/catalog.go
+type CatalogService interface {
+	// Track records the symbols of an ingestion tick in the catalog; symbols
+	// neither in the tick nor in tracked stop ingesting
+	Track(ctx context.Context, prices []*entity.Price, tracked []string) error
+	ListSymbols(ctx context.Context, req *dto.SymbolsReq) ([]*dto.SymbolRes, error)
+	// BasketNames returns the symbols reserved by baskets, which coins must not be stored under
+	BasketNames(ctx context.Context) (map[string]bool, error)
+}
+
+type catalogService struct {
+	logger *slog.Logger
+	repo   catalog.CatalogRepository
+}
+
+func NewCatalogService(logger *slog.Logger, repo catalog.CatalogRepository) CatalogService {
+	return &catalogService{
+		logger: logger.With("Layer", "CatalogService"),
+		repo:   repo,
+	}
+}
+
+func (s *catalogService) Track(ctx context.Context, prices []*entity.Price, tracked []string) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	return s.repo.Track(ctx, prices, tracked, time.Now().Unix())
+}
+
+func (s *catalogService) ListSymbols(ctx context.Context, req *dto.SymbolsReq) ([]*dto.SymbolRes, error) {
+	lg := s.logger.With("method", "ListSymbols")
+
+	entries, err := s.repo.List(ctx)
+	if err != nil {
+		lg.Error("failed to list symbols", "error", err)
+		return nil, err
+	}
+	res := make([]*dto.SymbolRes, 0, len(entries))
+	for _, e := range entries {
+		if req.Ingesting != nil && e.Ingesting != *req.Ingesting {
+			continue
+		}
+		res = append(res, &dto.SymbolRes{
+			Symbol:         e.Symbol,
+			Name:           e.Name,
+			ProviderIDs:    e.ProviderIDs,
+			FirstTime:      e.FirstTime,
+			LastTime:       e.LastTime,
+			Rows:           e.Rows,
+			Basket:         e.Basket,
+			Ingesting:      e.Ingesting,
+			LastIngestedAt: e.LastIngestedAt,
+		})
+	}
+	return res, nil
+}
+
+func (s *catalogService) BasketNames(ctx context.Context) (map[string]bool, error) {
+	names, err := s.repo.BasketNames(ctx)
+	if err != nil {
+		return nil, err
+	}
+	reserved := make(map[string]bool, len(names))
+	for _, name := range names {
+		reserved[name] = true
+	}
+	return reserved, nil
+}
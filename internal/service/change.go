@@ -0,0 +1,66 @@
+package service
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	DefaultChangeWindows = "1h,24h,7d,30d,ytd"
+	MaxChangeWindows     = 10
+
+	// WindowYTD starts at midnight UTC on the first of January
+	WindowYTD = "ytd"
+)
+
+var hundred = decimal.NewFromInt(100)
+
+// parseWindows parses a comma separated list of intervals such as "1h,7d,ytd"
+func parseWindows(spec string) ([]string, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultChangeWindows
+	}
+	parts := strings.Split(spec, ",")
+	if len(parts) > MaxChangeWindows {
+		return nil, fmt.Errorf("%w: at most %d windows", ErrInvalidRequest, MaxChangeWindows)
+	}
+
+	windows := make([]string, 0, len(parts))
+	for _, w := range parts {
+		w = strings.ToLower(strings.TrimSpace(w))
+		if w != WindowYTD {
+			if _, err := price.ParseInterval(w); err != nil {
+				return nil, fmt.Errorf("%w: window %q, expected an interval such as 1h or 7d, or ytd", ErrInvalidRequest, w)
+			}
+		}
+		windows = append(windows, w)
+	}
+	return windows, nil
+}
+
+// windowStart is when the window ending at latest started
+func windowStart(window string, latest int64) int64 {
+	if window == WindowYTD {
+		t := time.Unix(latest, 0).UTC()
+		return time.Date(t.Year(), time.January, 1, 0, 0, 0, 0, time.UTC).Unix()
+	}
+	d, _ := price.ParseInterval(window)
+	return latest - int64(d/time.Second)
+}
+
+func change(latest decimal.Decimal, reference *dto.PricePoint) *dto.ChangeRes {
+	if reference == nil || reference.Price.IsZero() {
+		return nil
+	}
+	diff := latest.Sub(reference.Price)
+	return &dto.ChangeRes{
+		Reference: *reference,
+		Change:    diff,
+		ChangePct: diff.Mul(hundred).DivRound(reference.Price, ratioPrecision),
+	}
+}
@@ -0,0 +1,140 @@
+package service
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+// ratePrecision is the number of decimal places kept for conversion rates,
+// enough for pairs of coins many orders of magnitude apart
+const ratePrecision = 36
+
+const (
+	ConvertIdentity = "identity"
+	ConvertDirect   = "direct"
+	ConvertInverse  = "inverse"
+	ConvertCross    = "cross"
+)
+
+// Convert prices req.Amount of req.From in req.To. It prefers a stored pair
+// (from-to, then to-from inverted) and otherwise crosses the USD prices of both.
+// A pair priced longer than the maximum staleness ago is passed over, and a
+// conversion left with only stale prices fails with ErrStalePrice.
+func (s *priceService) Convert(ctx context.Context, req *dto.ConvertReq) (*dto.ConvertRes, error) {
+	lg := s.logger.With("method", "Convert")
+
+	from, to := strings.ToLower(strings.TrimSpace(req.From)), strings.ToLower(strings.TrimSpace(req.To))
+	amount := decimal.NewFromInt(1)
+	if req.Amount != "" {
+		var err error
+		if amount, err = decimal.NewFromString(req.Amount); err != nil || amount.IsNegative() {
+			return nil, fmt.Errorf("%w: amount %q must be a non-negative number", ErrInvalidRequest, req.Amount)
+		}
+	}
+	maxAge, err := s.maxStaleness(req.MaxStaleness)
+	if err != nil {
+		return nil, err
+	}
+	at := req.At
+	if at == 0 {
+		at = time.Now().Unix()
+	}
+
+	res := &dto.ConvertRes{From: from, To: to, Amount: amount, At: at, Legs: []*dto.ConvertLeg{}}
+	if from == to {
+		res.Method, res.Rate, res.Result = ConvertIdentity, decimal.NewFromInt(1), amount
+		return res, nil
+	}
+
+	// stale is the error of the first pair passed over for being stale
+	var stale error
+	leg, err := s.priceAt(ctx, entity.PairSymbol(from, to), at, maxAge)
+	if errors.Is(err, ErrStalePrice) {
+		stale, err = err, nil
+	}
+	if err != nil {
+		lg.Error("failed to look up direct pair", "from", from, "to", to, "error", err)
+		return nil, err
+	}
+	if leg != nil {
+		res.Method, res.Legs, res.Rate = ConvertDirect, []*dto.ConvertLeg{leg}, leg.Price
+		res.Result = amount.Mul(leg.Price)
+		lg.Info("converted", "from", from, "to", to, "method", res.Method)
+		return res, nil
+	}
+
+	leg, err = s.priceAt(ctx, entity.PairSymbol(to, from), at, maxAge)
+	if errors.Is(err, ErrStalePrice) {
+		stale, err = cmp.Or(stale, err), nil
+	}
+	if err != nil {
+		lg.Error("failed to look up inverse pair", "from", from, "to", to, "error", err)
+		return nil, err
+	}
+	if leg != nil && leg.Price.IsPositive() {
+		res.Method, res.Legs = ConvertInverse, []*dto.ConvertLeg{leg}
+		res.Rate = decimal.NewFromInt(1).DivRound(leg.Price, ratePrecision)
+		res.Result = amount.DivRound(leg.Price, ratePrecision)
+		lg.Info("converted", "from", from, "to", to, "method", res.Method)
+		return res, nil
+	}
+
+	// both legs are USD prices, and USD itself is worth exactly one
+	fromUSD, toUSD := decimal.NewFromInt(1), decimal.NewFromInt(1)
+	for _, side := range []struct {
+		currency string
+		price    *decimal.Decimal
+	}{{from, &fromUSD}, {to, &toUSD}} {
+		if side.currency == entity.DefaultQuote {
+			continue
+		}
+		leg, err := s.priceAt(ctx, entity.PairSymbol(side.currency, entity.DefaultQuote), at, maxAge)
+		if errors.Is(err, ErrStalePrice) {
+			return nil, err
+		}
+		if err != nil {
+			lg.Error("failed to look up usd price", "symbol", side.currency, "error", err)
+			return nil, err
+		}
+		if leg == nil || !leg.Price.IsPositive() {
+			if stale != nil {
+				return nil, stale
+			}
+			return nil, fmt.Errorf("%w: no rate from %s to %s", price.ErrPriceNotFound, from, to)
+		}
+		*side.price = leg.Price
+		res.Legs = append(res.Legs, leg)
+	}
+
+	res.Method = ConvertCross
+	res.Rate = fromUSD.DivRound(toUSD, ratePrecision)
+	res.Result = amount.Mul(fromUSD).DivRound(toUSD, ratePrecision)
+
+	lg.Info("converted", "from", from, "to", to, "method", res.Method)
+	return res, nil
+}
+
+// priceAt returns the price of symbol in effect at at, or nil when there is
+// none; one recorded more than maxAge seconds earlier is an ErrStalePrice
+func (s *priceService) priceAt(ctx context.Context, symbol string, at, maxAge int64) (*dto.ConvertLeg, error) {
+	points, err := s.repo.GetPricesAt(ctx, symbol, []int64{at})
+	if err != nil {
+		return nil, err
+	}
+	if len(points) == 0 || points[0] == nil {
+		return nil, nil
+	}
+	if at-points[0].Timestamp > maxAge {
+		return nil, fmt.Errorf("%w: %s has no price within %ds before %d", ErrStalePrice, symbol, maxAge, at)
+	}
+	return &dto.ConvertLeg{Symbol: symbol, Price: points[0].Price, Timestamp: points[0].Timestamp}, nil
+}
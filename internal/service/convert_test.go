@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+// pricesAt answers every lookup with the price of symbol, recorded age seconds earlier
+type pricesAt struct {
+	price.PriceRepository
+	prices map[string]int64
+	age    map[string]int64
+}
+
+func (p pricesAt) GetPricesAt(ctx context.Context, symbol string, at []int64) ([]*dto.PricePoint, error) {
+	points := make([]*dto.PricePoint, len(at))
+	if v, ok := p.prices[symbol]; ok {
+		for i, t := range at {
+			points[i] = &dto.PricePoint{Price: decimal.NewFromInt(v), Timestamp: t - p.age[symbol]}
+		}
+	}
+	return points, nil
+}
+
+func TestConvertStaleness(t *testing.T) {
+	const hour = 3600
+	tests := []struct {
+		name         string
+		age          map[string]int64
+		maxStaleness string
+		method       string   // empty when the conversion fails
+		legs         []string // symbols of the legs used
+		err          error
+	}{
+		{
+			name:   "fresh direct pair",
+			method: ConvertDirect,
+			legs:   []string{"btc-eur"},
+		},
+		{
+			name:   "stale direct pair passed over",
+			age:    map[string]int64{"btc-eur": 2 * hour},
+			method: ConvertCross,
+			legs:   []string{"btc", "eur"},
+		},
+		{
+			name:         "stale direct pair within the override",
+			age:          map[string]int64{"btc-eur": 2 * hour},
+			maxStaleness: "3h",
+			method:       ConvertDirect,
+			legs:         []string{"btc-eur"},
+		},
+		{
+			name: "stale cross leg",
+			age:  map[string]int64{"btc-eur": 2 * hour, "eur": 2 * hour},
+			err:  ErrStalePrice,
+		},
+		{
+			name:         "invalid override",
+			maxStaleness: "soon",
+			err:          ErrInvalidRequest,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Query.MaxStaleness = config.Duration{Duration: time.Hour}
+			repo := pricesAt{prices: map[string]int64{"btc-eur": 90000, "btc": 100000, "eur": 1}, age: tt.age}
+			s := NewPriceService(slog.New(slog.NewTextHandler(io.Discard, nil)), config.NewStore(cfg), repo,
+				&tickProvider{}, &flagAll{}, noBaskets{}, noAlerts{}, &catalogRecorder{})
+
+			res, err := s.Convert(context.Background(), &dto.ConvertReq{From: "btc", To: "eur", At: 1735689600, MaxStaleness: tt.maxStaleness})
+			if tt.err != nil {
+				if !errors.Is(err, tt.err) {
+					t.Fatalf("Convert() error = %v, want %v", err, tt.err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("Convert() error = %v", err)
+			}
+			var legs []string
+			for _, leg := range res.Legs {
+				legs = append(legs, leg.Symbol)
+			}
+			if res.Method != tt.method || !slices.Equal(legs, tt.legs) {
+				t.Errorf("Convert() = %s over %v, want %s over %v", res.Method, legs, tt.method, tt.legs)
+			}
+		})
+	}
+}
@@ -0,0 +1,69 @@
+package service
+
+import (
+	"encoding/base64"
+	"fmt"
+	"strconv"
+	"strings"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+const ticksCursorVersion = "v1"
+
+// encodeTicksCursor builds the opaque keyset cursor pointing at the last row returned
+func encodeTicksCursor(order string, lastTime int64) string {
+	raw := strings.Join([]string{ticksCursorVersion, order, strconv.FormatInt(lastTime, 10)}, ":")
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func decodeTicksCursor(cursor string) (order string, lastTime int64, err error) {
+	raw, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+	parts := strings.Split(string(raw), ":")
+	if len(parts) != 3 || parts[0] != ticksCursorVersion {
+		return "", 0, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+	lastTime, err = strconv.ParseInt(parts[2], 10, 64)
+	if err != nil {
+		return "", 0, fmt.Errorf("%w: malformed cursor", ErrInvalidRequest)
+	}
+	return parts[1], lastTime, nil
+}
+
+// resolveTicksCursor validates the order and narrows the time range to the
+// rows that come after the cursor. Keyset on time is enough because
+// (symbol, time) is the primary key.
+func resolveTicksCursor(req *dto.TicksReq) error {
+	switch req.Order {
+	case "":
+		req.Order = price.OrderAsc
+	case price.OrderAsc, price.OrderDesc:
+	default:
+		return fmt.Errorf("%w: order must be %q or %q", ErrInvalidRequest, price.OrderAsc, price.OrderDesc)
+	}
+	if req.From > req.To {
+		return fmt.Errorf("%w: from must not be after to", ErrInvalidRequest)
+	}
+
+	if req.Cursor == "" {
+		return nil
+	}
+	order, lastTime, err := decodeTicksCursor(req.Cursor)
+	if err != nil {
+		return err
+	}
+	if order != req.Order {
+		return fmt.Errorf("%w: cursor was issued for order %q", ErrInvalidRequest, order)
+	}
+
+	if req.Order == price.OrderAsc {
+		req.From = max(req.From, lastTime+1)
+	} else {
+		req.To = min(req.To, lastTime-1)
+	}
+	return nil
+}
@@ -0,0 +1,92 @@
+package service
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+func TestTicksCursorRoundTrip(t *testing.T) {
+	tests := []struct {
+		order    string
+		lastTime int64
+	}{
+		{price.OrderAsc, 1735689600},
+		{price.OrderDesc, 0},
+		{price.OrderDesc, -1},
+	}
+	for _, tt := range tests {
+		cursor := encodeTicksCursor(tt.order, tt.lastTime)
+		order, lastTime, err := decodeTicksCursor(cursor)
+		if err != nil {
+			t.Fatalf("decodeTicksCursor(%q) error = %v", cursor, err)
+		}
+		if order != tt.order || lastTime != tt.lastTime {
+			t.Errorf("decodeTicksCursor(encodeTicksCursor(%q, %d)) = %q, %d", tt.order, tt.lastTime, order, lastTime)
+		}
+	}
+}
+
+func TestDecodeTicksCursorMalformed(t *testing.T) {
+	encode := func(raw string) string { return base64.RawURLEncoding.EncodeToString([]byte(raw)) }
+	tests := []struct {
+		name   string
+		cursor string
+	}{
+		{"not base64", "!!!"},
+		{"padded base64", base64.URLEncoding.EncodeToString([]byte("v1:asc:1"))},
+		{"other version", encode("v2:asc:1")},
+		{"missing time", encode("v1:asc")},
+		{"extra part", encode("v1:asc:1:2")},
+		{"time not a number", encode("v1:asc:x")},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, _, err := decodeTicksCursor(tt.cursor); !errors.Is(err, ErrInvalidRequest) {
+				t.Errorf("decodeTicksCursor(%q) error = %v, want ErrInvalidRequest", tt.cursor, err)
+			}
+		})
+	}
+}
+
+func TestResolveTicksCursor(t *testing.T) {
+	tests := []struct {
+		name     string
+		req      dto.TicksReq
+		from, to int64
+		invalid  bool
+	}{
+		{name: "no cursor", req: dto.TicksReq{From: 10, To: 100}, from: 10, to: 100},
+		{name: "ascending after the cursor",
+			req: dto.TicksReq{From: 10, To: 100, Order: price.OrderAsc, Cursor: encodeTicksCursor(price.OrderAsc, 50)}, from: 51, to: 100},
+		{name: "ascending cursor before the range",
+			req: dto.TicksReq{From: 10, To: 100, Cursor: encodeTicksCursor(price.OrderAsc, 5)}, from: 10, to: 100},
+		{name: "descending before the cursor",
+			req: dto.TicksReq{From: 10, To: 100, Order: price.OrderDesc, Cursor: encodeTicksCursor(price.OrderDesc, 50)}, from: 10, to: 49},
+		{name: "cursor of the other order", invalid: true,
+			req: dto.TicksReq{From: 10, To: 100, Order: price.OrderDesc, Cursor: encodeTicksCursor(price.OrderAsc, 50)}},
+		{name: "unknown order", req: dto.TicksReq{Order: "up"}, invalid: true},
+		{name: "inverted range", req: dto.TicksReq{From: 100, To: 10}, invalid: true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			req := tt.req
+			err := resolveTicksCursor(&req)
+			if tt.invalid {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Fatalf("resolveTicksCursor() error = %v, want ErrInvalidRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("resolveTicksCursor() error = %v", err)
+			}
+			if req.From != tt.from || req.To != tt.to {
+				t.Errorf("range = [%d, %d], want [%d, %d]", req.From, req.To, tt.from, tt.to)
+			}
+		})
+	}
+}
@@ -0,0 +1,376 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+const (
+	// MaxIndicatorPeriod bounds window parameters, and with them the warm-up fetched
+	MaxIndicatorPeriod = 200
+	MaxIndicators      = 10
+
+	DefaultIndicators = "sma:20,ema:20,rsi:14,bollinger:20:2,macd:12:26:9"
+
+	// warmupTolerance is the weight the seed of a smoothed indicator (EMA, RSI)
+	// may still have on the first returned point
+	warmupTolerance = 1e-4
+)
+
+// indicator computes one or more named series over closing prices. Series are
+// as long as the input and hold NaN where the indicator is not defined yet.
+type indicator struct {
+	// warmup is how many buckets before the first returned one are needed for it to be correct
+	warmup  int
+	compute func(closes []float64) map[string][]float64
+	// valid is false when the parameters contradict each other
+	valid bool
+}
+
+// parseIndicators parses a comma separated list such as "sma:20,rsi:14,macd:12:26:9".
+// Parameters may be left out to use the usual defaults.
+func parseIndicators(spec string) ([]indicator, error) {
+	if strings.TrimSpace(spec) == "" {
+		spec = DefaultIndicators
+	}
+	items := strings.Split(spec, ",")
+	if len(items) > MaxIndicators {
+		return nil, fmt.Errorf("%w: at most %d indicators", ErrInvalidRequest, MaxIndicators)
+	}
+
+	var out []indicator
+	for _, item := range items {
+		parts := strings.Split(strings.TrimSpace(item), ":")
+		name, args := strings.ToLower(parts[0]), parts[1:]
+		var (
+			ind indicator
+			err error
+		)
+		switch name {
+		case "sma":
+			ind, err = withPeriods(name, args, []int{20}, func(p []int) indicator { return smaIndicator(p[0]) })
+		case "ema":
+			ind, err = withPeriods(name, args, []int{20}, func(p []int) indicator { return emaIndicator(p[0]) })
+		case "rsi":
+			ind, err = withPeriods(name, args, []int{14}, func(p []int) indicator { return rsiIndicator(p[0]) })
+		case "macd":
+			ind, err = withPeriods(name, args, []int{12, 26, 9}, func(p []int) indicator { return macdIndicator(p[0], p[1], p[2]) })
+			if err == nil && !ind.valid {
+				err = fmt.Errorf("%w: macd fast period must be shorter than the slow one", ErrInvalidRequest)
+			}
+		case "bollinger":
+			ind, err = bollingerFromArgs(args)
+		default:
+			err = fmt.Errorf("%w: unknown indicator %q, expected sma, ema, rsi, bollinger or macd", ErrInvalidRequest, name)
+		}
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, ind)
+	}
+	return out, nil
+}
+
+func withPeriods(name string, args []string, defaults []int, build func([]int) indicator) (indicator, error) {
+	if len(args) > len(defaults) {
+		return indicator{}, fmt.Errorf("%w: %s takes at most %d parameters", ErrInvalidRequest, name, len(defaults))
+	}
+	periods := append([]int(nil), defaults...)
+	for i, arg := range args {
+		n, err := strconv.Atoi(arg)
+		if err != nil || n < 1 || n > MaxIndicatorPeriod {
+			return indicator{}, fmt.Errorf("%w: %s period %q must be between 1 and %d", ErrInvalidRequest, name, arg, MaxIndicatorPeriod)
+		}
+		periods[i] = n
+	}
+	return build(periods), nil
+}
+
+func bollingerFromArgs(args []string) (indicator, error) {
+	if len(args) > 2 {
+		return indicator{}, fmt.Errorf("%w: bollinger takes a period and a width", ErrInvalidRequest)
+	}
+	var width float64 = 2
+	if len(args) == 2 {
+		k, err := strconv.ParseFloat(args[1], 64)
+		if err != nil || k <= 0 || k > 10 {
+			return indicator{}, fmt.Errorf("%w: bollinger width %q must be above 0 and at most 10", ErrInvalidRequest, args[1])
+		}
+		width = k
+	}
+	return withPeriods("bollinger", args[:min(len(args), 1)], []int{20}, func(p []int) indicator {
+		return bollingerIndicator(p[0], width)
+	})
+}
+
+// convergence is how many steps of smoothing with alpha it takes for the seed
+// to weigh less than warmupTolerance
+func convergence(alpha float64) int {
+	if alpha >= 1 {
+		return 0
+	}
+	return int(math.Ceil(math.Log(warmupTolerance) / math.Log(1-alpha)))
+}
+
+func emaAlpha(n int) float64 { return 2 / float64(n+1) }
+
+func smaIndicator(n int) indicator {
+	return indicator{
+		valid:  true,
+		warmup: n - 1,
+		compute: func(closes []float64) map[string][]float64 {
+			return map[string][]float64{fmt.Sprintf("sma_%d", n): sma(closes, n)}
+		},
+	}
+}
+
+func emaIndicator(n int) indicator {
+	return indicator{
+		valid:  true,
+		warmup: n - 1 + convergence(emaAlpha(n)),
+		compute: func(closes []float64) map[string][]float64 {
+			return map[string][]float64{fmt.Sprintf("ema_%d", n): ema(closes, n)}
+		},
+	}
+}
+
+func rsiIndicator(n int) indicator {
+	return indicator{
+		valid:  true,
+		warmup: n + convergence(1/float64(n)),
+		compute: func(closes []float64) map[string][]float64 {
+			return map[string][]float64{fmt.Sprintf("rsi_%d", n): rsi(closes, n)}
+		},
+	}
+}
+
+func bollingerIndicator(n int, width float64) indicator {
+	return indicator{
+		valid:  true,
+		warmup: n - 1,
+		compute: func(closes []float64) map[string][]float64 {
+			name := fmt.Sprintf("bollinger_%d_%s", n, strconv.FormatFloat(width, 'f', -1, 64))
+			middle, upper, lower := bollinger(closes, n, width)
+			return map[string][]float64{
+				name + "_middle": middle,
+				name + "_upper":  upper,
+				name + "_lower":  lower,
+			}
+		},
+	}
+}
+
+func macdIndicator(fast, slow, signal int) indicator {
+	return indicator{
+		valid:  fast < slow,
+		warmup: slow - 1 + convergence(emaAlpha(slow)) + signal - 1 + convergence(emaAlpha(signal)),
+		compute: func(closes []float64) map[string][]float64 {
+			name := fmt.Sprintf("macd_%d_%d_%d", fast, slow, signal)
+			line, sig, hist := macd(closes, fast, slow, signal)
+			return map[string][]float64{
+				name:                line,
+				name + "_signal":    sig,
+				name + "_histogram": hist,
+			}
+		},
+	}
+}
+
+func nanSeries(n int) []float64 {
+	out := make([]float64, n)
+	for i := range out {
+		out[i] = math.NaN()
+	}
+	return out
+}
+
+func sma(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	var sum float64
+	for i, v := range values {
+		sum += v
+		if i >= n {
+			sum -= values[i-n]
+		}
+		if i >= n-1 {
+			out[i] = sum / float64(n)
+		}
+	}
+	return out
+}
+
+// ema is seeded with the SMA of the first n defined values; leading NaNs are skipped
+func ema(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	start := 0
+	for start < len(values) && math.IsNaN(values[start]) {
+		start++
+	}
+	if len(values)-start < n {
+		return out
+	}
+
+	alpha := emaAlpha(n)
+	var sum float64
+	for _, v := range values[start : start+n] {
+		sum += v
+	}
+	prev := sum / float64(n)
+	out[start+n-1] = prev
+	for i := start + n; i < len(values); i++ {
+		prev = alpha*values[i] + (1-alpha)*prev
+		out[i] = prev
+	}
+	return out
+}
+
+// rsi uses Wilder's smoothing of average gains and losses
+func rsi(values []float64, n int) []float64 {
+	out := nanSeries(len(values))
+	if len(values) <= n {
+		return out
+	}
+
+	var gain, loss float64
+	for i := 1; i <= n; i++ {
+		change := values[i] - values[i-1]
+		gain += max(change, 0)
+		loss += max(-change, 0)
+	}
+	gain, loss = gain/float64(n), loss/float64(n)
+	out[n] = rsiValue(gain, loss)
+
+	for i := n + 1; i < len(values); i++ {
+		change := values[i] - values[i-1]
+		gain = (gain*float64(n-1) + max(change, 0)) / float64(n)
+		loss = (loss*float64(n-1) + max(-change, 0)) / float64(n)
+		out[i] = rsiValue(gain, loss)
+	}
+	return out
+}
+
+func rsiValue(gain, loss float64) float64 {
+	if loss == 0 {
+		if gain == 0 {
+			return 50
+		}
+		return 100
+	}
+	return 100 - 100/(1+gain/loss)
+}
+
+// bollinger bands are the SMA plus and minus width population standard deviations
+func bollinger(values []float64, n int, width float64) (middle, upper, lower []float64) {
+	middle = sma(values, n)
+	upper, lower = nanSeries(len(values)), nanSeries(len(values))
+	for i := n - 1; i < len(values); i++ {
+		var variance float64
+		for _, v := range values[i-n+1 : i+1] {
+			variance += (v - middle[i]) * (v - middle[i])
+		}
+		sd := math.Sqrt(variance / float64(n))
+		upper[i] = middle[i] + width*sd
+		lower[i] = middle[i] - width*sd
+	}
+	return middle, upper, lower
+}
+
+func macd(values []float64, fast, slow, signal int) (line, sig, hist []float64) {
+	fastEMA, slowEMA := ema(values, fast), ema(values, slow)
+	line = nanSeries(len(values))
+	for i := range values {
+		line[i] = fastEMA[i] - slowEMA[i] // NaN until both are defined
+	}
+	sig = ema(line, signal)
+	hist = nanSeries(len(values))
+	for i := range values {
+		hist[i] = line[i] - sig[i]
+	}
+	return line, sig, hist
+}
+
+// GetIndicators computes indicators over the closing prices of the buckets in
+// [req.From, req.To]. History before From is read as well, so that the first
+// returned point already has the values it would have on a longer chart.
+func (s *priceService) GetIndicators(ctx context.Context, req *dto.IndicatorsReq) (*dto.IndicatorsRes, error) {
+	lg := s.logger.With("method", "GetIndicators")
+
+	if err := validateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+	indicators, err := parseIndicators(req.Indicators)
+	if err != nil {
+		return nil, err
+	}
+	if req.Interval == "" {
+		req.Interval = price.DefaultInterval
+	}
+	interval, err := price.ParseInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+	bucket := int64(interval / time.Second)
+
+	warmup := 0
+	for _, ind := range indicators {
+		warmup = max(warmup, ind.warmup)
+	}
+
+	history, err := s.repo.GetHistory(ctx, &dto.HistoryReq{
+		Symbol:   req.Symbol,
+		Interval: req.Interval,
+		From:     max(0, req.From-int64(warmup)*bucket),
+		To:       req.To,
+	})
+	if err != nil {
+		lg.Error("failed to fetch price history", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	closes := make([]float64, len(history))
+	for i, point := range history {
+		closes[i] = point.LastPrice.InexactFloat64()
+	}
+	series := make(map[string][]float64)
+	for _, ind := range indicators {
+		for name, values := range ind.compute(closes) {
+			series[name] = values
+		}
+	}
+
+	res := &dto.IndicatorsRes{Symbol: req.Symbol, Interval: req.Interval}
+	for i, point := range history {
+		// the bucket holding From is returned, as GetHistory does
+		if point.StartedAt+bucket <= req.From {
+			continue
+		}
+		values := make(map[string]*decimal.Decimal, len(series))
+		for name, line := range series {
+			if !math.IsNaN(line[i]) && !math.IsInf(line[i], 0) {
+				v := decimal.NewFromFloat(line[i])
+				values[name] = &v
+			} else {
+				values[name] = nil
+			}
+		}
+		res.Points = append(res.Points, &dto.IndicatorPoint{
+			StartedAt: point.StartedAt,
+			Close:     point.LastPrice,
+			Values:    values,
+		})
+	}
+	if len(res.Points) == 0 {
+		return nil, price.ErrPriceNotFound
+	}
+
+	lg.Info("computed indicators", "symbol", req.Symbol, "points", len(res.Points), "warmup", len(history)-len(res.Points))
+	return res, nil
+}
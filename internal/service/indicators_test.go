@@ -0,0 +1,153 @@
+package service
+
+import (
+	"errors"
+	"math"
+	"slices"
+	"strings"
+	"testing"
+)
+
+func TestParseIndicators(t *testing.T) {
+	tests := []struct {
+		spec   string
+		series []string // names computed, nil when the spec is invalid
+	}{
+		{"", []string{"bollinger_20_2_lower", "bollinger_20_2_middle", "bollinger_20_2_upper",
+			"ema_20", "macd_12_26_9", "macd_12_26_9_histogram", "macd_12_26_9_signal", "rsi_14", "sma_20"}},
+		{"sma", []string{"sma_20"}},
+		{" SMA:5 , ema:3", []string{"ema_3", "sma_5"}},
+		{"rsi:200", []string{"rsi_200"}},
+		{"bollinger:10:2.5", []string{"bollinger_10_2.5_lower", "bollinger_10_2.5_middle", "bollinger_10_2.5_upper"}},
+		{"macd:3:6", []string{"macd_3_6_9", "macd_3_6_9_histogram", "macd_3_6_9_signal"}},
+		{"sma:0", nil},
+		{"sma:201", nil},
+		{"sma:x", nil},
+		{"sma:1:2", nil},
+		{"macd:26:12", nil},
+		{"macd:12:12", nil},
+		{"bollinger:20:0", nil},
+		{"bollinger:20:11", nil},
+		{"bollinger:20:2:1", nil},
+		{"vwap", nil},
+		{"sma,", nil},
+		{strings.Repeat("sma,", MaxIndicators) + "sma", nil},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			inds, err := parseIndicators(tt.spec)
+			if tt.series == nil {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Fatalf("parseIndicators(%q) error = %v, want ErrInvalidRequest", tt.spec, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("parseIndicators(%q) error = %v", tt.spec, err)
+			}
+			var names []string
+			for _, ind := range inds {
+				for name := range ind.compute(make([]float64, 3)) {
+					names = append(names, name)
+				}
+			}
+			slices.Sort(names)
+			if !slices.Equal(names, tt.series) {
+				t.Errorf("parseIndicators(%q) computes %v, want %v", tt.spec, names, tt.series)
+			}
+		})
+	}
+}
+
+func TestEMA(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{"seeded with the sma", []float64{1, 2, 3, 4, 5}, 3, []float64{nan, nan, 2, 3, 4}},
+		{"leading gaps skipped", []float64{nan, 1, 2, 3, 4, 5}, 3, []float64{nan, nan, nan, 2, 3, 4}},
+		{"too short", []float64{1, 2}, 3, []float64{nan, nan}},
+		{"period of one", []float64{4, 2}, 1, []float64{4, 2}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, ema(tt.values, tt.n), tt.want, 1e-12)
+		})
+	}
+}
+
+func TestRSI(t *testing.T) {
+	nan := math.NaN()
+	tests := []struct {
+		name   string
+		values []float64
+		n      int
+		want   []float64
+	}{
+		{"only gains", []float64{1, 2, 3, 4}, 2, []float64{nan, nan, 100, 100}},
+		{"only losses", []float64{4, 3, 2, 1}, 2, []float64{nan, nan, 0, 0}},
+		{"flat", []float64{5, 5, 5}, 2, []float64{nan, nan, 50}},
+		// average gain 1 and loss 0, then 0.5 and 0.25, then 0.25 and 0.125 by Wilder's smoothing
+		{"smoothed", []float64{10, 11, 12, 11.5, 11.5}, 2, []float64{nan, nan, 100, 100 - 100/(1+0.5/0.25), 100 - 100/(1+0.25/0.125)}},
+		{"too short", []float64{1, 2}, 2, []float64{nan, nan}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assertSeries(t, rsi(tt.values, tt.n), tt.want, 1e-9)
+		})
+	}
+}
+
+// TestIndicatorWarmup checks that warmup buckets before the first returned one
+// are enough for a smoothed indicator to match the one computed over the full
+// history, whatever the seed was
+func TestIndicatorWarmup(t *testing.T) {
+	closes := make([]float64, 3000)
+	for i := range closes {
+		closes[i] = 100 + 10*math.Sin(float64(i)/7) + 5*math.Cos(float64(i)/3)
+	}
+	const returned = 50
+
+	tests := []struct {
+		spec string
+		tol  float64 // the seed weighs at most warmupTolerance, prices span 30
+	}{
+		{"ema:2", 30 * warmupTolerance},
+		{"ema:20", 30 * warmupTolerance},
+		{"ema:200", 30 * warmupTolerance},
+		{"rsi:2", 100 * warmupTolerance},
+		{"rsi:14", 100 * warmupTolerance},
+		{"rsi:200", 100 * warmupTolerance},
+		{"macd:12:26:9", 2 * 30 * warmupTolerance},
+	}
+	for _, tt := range tests {
+		t.Run(tt.spec, func(t *testing.T) {
+			inds, err := parseIndicators(tt.spec)
+			if err != nil {
+				t.Fatal(err)
+			}
+			ind := inds[0]
+			start := len(closes) - returned
+			full := ind.compute(closes)
+			short := ind.compute(closes[start-ind.warmup:])
+			for name, series := range full {
+				assertSeries(t, short[name][ind.warmup:], series[start:], tt.tol)
+			}
+		})
+	}
+}
+
+func assertSeries(t *testing.T, got, want []float64, tol float64) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %d points, want %d", len(got), len(want))
+	}
+	for i := range want {
+		if math.IsNaN(want[i]) != math.IsNaN(got[i]) || math.Abs(got[i]-want[i]) > tol {
+			t.Errorf("point %d = %v, want %v", i, got[i], want[i])
+		}
+	}
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/shopspring/decimal"
+)
+
+// MaxHoldings bounds the holdings valued in one request
+const MaxHoldings = 100
+
+// ValuePortfolio values every holding at req.At and req.Since from stored
+// prices. A holding without a price within the maximum staleness of either
+// time fails the request rather than being left out of the totals.
+func (s *priceService) ValuePortfolio(ctx context.Context, req *dto.PortfolioReq) (*dto.PortfolioRes, error) {
+	lg := s.logger.With("method", "ValuePortfolio")
+
+	holdings, err := mergeHoldings(req.Holdings)
+	if err != nil {
+		return nil, err
+	}
+	maxAge, err := s.maxStaleness(req.MaxStaleness)
+	if err != nil {
+		return nil, err
+	}
+	at, since := req.At, req.Since
+	if at == 0 {
+		at = time.Now().Unix()
+	}
+	if since == 0 {
+		since = at - 86400
+	}
+	if since > at {
+		return nil, fmt.Errorf("%w: since must not be after at", ErrInvalidRequest)
+	}
+
+	res := &dto.PortfolioRes{At: at, Since: since, Value: decimal.Zero, SinceValue: decimal.Zero}
+	for _, h := range holdings {
+		points, err := s.repo.GetPricesAt(ctx, h.Symbol, []int64{at, since})
+		if err != nil {
+			lg.Error("failed to get prices", "symbol", h.Symbol, "error", err)
+			return nil, err
+		}
+		for i, t := range []int64{at, since} {
+			if points[i] == nil || t-points[i].Timestamp > maxAge {
+				return nil, fmt.Errorf("%w: %s has no price within %ds before %d", ErrStalePrice, h.Symbol, maxAge, t)
+			}
+		}
+
+		asset := &dto.HoldingRes{
+			Symbol:     h.Symbol,
+			Quantity:   h.Quantity,
+			Price:      *points[0],
+			SincePrice: *points[1],
+			Value:      h.Quantity.Mul(points[0].Price),
+			SinceValue: h.Quantity.Mul(points[1].Price),
+		}
+		asset.PnL = asset.Value.Sub(asset.SinceValue)
+		asset.PnLPct = pct(asset.PnL, asset.SinceValue)
+		res.Value = res.Value.Add(asset.Value)
+		res.SinceValue = res.SinceValue.Add(asset.SinceValue)
+		res.Assets = append(res.Assets, asset)
+	}
+
+	res.PnL = res.Value.Sub(res.SinceValue)
+	res.PnLPct = pct(res.PnL, res.SinceValue)
+	for _, asset := range res.Assets {
+		asset.Weight = decimal.Zero
+		if res.Value.IsPositive() {
+			asset.Weight = asset.Value.DivRound(res.Value, ratioPrecision)
+		}
+	}
+
+	lg.Info("valued portfolio", "holdings", len(res.Assets), "value", res.Value)
+	return res, nil
+}
+
+// mergeHoldings normalizes symbols and adds up holdings of the same symbol, keeping their order
+func mergeHoldings(in []dto.HoldingReq) ([]dto.HoldingReq, error) {
+	if len(in) == 0 || len(in) > MaxHoldings {
+		return nil, fmt.Errorf("%w: between 1 and %d holdings", ErrInvalidRequest, MaxHoldings)
+	}
+
+	var out []dto.HoldingReq
+	index := make(map[string]int, len(in))
+	for _, h := range in {
+		symbol := strings.ToLower(strings.TrimSpace(h.Symbol))
+		if symbol == "" {
+			return nil, fmt.Errorf("%w: holding without a symbol", ErrInvalidRequest)
+		}
+		if h.Quantity.IsNegative() {
+			return nil, fmt.Errorf("%w: quantity of %s must not be negative", ErrInvalidRequest, symbol)
+		}
+		if i, ok := index[symbol]; ok {
+			out[i].Quantity = out[i].Quantity.Add(h.Quantity)
+			continue
+		}
+		index[symbol] = len(out)
+		out = append(out, dto.HoldingReq{Symbol: symbol, Quantity: h.Quantity})
+	}
+	return out, nil
+}
+
+// pct is change as a percentage of base, nil when base is zero
+func pct(change, base decimal.Decimal) *decimal.Decimal {
+	if base.IsZero() {
+		return nil
+	}
+	p := change.Mul(hundred).DivRound(base, ratioPrecision)
+	return &p
+}
@@ -0,0 +1,62 @@
+package service
+
+import (
+	"errors"
+	"testing"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/shopspring/decimal"
+)
+
+func TestMergeHoldings(t *testing.T) {
+	holding := func(symbol, quantity string) dto.HoldingReq {
+		return dto.HoldingReq{Symbol: symbol, Quantity: decimal.RequireFromString(quantity)}
+	}
+	tests := []struct {
+		name string
+		in   []dto.HoldingReq
+		want []dto.HoldingReq // nil when the holdings are invalid
+	}{
+		{
+			name: "distinct",
+			in:   []dto.HoldingReq{holding("btc", "0.5"), holding("eth", "12")},
+			want: []dto.HoldingReq{holding("btc", "0.5"), holding("eth", "12")},
+		},
+		{
+			name: "same symbol added up in first order",
+			in:   []dto.HoldingReq{holding("eth", "1"), holding(" BTC ", "0.25"), holding("Eth", "2.5"), holding("btc", "0.25")},
+			want: []dto.HoldingReq{holding("eth", "3.5"), holding("btc", "0.5")},
+		},
+		{
+			name: "zero quantity kept",
+			in:   []dto.HoldingReq{holding("btc", "0")},
+			want: []dto.HoldingReq{holding("btc", "0")},
+		},
+		{name: "none", in: nil},
+		{name: "too many", in: make([]dto.HoldingReq, MaxHoldings+1)},
+		{name: "blank symbol", in: []dto.HoldingReq{holding(" ", "1")}},
+		{name: "negative quantity", in: []dto.HoldingReq{holding("btc", "1"), holding("btc", "-0.1")}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := mergeHoldings(tt.in)
+			if tt.want == nil {
+				if !errors.Is(err, ErrInvalidRequest) {
+					t.Fatalf("mergeHoldings() error = %v, want ErrInvalidRequest", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("mergeHoldings() error = %v", err)
+			}
+			if len(got) != len(tt.want) {
+				t.Fatalf("mergeHoldings() = %v, want %v", got, tt.want)
+			}
+			for i := range tt.want {
+				if got[i].Symbol != tt.want[i].Symbol || !got[i].Quantity.Equal(tt.want[i].Quantity) {
+					t.Errorf("holding %d = %s %s, want %s %s", i, got[i].Symbol, got[i].Quantity, tt.want[i].Symbol, tt.want[i].Quantity)
+				}
+			}
+		})
+	}
+}
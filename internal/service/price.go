@@ -6,20 +6,23 @@ import (
 	"fmt"
 	"github.com/milad-rasouli/price/entity"
 	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
 	"github.com/milad-rasouli/price/internal/providers/currency"
 	"github.com/milad-rasouli/price/internal/repository/repository/price"
 	"log/slog"
-	"time"
+	"slices"
 )
 
 var (
 	ErrFailedToGetPrice         = errors.New("failed to get price")
 	ErrFailedToInsertBatchPrice = errors.New("failed to insert batch price")
+	ErrInvalidRequest           = errors.New("invalid request")
+	ErrStalePrice               = errors.New("no recent price")
 )
 
 const (
-	MaxRetry     int           = 3
-	BackoffDelay time.Duration = 1 * time.Second
+	DefaultTicksLimit = 100
+	MaxTicksLimit     = 1000
 )
 
 //go:generate mockgen -source=price.go -destination=../../mock/service/price/price.go
@@ -27,70 +30,211 @@ type PriceService interface {
 	InsertBatch(ctx context.Context) error
 	GetLatest(ctx context.Context, req *dto.LatestReq) (*dto.LatestRes, error)
 	GetHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.HistoryRes, error)
+	StreamHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.HistoryRes) error) error
+	GetMarketHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.MarketHistoryRes, error)
+	StreamMarketHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.MarketHistoryRes) error) error
+	GetTicks(ctx context.Context, req *dto.TicksReq) (*dto.TicksRes, error)
+	StreamTicks(ctx context.Context, req *dto.TicksReq, fn func(*dto.TickRes) error) error
+	GetVersion(ctx context.Context, symbol string, to int64) (*entity.DataVersion, error)
+	GetTWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error)
+	GetVWAP(ctx context.Context, req *dto.AverageReq) (*dto.AverageRes, error)
+	GetIndicators(ctx context.Context, req *dto.IndicatorsReq) (*dto.IndicatorsRes, error)
+	GetStatistics(ctx context.Context, req *dto.HistoryReq) (*dto.StatisticsRes, error)
+	Convert(ctx context.Context, req *dto.ConvertReq) (*dto.ConvertRes, error)
+	GetAsOf(ctx context.Context, req *dto.AsOfReq) ([]*dto.AsOfRes, error)
+	ValuePortfolio(ctx context.Context, req *dto.PortfolioReq) (*dto.PortfolioRes, error)
+	Backfill(ctx context.Context, symbol string, from, to int64) (int64, error)
 }
 
 type priceService struct {
 	logger           *slog.Logger
+	store            *config.Store
 	repo             price.PriceRepository
 	currencyProvider currency.CurrencyProvider
+	anomalies        AnomalyService
+	baskets          BasketService
+	alerts           AlertService
+	catalog          CatalogService
 }
 
 func NewPriceService(
 	logger *slog.Logger,
+	store *config.Store,
 	repo price.PriceRepository,
 	currencyProvider currency.CurrencyProvider,
+	anomalies AnomalyService,
+	baskets BasketService,
+	alerts AlertService,
+	catalog CatalogService,
 ) PriceService {
 	return &priceService{
 		logger:           logger.With("Layer", "PriceService"),
+		store:            store,
 		repo:             repo,
 		currencyProvider: currencyProvider,
+		anomalies:        anomalies,
+		baskets:          baskets,
+		alerts:           alerts,
+		catalog:          catalog,
 	}
 }
 
 func (s *priceService) InsertBatch(ctx context.Context) error {
-	var (
-		lg     = s.logger.With("method", "InsertBatch")
-		prices []*entity.Price
-		err    error
-	)
-	for attempt := 1; attempt <= MaxRetry; attempt++ {
-		prices, err = s.currencyProvider.Get(ctx, 1, 3)
-		if err == nil {
-			break
-		}
-		if errors.Is(err, currency.ErrCurrencyTooManyRequests) {
-			lg.Warn("Get currency is too many requests", "error", err)
-			return fmt.Errorf("%w (attempt %d)", err, attempt)
-		}
-		if attempt == MaxRetry {
-			lg.Error("failed to get prices", "attempt", attempt, "error", err)
-			return fmt.Errorf("%w: %s", ErrFailedToGetPrice, err)
+	lg := s.logger.With("method", "InsertBatch")
+
+	// one snapshot per batch, a reload applies from the next tick
+	ingest := s.store.Current().Ingest
+
+	var prices []*entity.Price
+	for _, quote := range ingest.QuoteCurrencies {
+		quoted, err := s.fetchQuote(ctx, lg, &ingest, quote)
+		if err != nil {
+			return err
 		}
+		prices = append(prices, quoted...)
+	}
 
-		lg.Warn("failed to get prices", "attempt", attempt, "error", err)
-		select {
-		case <-time.After(BackoffDelay):
-		case <-ctx.Done():
-			return ctx.Err()
+	// a coin listed under a basket's name would mix into the basket's series
+	baskets, err := s.catalog.BasketNames(ctx)
+	if err != nil {
+		lg.Error("failed to get basket names", "error", err)
+		return fmt.Errorf("%w: %s", ErrFailedToInsertBatchPrice, err)
+	}
+	prices = slices.DeleteFunc(prices, func(p *entity.Price) bool {
+		if baskets[p.Symbol] {
+			lg.Warn("skipped a price under a basket's name", "symbol", p.Symbol)
 		}
+		return baskets[p.Symbol]
+	})
 
+	fetched := prices
+	prices, anomalies, err := s.anomalies.Screen(ctx, prices)
+	if err != nil {
+		return fmt.Errorf("%w: %s", ErrFailedToInsertBatchPrice, err)
 	}
 	if err := s.repo.BatchInsert(ctx, prices); err != nil {
 		lg.Error("failed to batch insert prices", "error", err)
 		return fmt.Errorf("%w: %s", ErrFailedToInsertBatchPrice, err)
 	}
 	lg.Info("successfully inserted batch of prices", "count", len(prices))
+
+	// the flags only go to review once their ticks are stored; if recording
+	// fails, flagged ticks stay stored unreviewed and quarantined ones are
+	// dropped like a tick the provider missed
+	if err := s.anomalies.Record(ctx, anomalies); err != nil {
+		lg.Error("failed to record anomalies", "error", err)
+	}
+
+	// the prices are stored either way, a basket missing this tick shows as a gap
+	values, err := s.baskets.ValueBaskets(ctx, prices)
+	if err != nil {
+		lg.Error("failed to value baskets", "error", err)
+	}
+	if err := s.alerts.Evaluate(ctx, append(prices, values...)); err != nil {
+		lg.Error("failed to evaluate alert rules", "error", err)
+	}
+	// quarantined ticks are still being ingested, only their prices wait for review.
+	// A configured pair the provider skipped this tick keeps ingesting, only
+	// the ones removed from the config stop; the top coins are those of the tick.
+	if err := s.catalog.Track(ctx, slices.Concat(fetched, values), trackedSymbols(&ingest)); err != nil {
+		lg.Error("failed to update the symbol catalog", "error", err)
+	}
 	return nil
 }
 
+// trackedSymbols are the catalog symbols of the configured coins in every
+// quote currency, or none when the top coins are ingested
+func trackedSymbols(ingest *config.Ingest) []string {
+	tracked := make([]string, 0, len(ingest.Symbols)*len(ingest.QuoteCurrencies))
+	for _, quote := range ingest.QuoteCurrencies {
+		for _, symbol := range ingest.Symbols {
+			tracked = append(tracked, entity.PairSymbol(symbol, quote))
+		}
+	}
+	return tracked
+}
+
+// fetchQuote pages through the tracked symbols, or the top coins by market
+// cap when none are configured, priced in one quote currency.
+func (s *priceService) fetchQuote(ctx context.Context, lg *slog.Logger, ingest *config.Ingest, quote string) ([]*entity.Price, error) {
+	var (
+		prices  []*entity.Price
+		queries []*currency.Query
+	)
+	if len(ingest.Symbols) > 0 {
+		for chunk := range slices.Chunk(ingest.Symbols, int(ingest.PageSize)) {
+			queries = append(queries, &currency.Query{Quote: quote, Symbols: chunk, Page: 1, Limit: uint32(len(chunk))})
+		}
+	} else {
+		perPage := min(ingest.PageSize, ingest.Top)
+		for page := uint32(1); (page-1)*perPage < ingest.Top; page++ {
+			queries = append(queries, &currency.Query{Quote: quote, Page: page, Limit: perPage})
+		}
+	}
+
+	for _, q := range queries {
+		page, err := s.currencyProvider.Get(ctx, q)
+		if err != nil {
+			return nil, fetchErr(lg, err)
+		}
+		prices = append(prices, page...)
+	}
+
+	if len(ingest.Symbols) == 0 && len(prices) > int(ingest.Top) {
+		prices = prices[:ingest.Top]
+	}
+	return prices, nil
+}
+
+// fetchErr wraps a provider error for the controllers. Rate limiting keeps
+// its own error so it can be answered with 429.
+func fetchErr(lg *slog.Logger, err error) error {
+	if errors.Is(err, currency.ErrCurrencyTooManyRequests) {
+		lg.Warn("Get currency is too many requests", "error", err)
+		return err
+	}
+	if errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) {
+		return err
+	}
+	lg.Error("failed to get prices", "error", err)
+	return fmt.Errorf("%w: %s", ErrFailedToGetPrice, err)
+}
+
 func (s *priceService) GetLatest(ctx context.Context, req *dto.LatestReq) (*dto.LatestRes, error) {
 	lg := s.logger.With("method", "GetLatest")
+
+	windows, err := parseWindows(req.Windows)
+	if err != nil {
+		return nil, err
+	}
+
 	latest, err := s.repo.GetLatest(ctx, req)
 	if err != nil {
 		lg.Error("failed to get latest price", "symbol", req.Symbol, "error", err)
 		return nil, err
 	}
 
+	// the last lookup backs the deprecated Change24HPct
+	at := make([]int64, 0, len(windows)+1)
+	for _, w := range windows {
+		at = append(at, windowStart(w, latest.Timestamp))
+	}
+	at = append(at, latest.Timestamp-86400)
+
+	references, err := s.repo.GetPricesAt(ctx, req.Symbol, at)
+	if err != nil {
+		lg.Error("failed to get reference prices", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	latest.Changes = make(map[string]*dto.ChangeRes, len(windows))
+	for i, w := range windows {
+		latest.Changes[w] = change(latest.Price, references[i])
+	}
+	if c := change(latest.Price, references[len(windows)]); c != nil {
+		latest.Change24HPct = c.ChangePct.InexactFloat64()
+	}
+
 	lg.Info("fetched latest price", "symbol", req.Symbol, "price", latest.Price, "change_24h_pct", latest.Change24HPct)
 	return latest, nil
 }
@@ -107,3 +251,111 @@ func (s *priceService) GetHistory(ctx context.Context, req *dto.HistoryReq) ([]*
 	lg.Info("fetched price history", "symbol", req.Symbol, "points", len(history))
 	return history, nil
 }
+
+func (s *priceService) StreamHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.HistoryRes) error) error {
+	lg := s.logger.With("method", "StreamHistory")
+
+	if err := s.repo.StreamHistory(ctx, req, fn); err != nil {
+		lg.Error("failed to stream price history", "symbol", req.Symbol, "error", err)
+		return err
+	}
+
+	lg.Info("streamed price history", "symbol", req.Symbol)
+	return nil
+}
+
+func (s *priceService) GetMarketHistory(ctx context.Context, req *dto.HistoryReq) ([]*dto.MarketHistoryRes, error) {
+	lg := s.logger.With("method", "GetMarketHistory")
+
+	history, err := s.repo.GetMarketHistory(ctx, req)
+	if err != nil {
+		lg.Error("failed to fetch market history", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	lg.Info("fetched market history", "symbol", req.Symbol, "points", len(history))
+	return history, nil
+}
+
+func (s *priceService) StreamMarketHistory(ctx context.Context, req *dto.HistoryReq, fn func(*dto.MarketHistoryRes) error) error {
+	lg := s.logger.With("method", "StreamMarketHistory")
+
+	if err := s.repo.StreamMarketHistory(ctx, req, fn); err != nil {
+		lg.Error("failed to stream market history", "symbol", req.Symbol, "error", err)
+		return err
+	}
+
+	lg.Info("streamed market history", "symbol", req.Symbol)
+	return nil
+}
+
+// GetTicks returns one page of raw rows. The next cursor is only set when
+// more rows exist past this page.
+func (s *priceService) GetTicks(ctx context.Context, req *dto.TicksReq) (*dto.TicksRes, error) {
+	lg := s.logger.With("method", "GetTicks")
+
+	if req.Limit == 0 {
+		req.Limit = DefaultTicksLimit
+	}
+	if req.Limit < 0 || req.Limit > MaxTicksLimit {
+		return nil, fmt.Errorf("%w: limit must be between 1 and %d", ErrInvalidRequest, MaxTicksLimit)
+	}
+	if err := resolveTicksCursor(req); err != nil {
+		return nil, err
+	}
+
+	pageSize := req.Limit
+	req.Limit++ // one extra row tells us whether there is a next page
+
+	res := &dto.TicksRes{Ticks: make([]*dto.TickRes, 0, pageSize)}
+	err := s.repo.StreamTicks(ctx, req, func(p *entity.Price) error {
+		res.Ticks = append(res.Ticks, &dto.TickRes{Symbol: p.Symbol, Price: p.Price, Timestamp: p.Time})
+		return nil
+	})
+	if err != nil {
+		lg.Error("failed to fetch ticks", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	if len(res.Ticks) > pageSize {
+		res.Ticks = res.Ticks[:pageSize]
+		res.NextCursor = encodeTicksCursor(req.Order, res.Ticks[pageSize-1].Timestamp)
+	}
+
+	lg.Info("fetched ticks", "symbol", req.Symbol, "count", len(res.Ticks))
+	return res, nil
+}
+
+// StreamTicks streams every raw row in the range, starting after req.Cursor when given.
+func (s *priceService) StreamTicks(ctx context.Context, req *dto.TicksReq, fn func(*dto.TickRes) error) error {
+	lg := s.logger.With("method", "StreamTicks")
+
+	if err := resolveTicksCursor(req); err != nil {
+		return err
+	}
+	req.Limit = 0
+
+	err := s.repo.StreamTicks(ctx, req, func(p *entity.Price) error {
+		return fn(&dto.TickRes{Symbol: p.Symbol, Price: p.Price, Timestamp: p.Time})
+	})
+	if err != nil {
+		lg.Error("failed to stream ticks", "symbol", req.Symbol, "error", err)
+		return err
+	}
+
+	lg.Info("streamed ticks", "symbol", req.Symbol)
+	return nil
+}
+
+// GetVersion identifies the data of a symbol up to to. Responses built from
+// that data only change when the version does.
+func (s *priceService) GetVersion(ctx context.Context, symbol string, to int64) (*entity.DataVersion, error) {
+	lg := s.logger.With("method", "GetVersion")
+
+	version, err := s.repo.GetVersion(ctx, symbol, to)
+	if err != nil {
+		lg.Error("failed to get data version", "symbol", symbol, "error", err)
+		return nil, err
+	}
+	return version, nil
+}
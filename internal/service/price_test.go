@@ -0,0 +1,167 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"io"
+	"log/slog"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/milad-rasouli/price/entity"
+	"github.com/milad-rasouli/price/internal/infrastructure/config"
+	"github.com/milad-rasouli/price/internal/providers/currency"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+// tickProvider returns a price for every requested coin except the skipped pairs
+type tickProvider struct {
+	top     []string
+	skipped map[string]bool
+}
+
+func (p *tickProvider) Get(ctx context.Context, q *currency.Query) ([]*entity.Price, error) {
+	coins := q.Symbols
+	if len(coins) == 0 {
+		coins = p.top
+	}
+	var prices []*entity.Price
+	for _, coin := range coins {
+		symbol := entity.PairSymbol(coin, q.Quote)
+		if !p.skipped[symbol] {
+			prices = append(prices, &entity.Price{Symbol: symbol, Price: decimal.NewFromInt(1), Time: 1735689600})
+		}
+	}
+	return prices, nil
+}
+
+type catalogRecorder struct {
+	CatalogService
+	prices  []string
+	tracked []string
+}
+
+func (c *catalogRecorder) Track(ctx context.Context, prices []*entity.Price, tracked []string) error {
+	for _, p := range prices {
+		c.prices = append(c.prices, p.Symbol)
+	}
+	c.tracked = tracked
+	return nil
+}
+
+func (c *catalogRecorder) BasketNames(ctx context.Context) (map[string]bool, error) {
+	return map[string]bool{}, nil
+}
+
+// flagAll flags every tick, recording the flags it is asked to
+type flagAll struct {
+	AnomalyService
+	recorded []*entity.Anomaly
+}
+
+func (f *flagAll) Screen(ctx context.Context, prices []*entity.Price) ([]*entity.Price, []*entity.Anomaly, error) {
+	anomalies := make([]*entity.Anomaly, len(prices))
+	for i, p := range prices {
+		anomalies[i] = &entity.Anomaly{Tick: *p, Action: config.AnomalyFlag}
+	}
+	return prices, anomalies, nil
+}
+
+func (f *flagAll) Record(ctx context.Context, anomalies []*entity.Anomaly) error {
+	f.recorded = append(f.recorded, anomalies...)
+	return nil
+}
+
+type discardPrices struct {
+	price.PriceRepository
+	err error
+}
+
+func (d discardPrices) BatchInsert(ctx context.Context, p []*entity.Price) error { return d.err }
+
+type noBaskets struct{ BasketService }
+
+func (noBaskets) ValueBaskets(ctx context.Context, prices []*entity.Price) ([]*entity.Price, error) {
+	return nil, nil
+}
+
+type noAlerts struct{ AlertService }
+
+func (noAlerts) Evaluate(ctx context.Context, prices []*entity.Price) error { return nil }
+
+func TestInsertBatchTracksConfiguredPairs(t *testing.T) {
+	tests := []struct {
+		name    string
+		symbols []string
+		skipped map[string]bool
+		prices  []string // symbols of the tick
+		tracked []string // symbols kept ingesting besides those of the tick
+	}{
+		{
+			name:    "configured pair skipped for a tick",
+			symbols: []string{"btc", "eth"},
+			skipped: map[string]bool{"eth-eur": true},
+			prices:  []string{"btc", "eth", "btc-eur"},
+			tracked: []string{"btc", "eth", "btc-eur", "eth-eur"},
+		},
+		{
+			name:    "top coins",
+			skipped: map[string]bool{"eth-eur": true},
+			prices:  []string{"btc", "eth", "btc-eur"},
+			tracked: []string{},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Ingest.Symbols = tt.symbols
+			cfg.Ingest.Top = 2
+			cfg.Ingest.QuoteCurrencies = []string{"usd", "eur"}
+			cfg.Ingest.Timeout = config.Duration{Duration: time.Second}
+			catalog := &catalogRecorder{}
+			s := NewPriceService(slog.New(slog.NewTextHandler(io.Discard, nil)), config.NewStore(cfg), discardPrices{},
+				&tickProvider{top: []string{"btc", "eth"}, skipped: tt.skipped}, &flagAll{}, noBaskets{}, noAlerts{}, catalog)
+
+			if err := s.InsertBatch(context.Background()); err != nil {
+				t.Fatalf("InsertBatch() error = %v", err)
+			}
+			if !slices.Equal(catalog.prices, tt.prices) {
+				t.Errorf("tracked prices = %v, want %v", catalog.prices, tt.prices)
+			}
+			if !slices.Equal(catalog.tracked, tt.tracked) {
+				t.Errorf("kept ingesting = %v, want %v", catalog.tracked, tt.tracked)
+			}
+		})
+	}
+}
+
+func TestInsertBatchRecordsAnomaliesOnceStored(t *testing.T) {
+	tests := []struct {
+		name     string
+		insert   error
+		recorded int
+	}{
+		{"stored", nil, 2},
+		{"insert failed", errors.New("connection reset"), 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := config.Default()
+			cfg.Ingest.Symbols = []string{"btc", "eth"}
+			cfg.Ingest.QuoteCurrencies = []string{"usd"}
+			anomalies := &flagAll{}
+			s := NewPriceService(slog.New(slog.NewTextHandler(io.Discard, nil)), config.NewStore(cfg), discardPrices{err: tt.insert},
+				&tickProvider{}, anomalies, noBaskets{}, noAlerts{}, &catalogRecorder{})
+
+			err := s.InsertBatch(context.Background())
+			if (err != nil) != (tt.insert != nil) {
+				t.Fatalf("InsertBatch() error = %v, want one: %v", err, tt.insert != nil)
+			}
+			if len(anomalies.recorded) != tt.recorded {
+				t.Errorf("recorded %d anomalies, want %d", len(anomalies.recorded), tt.recorded)
+			}
+		})
+	}
+}
@@ -6,4 +6,8 @@ import (
 
 var ProviderSet = wire.NewSet(
 	NewPriceService,
+	NewBasketService,
+	NewAlertService,
+	NewAnomalyService,
+	NewCatalogService,
 )
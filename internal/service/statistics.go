@@ -0,0 +1,133 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"time"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+	"github.com/shopspring/decimal"
+)
+
+// ratioPrecision is the number of decimal places kept for returns and drawdowns
+const ratioPrecision = 18
+
+const secondsPerYear = 365 * 86400
+
+// GetStatistics summarizes the bucket closes of [req.From, req.To] as returned by GetHistory
+func (s *priceService) GetStatistics(ctx context.Context, req *dto.HistoryReq) (*dto.StatisticsRes, error) {
+	lg := s.logger.With("method", "GetStatistics")
+
+	if err := validateRange(req.From, req.To); err != nil {
+		return nil, err
+	}
+	if req.Interval == "" {
+		req.Interval = price.DefaultInterval
+	}
+	interval, err := price.ParseInterval(req.Interval)
+	if err != nil {
+		return nil, err
+	}
+
+	history, err := s.repo.GetHistory(ctx, req)
+	if err != nil {
+		lg.Error("failed to fetch price history", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+
+	res, err := statistics(history, interval)
+	if err != nil {
+		lg.Error("failed to compute statistics", "symbol", req.Symbol, "error", err)
+		return nil, err
+	}
+	res.Symbol, res.Interval = req.Symbol, req.Interval
+
+	lg.Info("computed statistics", "symbol", req.Symbol, "buckets", res.Buckets)
+	return res, nil
+}
+
+func statistics(history []*dto.HistoryRes, interval time.Duration) (*dto.StatisticsRes, error) {
+	if len(history) == 0 {
+		return nil, price.ErrPriceNotFound
+	}
+	point := func(h *dto.HistoryRes) dto.PricePoint {
+		return dto.PricePoint{Price: h.LastPrice, Timestamp: h.StartedAt}
+	}
+
+	first, last := history[0], history[len(history)-1]
+	res := &dto.StatisticsRes{
+		Buckets: len(history),
+		Open:    point(first),
+		Close:   point(last),
+		Min:     point(first),
+		Max:     point(first),
+		MaxDrawdown: dto.Drawdown{
+			Ratio:  decimal.Zero,
+			Peak:   point(first),
+			Trough: point(first),
+		},
+	}
+
+	peak := point(first)
+	logReturns := make([]float64, 0, len(history)-1)
+	for i, h := range history {
+		if h.LastPrice.LessThan(res.Min.Price) {
+			res.Min = point(h)
+		}
+		if h.LastPrice.GreaterThan(res.Max.Price) {
+			res.Max = point(h)
+		}
+
+		if h.LastPrice.GreaterThan(peak.Price) {
+			peak = point(h)
+		} else if peak.Price.IsPositive() {
+			drawdown := peak.Price.Sub(h.LastPrice).DivRound(peak.Price, ratioPrecision)
+			if drawdown.GreaterThan(res.MaxDrawdown.Ratio) {
+				res.MaxDrawdown = dto.Drawdown{Ratio: drawdown, Peak: peak, Trough: point(h)}
+			}
+		}
+
+		if i > 0 {
+			prev := history[i-1].LastPrice
+			if !prev.IsPositive() || !h.LastPrice.IsPositive() {
+				return nil, fmt.Errorf("non-positive price at %d", h.StartedAt)
+			}
+			logReturns = append(logReturns, math.Log(h.LastPrice.DivRound(prev, ratioPrecision).InexactFloat64()))
+		}
+	}
+
+	if !first.LastPrice.IsPositive() {
+		return nil, fmt.Errorf("non-positive price at %d", first.StartedAt)
+	}
+	growth := last.LastPrice.DivRound(first.LastPrice, ratioPrecision)
+	res.SimpleReturn = growth.Sub(decimal.NewFromInt(1))
+	logReturn, err := growth.Ln(ratioPrecision)
+	if err != nil {
+		return nil, err
+	}
+	res.LogReturn = logReturn
+
+	if len(logReturns) >= 2 {
+		vol := stddev(logReturns)
+		annualized := vol * math.Sqrt(float64(secondsPerYear)/interval.Seconds())
+		res.Volatility, res.AnnualizedVolatility = &vol, &annualized
+	}
+	return res, nil
+}
+
+// stddev is the sample standard deviation
+func stddev(values []float64) float64 {
+	var mean float64
+	for _, v := range values {
+		mean += v
+	}
+	mean /= float64(len(values))
+
+	var sum float64
+	for _, v := range values {
+		sum += (v - mean) * (v - mean)
+	}
+	return math.Sqrt(sum / float64(len(values)-1))
+}
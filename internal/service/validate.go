@@ -0,0 +1,58 @@
+package service
+
+import (
+	"fmt"
+
+	"github.com/milad-rasouli/price/internal/app/api/dto"
+	"github.com/milad-rasouli/price/internal/repository/repository/price"
+)
+
+// The Validate functions reject what the matching PriceService methods would,
+// so controllers can do it before answering a conditional request, which
+// never reaches the service.
+
+func ValidateLatest(req *dto.LatestReq) error {
+	_, err := parseWindows(req.Windows)
+	return err
+}
+
+func ValidateHistory(req *dto.HistoryReq) error {
+	return validateInterval(req.Interval)
+}
+
+func ValidateStatistics(req *dto.HistoryReq) error {
+	if err := validateRange(req.From, req.To); err != nil {
+		return err
+	}
+	return validateInterval(req.Interval)
+}
+
+func ValidateAverage(req *dto.AverageReq) error {
+	return validateRange(req.From, req.To)
+}
+
+func ValidateIndicators(req *dto.IndicatorsReq) error {
+	if err := validateRange(req.From, req.To); err != nil {
+		return err
+	}
+	if _, err := parseIndicators(req.Indicators); err != nil {
+		return err
+	}
+	return validateInterval(req.Interval)
+}
+
+func validateRange(from, to int64) error {
+	if from >= to {
+		return fmt.Errorf("%w: from must be before to", ErrInvalidRequest)
+	}
+	return nil
+}
+
+// validateInterval accepts an empty interval, which means price.DefaultInterval
+func validateInterval(interval string) error {
+	if interval == "" {
+		return nil
+	}
+	_, err := price.ParseInterval(interval)
+	return err
+}
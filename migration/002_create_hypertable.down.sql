@@ -0,0 +1,16 @@
+-- a hypertable can't be turned back into a plain table, so copy the rows out
+CREATE TABLE coin_prices_plain (
+    symbol VARCHAR(16) NOT NULL,
+    price NUMERIC(30,10) NOT NULL,
+    time BIGINT NOT NULL,
+    PRIMARY KEY (symbol, time)
+);
+
+INSERT INTO coin_prices_plain (symbol, price, time)
+SELECT symbol, price, time FROM coin_prices;
+
+DROP TABLE coin_prices;
+ALTER TABLE coin_prices_plain RENAME TO coin_prices;
+ALTER INDEX coin_prices_plain_pkey RENAME TO coin_prices_pkey;
+
+DROP FUNCTION IF EXISTS coin_prices_unix_now();
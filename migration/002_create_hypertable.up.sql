@@ -0,0 +1,14 @@
+-- time is stored as unix seconds, so the hypertable needs an integer "now" for policies
+CREATE OR REPLACE FUNCTION coin_prices_unix_now() RETURNS BIGINT
+    LANGUAGE SQL STABLE AS $$ SELECT EXTRACT(EPOCH FROM now())::BIGINT $$;
+
+-- one chunk per week
+SELECT create_hypertable('coin_prices', 'time', chunk_time_interval => 604800, migrate_data => true);
+SELECT set_integer_now_func('coin_prices', 'coin_prices_unix_now');
+
+-- the compression and retention policies themselves are applied at startup from configuration
+ALTER TABLE coin_prices SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'symbol',
+    timescaledb.compress_orderby = 'time DESC'
+);
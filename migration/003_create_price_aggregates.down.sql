@@ -0,0 +1,3 @@
+DROP MATERIALIZED VIEW IF EXISTS coin_prices_1d;
+DROP MATERIALIZED VIEW IF EXISTS coin_prices_1h;
+DROP MATERIALIZED VIEW IF EXISTS coin_prices_1m;
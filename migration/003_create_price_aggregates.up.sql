@@ -0,0 +1,50 @@
+-- sum and samples are kept instead of avg so coarser buckets can be re-aggregated exactly
+CREATE MATERIALIZED VIEW coin_prices_1m
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(BIGINT '60', time) AS bucket,
+       symbol,
+       SUM(price) AS sum_price,
+       COUNT(*) AS samples,
+       FIRST(price, time) AS open_price,
+       MAX(price) AS high_price,
+       MIN(price) AS low_price,
+       LAST(price, time) AS last_price
+FROM coin_prices
+GROUP BY bucket, symbol
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW coin_prices_1h
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(BIGINT '3600', time) AS bucket,
+       symbol,
+       SUM(price) AS sum_price,
+       COUNT(*) AS samples,
+       FIRST(price, time) AS open_price,
+       MAX(price) AS high_price,
+       MIN(price) AS low_price,
+       LAST(price, time) AS last_price
+FROM coin_prices
+GROUP BY bucket, symbol
+WITH NO DATA;
+
+CREATE MATERIALIZED VIEW coin_prices_1d
+WITH (timescaledb.continuous, timescaledb.materialized_only = false) AS
+SELECT time_bucket(BIGINT '86400', time) AS bucket,
+       symbol,
+       SUM(price) AS sum_price,
+       COUNT(*) AS samples,
+       FIRST(price, time) AS open_price,
+       MAX(price) AS high_price,
+       MIN(price) AS low_price,
+       LAST(price, time) AS last_price
+FROM coin_prices
+GROUP BY bucket, symbol
+WITH NO DATA;
+
+-- offsets are in seconds; raw retention must stay longer than the largest start_offset (30 days)
+SELECT add_continuous_aggregate_policy('coin_prices_1m',
+    start_offset => BIGINT '7200', end_offset => BIGINT '60', schedule_interval => INTERVAL '1 minute');
+SELECT add_continuous_aggregate_policy('coin_prices_1h',
+    start_offset => BIGINT '172800', end_offset => BIGINT '3600', schedule_interval => INTERVAL '30 minutes');
+SELECT add_continuous_aggregate_policy('coin_prices_1d',
+    start_offset => BIGINT '2592000', end_offset => BIGINT '86400', schedule_interval => INTERVAL '1 hour');
@@ -0,0 +1,57 @@
+-- the aggregates are recreated from their own definitions, as in the up migration
+CREATE TEMP TABLE price_aggregates AS
+SELECT ca.view_name, ca.view_definition, ca.materialized_only, j.config, j.schedule_interval
+FROM timescaledb_information.continuous_aggregates ca
+LEFT JOIN timescaledb_information.jobs j
+    ON j.hypertable_name = ca.materialization_hypertable_name
+    AND j.proc_name = 'policy_refresh_continuous_aggregate'
+WHERE ca.hypertable_name = 'coin_prices';
+
+DO $$
+DECLARE
+    agg RECORD;
+BEGIN
+    FOR agg IN SELECT view_name FROM price_aggregates LOOP
+        EXECUTE format('DROP MATERIALIZED VIEW %I', agg.view_name);
+    END LOOP;
+END
+$$;
+
+-- the type of a column can't change while compression is enabled;
+-- the compression policy is added back at startup
+SELECT remove_compression_policy('coin_prices', if_exists => true);
+SELECT decompress_chunk(c, if_compressed => true) FROM show_chunks('coin_prices') c;
+ALTER TABLE coin_prices SET (timescaledb.compress = false);
+
+-- prices with more than 10 decimal places are rounded
+ALTER TABLE coin_prices ALTER COLUMN price TYPE NUMERIC(30,10);
+
+ALTER TABLE coin_prices SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'symbol',
+    timescaledb.compress_orderby = 'time DESC'
+);
+
+DO $$
+DECLARE
+    agg RECORD;
+BEGIN
+    FOR agg IN SELECT * FROM price_aggregates LOOP
+        EXECUTE format(
+            'CREATE MATERIALIZED VIEW %I WITH (timescaledb.continuous, timescaledb.materialized_only = %L) AS %s WITH NO DATA',
+            agg.view_name, agg.materialized_only, rtrim(agg.view_definition, E'; \n'));
+        IF agg.config IS NOT NULL THEN
+            PERFORM add_continuous_aggregate_policy(agg.view_name::regclass,
+                start_offset => (agg.config->>'start_offset')::BIGINT,
+                end_offset => (agg.config->>'end_offset')::BIGINT,
+                schedule_interval => agg.schedule_interval);
+        END IF;
+    END LOOP;
+END
+$$;
+
+DROP TABLE price_aggregates;
+
+-- buckets that only the archive holds are lost; the aggregates are rebuilt
+-- from the raw rows that are left
+DROP TABLE IF EXISTS coin_prices_archive;
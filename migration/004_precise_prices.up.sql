@@ -0,0 +1,84 @@
+-- NUMERIC(30,10) rounds prices below 1e-10; an unconstrained NUMERIC stores
+-- every digit the provider sends. The aggregates depend on the column, so they
+-- are dropped and recreated from their definitions in 003, then rebuilt from
+-- the raw rows. Buckets from before the raw rows the retention policy left
+-- can't be rebuilt and are kept in coin_prices_archive instead.
+CREATE TABLE coin_prices_archive (
+    aggregate TEXT NOT NULL, -- the continuous aggregate the bucket was copied from
+    bucket BIGINT NOT NULL,
+    symbol VARCHAR(16) NOT NULL,
+    sum_price NUMERIC NOT NULL,
+    samples BIGINT NOT NULL,
+    open_price NUMERIC NOT NULL,
+    high_price NUMERIC NOT NULL,
+    low_price NUMERIC NOT NULL,
+    last_price NUMERIC NOT NULL,
+    PRIMARY KEY (aggregate, symbol, bucket)
+);
+
+CREATE TEMP TABLE price_aggregates AS
+SELECT ca.view_name, ca.view_definition, ca.materialized_only, j.config, j.schedule_interval
+FROM timescaledb_information.continuous_aggregates ca
+LEFT JOIN timescaledb_information.jobs j
+    ON j.hypertable_name = ca.materialization_hypertable_name
+    AND j.proc_name = 'policy_refresh_continuous_aggregate'
+WHERE ca.hypertable_name = 'coin_prices';
+
+-- a bucket is archived whole when any raw row of it is gone, and the archived
+-- copy wins over the partial one rebuilt from the rows that are left
+DO $$
+DECLARE
+    agg RECORD;
+BEGIN
+    CREATE TEMP TABLE first_prices AS
+    SELECT symbol, MIN(time) AS first_time FROM coin_prices GROUP BY symbol;
+
+    FOR agg IN SELECT view_name FROM price_aggregates LOOP
+        EXECUTE format(
+            'INSERT INTO coin_prices_archive
+             SELECT %L, a.bucket, a.symbol, a.sum_price, a.samples, a.open_price, a.high_price, a.low_price, a.last_price
+             FROM %I a
+             LEFT JOIN first_prices f ON f.symbol = a.symbol
+             WHERE f.first_time IS NULL OR a.bucket < f.first_time',
+            agg.view_name, agg.view_name);
+        EXECUTE format('DROP MATERIALIZED VIEW %I', agg.view_name);
+    END LOOP;
+
+    DROP TABLE first_prices;
+END
+$$;
+
+-- the type of a column can't change while compression is enabled;
+-- the compression policy is added back at startup
+SELECT remove_compression_policy('coin_prices', if_exists => true);
+SELECT decompress_chunk(c, if_compressed => true) FROM show_chunks('coin_prices') c;
+ALTER TABLE coin_prices SET (timescaledb.compress = false);
+
+ALTER TABLE coin_prices ALTER COLUMN price TYPE NUMERIC;
+
+ALTER TABLE coin_prices SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'symbol',
+    timescaledb.compress_orderby = 'time DESC'
+);
+
+-- the migrator refreshes the recreated aggregates once all migrations ran
+DO $$
+DECLARE
+    agg RECORD;
+BEGIN
+    FOR agg IN SELECT * FROM price_aggregates LOOP
+        EXECUTE format(
+            'CREATE MATERIALIZED VIEW %I WITH (timescaledb.continuous, timescaledb.materialized_only = %L) AS %s WITH NO DATA',
+            agg.view_name, agg.materialized_only, rtrim(agg.view_definition, E'; \n'));
+        IF agg.config IS NOT NULL THEN
+            PERFORM add_continuous_aggregate_policy(agg.view_name::regclass,
+                start_offset => (agg.config->>'start_offset')::BIGINT,
+                end_offset => (agg.config->>'end_offset')::BIGINT,
+                schedule_interval => agg.schedule_interval);
+        END IF;
+    END LOOP;
+END
+$$;
+
+DROP TABLE price_aggregates;
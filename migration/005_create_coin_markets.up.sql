@@ -0,0 +1,21 @@
+-- market data reported with each price, same symbols and times as coin_prices
+CREATE TABLE coin_markets (
+    symbol VARCHAR(16) NOT NULL,
+    time BIGINT NOT NULL,
+    market_cap NUMERIC,
+    total_volume NUMERIC,
+    circulating_supply NUMERIC,
+    high_24h NUMERIC,
+    low_24h NUMERIC,
+    PRIMARY KEY (symbol, time)
+);
+
+SELECT create_hypertable('coin_markets', 'time', chunk_time_interval => 604800);
+SELECT set_integer_now_func('coin_markets', 'coin_prices_unix_now');
+
+-- compression and retention follow the coin_prices settings, applied at startup
+ALTER TABLE coin_markets SET (
+    timescaledb.compress,
+    timescaledb.compress_segmentby = 'symbol',
+    timescaledb.compress_orderby = 'time DESC'
+);
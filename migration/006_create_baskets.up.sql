@@ -0,0 +1,15 @@
+-- weighted baskets, valued on every ingestion tick and stored in coin_prices under their name
+CREATE TABLE baskets (
+    name VARCHAR(16) PRIMARY KEY,
+    base_value NUMERIC NOT NULL,
+    created_at BIGINT NOT NULL,
+    updated_at BIGINT NOT NULL
+);
+
+CREATE TABLE basket_components (
+    basket VARCHAR(16) NOT NULL REFERENCES baskets (name) ON DELETE CASCADE,
+    symbol VARCHAR(16) NOT NULL,
+    weight NUMERIC NOT NULL,
+    units NUMERIC NOT NULL,
+    PRIMARY KEY (basket, symbol)
+);
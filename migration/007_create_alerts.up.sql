@@ -0,0 +1,29 @@
+-- price alert rules, evaluated after every ingested batch
+CREATE TABLE alert_rules (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(16) NOT NULL,
+    kind TEXT NOT NULL CHECK (kind IN ('crosses_above', 'crosses_below', 'change')),
+    threshold NUMERIC NOT NULL,
+    window_seconds BIGINT NOT NULL DEFAULT 0,
+    webhook_url TEXT NOT NULL,
+    active BOOLEAN, -- whether the condition held at the last evaluation, NULL before the first
+    created_at BIGINT NOT NULL,
+    last_triggered_at BIGINT
+);
+
+CREATE INDEX alert_rules_symbol_idx ON alert_rules (symbol);
+
+-- every webhook delivery attempt
+CREATE TABLE alert_deliveries (
+    id BIGSERIAL PRIMARY KEY,
+    rule_id BIGINT NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+    event_id TEXT NOT NULL,
+    attempt INT NOT NULL,
+    status_code INT,
+    error TEXT NOT NULL DEFAULT '',
+    delivered BOOLEAN NOT NULL,
+    payload TEXT NOT NULL,
+    time BIGINT NOT NULL
+);
+
+CREATE INDEX alert_deliveries_rule_idx ON alert_deliveries (rule_id, id DESC);
@@ -0,0 +1,18 @@
+-- ingested ticks that deviated too far from the recent prices of their symbol
+CREATE TABLE price_anomalies (
+    id BIGSERIAL PRIMARY KEY,
+    symbol VARCHAR(16) NOT NULL,
+    price NUMERIC NOT NULL,
+    time BIGINT NOT NULL,
+    market JSONB, -- the market data of the tick, stored with it when a quarantined tick is approved
+    action TEXT NOT NULL CHECK (action IN ('flag', 'quarantine')),
+    reference NUMERIC NOT NULL, -- the last stored price the tick was compared with
+    change_pct NUMERIC NOT NULL,
+    z_score DOUBLE PRECISION, -- NULL when the history was too short
+    status TEXT NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'approved', 'rejected')),
+    detected_at BIGINT NOT NULL,
+    reviewed_at BIGINT,
+    UNIQUE (symbol, time)
+);
+
+CREATE INDEX price_anomalies_status_idx ON price_anomalies (status, id DESC);
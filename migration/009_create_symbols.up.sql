@@ -0,0 +1,13 @@
+-- the catalog of stored symbols. Ingestion keeps names, provider ids and the
+-- ingesting flag current; every write to coin_prices updates row_count.
+CREATE TABLE symbols (
+    symbol VARCHAR(16) PRIMARY KEY,
+    name TEXT NOT NULL DEFAULT '',
+    provider_ids JSONB NOT NULL DEFAULT '{}', -- provider -> the coin's id there
+    row_count BIGINT NOT NULL DEFAULT 0,
+    ingesting BOOLEAN NOT NULL DEFAULT false, -- part of the latest ingestion tick
+    last_ingested_at BIGINT
+);
+
+INSERT INTO symbols (symbol, row_count)
+SELECT symbol, COUNT(*) FROM coin_prices GROUP BY symbol;
@@ -0,0 +1,4 @@
+ALTER TABLE symbols
+    DROP COLUMN IF EXISTS revision,
+    DROP COLUMN IF EXISTS revised_at,
+    DROP COLUMN IF EXISTS last_time;
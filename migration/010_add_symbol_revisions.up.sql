@@ -0,0 +1,10 @@
+-- revision counts the writes that changed data which may already have been
+-- served: backfills at or below the newest stored tick and removals. Appending
+-- newer ticks only moves last_time, the newest time ever stored.
+ALTER TABLE symbols
+    ADD COLUMN revision BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN revised_at BIGINT NOT NULL DEFAULT 0,
+    ADD COLUMN last_time BIGINT;
+
+UPDATE symbols s
+SET last_time = (SELECT MAX(time) FROM coin_prices p WHERE p.symbol = s.symbol);
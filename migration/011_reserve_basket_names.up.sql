@@ -0,0 +1,7 @@
+-- basket names stay reserved after the basket is deleted, since its stored
+-- series would otherwise continue under a new basket or a coin
+ALTER TABLE symbols ADD COLUMN basket BOOLEAN NOT NULL DEFAULT false;
+
+INSERT INTO symbols (symbol, basket)
+SELECT name, true FROM baskets
+ON CONFLICT (symbol) DO UPDATE SET basket = true;
@@ -0,0 +1,13 @@
+-- fired events waiting for delivery, written in the transaction that records
+-- the rule firing so a restart can't lose them. Rows are removed once the
+-- webhook accepted the event or the attempts ran out.
+CREATE TABLE alert_outbox (
+    event_id TEXT PRIMARY KEY,
+    rule_id BIGINT NOT NULL REFERENCES alert_rules (id) ON DELETE CASCADE,
+    payload TEXT NOT NULL,
+    attempts INT NOT NULL DEFAULT 0,
+    next_attempt_at BIGINT NOT NULL, -- also pushed out while a worker holds the event
+    created_at BIGINT NOT NULL
+);
+
+CREATE INDEX alert_outbox_due_idx ON alert_outbox (next_attempt_at);
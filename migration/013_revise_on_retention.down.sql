@@ -0,0 +1,2 @@
+SELECT delete_job(job_id) FROM timescaledb_information.jobs WHERE proc_name = 'drop_raw_prices';
+DROP PROCEDURE IF EXISTS drop_raw_prices(INT, JSONB);
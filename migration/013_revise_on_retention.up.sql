@@ -0,0 +1,27 @@
+-- the retention of coin_prices runs through this procedure instead of a plain
+-- retention policy, so the revision moves on for responses that covered the
+-- rows it drops. config is {"drop_after": seconds}.
+CREATE OR REPLACE PROCEDURE drop_raw_prices(job_id INT, config JSONB)
+LANGUAGE plpgsql AS $$
+DECLARE
+    oldest BIGINT;
+    boundary BIGINT;
+BEGIN
+    -- drop_chunks only drops whole chunks, together they span [oldest, boundary)
+    SELECT min(range_start_integer), max(range_end_integer) INTO oldest, boundary
+    FROM timescaledb_information.chunks
+    WHERE hypertable_schema = current_schema() AND hypertable_name = 'coin_prices'
+      AND range_end_integer <= coin_prices_unix_now() - (config->>'drop_after')::BIGINT;
+    IF boundary IS NULL THEN
+        RETURN;
+    END IF;
+
+    -- a symbol whose newest tick is older than the span has nothing left in it
+    UPDATE symbols
+    SET revision = revision + 1,
+        revised_at = EXTRACT(EPOCH FROM now())::BIGINT
+    WHERE last_time >= oldest;
+
+    PERFORM drop_chunks('coin_prices', older_than => boundary);
+END
+$$;